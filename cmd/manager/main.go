@@ -119,6 +119,11 @@ func main() {
 		log.Error(err, "unable to run the manager")
 		os.Exit(1)
 	}
+	// Graceful shutdown.
+	log.Info("Shutting down.")
+	if !controller.Shutdown() {
+		log.Info("shutdown timeout expired; reconciles still in progress.")
+	}
 }
 
 //
@@ -59,6 +59,9 @@ type StoragePair struct {
 type DestinationStorage struct {
 	// A storage class.
 	StorageClass string `json:"storageClass"`
+	// Additional storage classes. The disks are assigned to
+	// the storage class and additional classes round-robin.
+	StorageClasses []string `json:"storageClasses,omitempty"`
 	// Volume mode.
 	// +kubebuilder:validation:Enum=Filesystem;Block
 	VolumeMode core.PersistentVolumeMode `json:"volumeMode,omitempty"`
@@ -67,6 +70,35 @@ type DestinationStorage struct {
 	AccessMode core.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
 }
 
+//
+// The (distinct) storage classes in assignment order.
+func (r *DestinationStorage) Classes() (list []string) {
+	set := map[string]bool{}
+	for _, name := range append([]string{r.StorageClass}, r.StorageClasses...) {
+		if name == "" || set[name] {
+			continue
+		}
+		set[name] = true
+		list = append(list, name)
+	}
+
+	return
+}
+
+//
+// The destination of the Nth disk (in assignment order).
+// The storage classes are assigned round-robin.
+func (r *DestinationStorage) Assign(n int) (destination DestinationStorage) {
+	destination = *r
+	destination.StorageClasses = nil
+	classes := r.Classes()
+	if len(classes) > 0 {
+		destination.StorageClass = classes[n%len(classes)]
+	}
+
+	return
+}
+
 //
 // Network map spec.
 type NetworkMapSpec struct {
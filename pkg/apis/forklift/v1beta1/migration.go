@@ -31,6 +31,8 @@ type MigrationSpec struct {
 	Plan core.ObjectReference `json:"plan" ref:"Plan"`
 	// List of VMs which will have their imports canceled.
 	Cancel []ref.Ref `json:"cancel,omitempty"`
+	// Cancel all VMs. Equivalent to listing every VM in `cancel`.
+	CancelAll bool `json:"cancelAll,omitempty"`
 	// Date and time to finalize a warm migration.
 	// If present, this will override the value set on the Plan.
 	Cutover *meta.Time `json:"cutover,omitempty"`
@@ -39,7 +41,12 @@ type MigrationSpec struct {
 //
 // Canceled indicates whether a VM ref is present
 // in the list of VM refs to be canceled.
+// All VMs are canceled when CancelAll is set.
 func (r *MigrationSpec) Canceled(ref ref.Ref) (found bool) {
+	if r.CancelAll {
+		found = true
+		return
+	}
 	if ref.ID == "" {
 		return
 	}
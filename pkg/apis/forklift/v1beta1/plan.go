@@ -23,6 +23,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sort"
 )
 
 //
@@ -36,12 +37,189 @@ type PlanSpec struct {
 	Provider provider.Pair `json:"provider"`
 	// Resource mapping.
 	Map plan.Map `json:"map"`
+	// Additional source providers. The VMs of each
+	// provider are mapped using the provider maps.
+	Sources []plan.Source `json:"sources,omitempty"`
 	// List of VMs.
 	VMs []plan.VM `json:"vms"`
+	// Hooks applied to the VMs matched by the selector.
+	// Hooks listed on the VM take precedence.
+	Hooks []plan.HookSelector `json:"hooks,omitempty"`
 	// Whether this is a warm migration.
 	Warm bool `json:"warm,omitempty"`
 	// The network attachment definition that should be used for disk transfer.
 	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
+	// A secret containing a (PEM) CA bundle (ca.crt) trusted when
+	// transferring disks from the source. Added to the provider CA
+	// certificate. Not used for vSphere; the host certificate
+	// thumbprint is pinned.
+	TransferCA *core.ObjectReference `json:"transferCA,omitempty"`
+	// Skip shared (and RDM) disks which cannot be migrated.
+	// When not set, VMs with shared disks cannot be migrated.
+	SkipSharedDisks bool `json:"skipSharedDisks,omitempty"`
+	// Migrate shared disks once. A shared disk is transferred by
+	// the first VM in the plan which shares it (the owner) and used
+	// by the target VMs of the other VMs (dependents) in the same
+	// target namespace. Dependents complete after the owner has
+	// succeeded and fail when the owner fails. RDM disks cannot
+	// be migrated.
+	MigrateSharedDisks bool `json:"migrateSharedDisks,omitempty"`
+	// Migrate VMs with a source host or datastore in maintenance mode.
+	// When not set, disk transfer is blocked until maintenance completes.
+	AllowMaintenanceMode bool `json:"allowMaintenanceMode,omitempty"`
+	// Default volume mode for DataVolumes.
+	// Used when not specified by the storage mapping.
+	// +kubebuilder:validation:Enum=Filesystem;Block
+	VolumeMode core.PersistentVolumeMode `json:"volumeMode,omitempty"`
+	// Default access mode for DataVolumes.
+	// Used when not specified by the storage mapping.
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadWriteMany;ReadOnlyMany
+	AccessMode core.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+	// Format of the target disks.
+	// Used when not specified by the disk override.
+	// When not set, the importer default (raw).
+	// +kubebuilder:validation:Enum=raw;qcow2
+	TargetDiskFormat string `json:"targetDiskFormat,omitempty"`
+	// Labels applied to the target DataVolumes and PVCs.
+	TargetPVCLabels map[string]string `json:"targetPVCLabels,omitempty"`
+	// Annotations applied to the target DataVolumes and PVCs.
+	TargetPVCAnnotations map[string]string `json:"targetPVCAnnotations,omitempty"`
+	// Node selector applied to the target VMs.
+	TargetNodeSelector map[string]string `json:"targetNodeSelector,omitempty"`
+	// Notified when the plan execution has completed.
+	CompletionWebhook *Webhook `json:"completionWebhook,omitempty"`
+	// Pause the plan execution.
+	// VMs are not advanced (or started) while paused
+	// and resume where they left off when unpaused.
+	Paused bool `json:"paused,omitempty"`
+	// Recurring time windows during which VMs are migrated.
+	// Outside the windows, VMs are not started. Always
+	// open when not specified.
+	AllowedWindows []plan.Window `json:"allowedWindows,omitempty"`
+	// Pause (running) VM migrations outside the allowed windows.
+	// VMs resume where they left off when a window opens. Warm
+	// precopies are not paused (not supported by the importer).
+	PauseOutsideWindow bool `json:"pauseOutsideWindow,omitempty"`
+	// Disk transfer bandwidth limit (MB/s) for each VM.
+	// Zero (or not set) is unlimited.
+	// +kubebuilder:validation:Minimum=0
+	BandwidthLimit int `json:"bandwidthLimit,omitempty"`
+	// Number of disks transferred concurrently for each VM.
+	// Zero (or not set) is the importer default.
+	// +kubebuilder:validation:Minimum=0
+	DisksPerVM int `json:"disksPerVM,omitempty"`
+	// Power-on policy applied to the target VM when the
+	// migration has succeeded. When not set, the VM is
+	// started as determined by the importer.
+	// +kubebuilder:validation:Enum=Always;Never;MatchSource
+	PowerOnAfterMigration string `json:"powerOnAfterMigration,omitempty"`
+	// Plan completion policy. AllOrNothing (default): the plan
+	// fails when any VM fails. BestEffort: the plan succeeds
+	// when at least one VM succeeded and the failed VMs are
+	// reported on the Succeeded condition.
+	// +kubebuilder:validation:Enum=AllOrNothing;BestEffort
+	CompletionPolicy string `json:"completionPolicy,omitempty"`
+	// Warm migration: cutover is forced after the number of
+	// successful precopies. Zero (or not set) is unbounded.
+	// +kubebuilder:validation:Minimum=0
+	MaxPrecopies int `json:"maxPrecopies,omitempty"`
+	// Warm migration: the VM fails after the number of consecutive
+	// precopy failures. Zero (or not set) is unbounded.
+	// +kubebuilder:validation:Minimum=0
+	MaxConsecutiveFailures int `json:"maxConsecutiveFailures,omitempty"`
+	// Warm migration: a VM is reported ready for cutover when the
+	// last precopy transferred no more than the threshold (MB).
+	// Zero (or not set) is not evaluated.
+	// +kubebuilder:validation:Minimum=0
+	CutoverReadyThreshold int `json:"cutoverReadyThreshold,omitempty"`
+	// Warm migration: the number of successful precopies required
+	// before a VM is reported ready for cutover. When the threshold
+	// is not set, a VM is ready after the precopies.
+	// Zero (or not set) is not evaluated.
+	// +kubebuilder:validation:Minimum=0
+	CutoverReadyPrecopies int `json:"cutoverReadyPrecopies,omitempty"`
+	// Warm migration: cutover is triggered when the source
+	// VM is powered off (out-of-band) during the precopies.
+	CutoverOnSourceShutdown bool `json:"cutoverOnSourceShutdown,omitempty"`
+	// Skip the preflight check of the target storage capacity.
+	// Intended for thin-provisioned target storage.
+	SkipCapacityCheck bool `json:"skipCapacityCheck,omitempty"`
+	// Preserve the source VM CPU topology (sockets and cores)
+	// on the target VM. Source CPU (or NUMA node) pinning is
+	// carried over as dedicated CPU placement which requires
+	// nodes with the CPU manager enabled.
+	PreserveCPUTopology bool `json:"preserveCPUTopology,omitempty"`
+	// Migrate source VM templates. The target VM is created
+	// as a golden image and is never powered on. When not set,
+	// the migration of templates is blocked.
+	MigrateTemplates bool `json:"migrateTemplates,omitempty"`
+	// Skip the (preflight) check that the source networks and
+	// storage used by each VM are mapped.
+	SkipMappingValidation bool `json:"skipMappingValidation,omitempty"`
+	// Quiesce the guest file systems (VMware Tools) before the
+	// disks are transferred. Cold migration of vSphere VMs only.
+	// The migration continues with a warning when the guest
+	// cannot be quiesced.
+	Quiesce bool `json:"quiesce,omitempty"`
+	// Force the cleanup of canceled (and failed) VM imports.
+	// Import resources not deleted within a grace period have
+	// their finalizers removed and are force deleted.
+	ForceCleanup bool `json:"forceCleanup,omitempty"`
+	// Migrate VMs which have not been validated (policy) against
+	// the current inventory revision. When not set, the import is
+	// blocked until the VM has been validated.
+	AllowUnvalidated bool `json:"allowUnvalidated,omitempty"`
+	// Continue the migration of VMs deleted from the source provider
+	// after the import has been created. The disk transfer may still
+	// complete. When not set, the VM migration fails.
+	ContinueSourceDeleted bool `json:"continueSourceDeleted,omitempty"`
+	// Delete the import CR and DataVolumes not used by the
+	// target VM after the VM migration has succeeded.
+	CleanupAfterSuccess bool `json:"cleanupAfterSuccess,omitempty"`
+	// Importer pod CPU/memory requests and limits.
+	// When not set, the importer defaults are used.
+	ImporterResources *core.ResourceRequirements `json:"importerResources,omitempty"`
+}
+
+//
+// Transfer CA secret key.
+const TransferCAKey = "ca.crt"
+
+//
+// Power-on policies.
+const (
+	// Always power on the target VM.
+	PowerOnAlways = "Always"
+	// Never power on the target VM.
+	PowerOnNever = "Never"
+	// Power on the target VM when the source VM
+	// was powered on when the migration started.
+	PowerOnMatchSource = "MatchSource"
+)
+
+//
+// Completion policies.
+const (
+	// The plan fails when any VM fails.
+	CompletionAllOrNothing = "AllOrNothing"
+	// The plan succeeds when at least one VM succeeded.
+	CompletionBestEffort = "BestEffort"
+)
+
+//
+// Disk formats.
+const (
+	DiskFormatRaw   = "raw"
+	DiskFormatQcow2 = "qcow2"
+)
+
+//
+// Webhook.
+type Webhook struct {
+	// The URL (POST).
+	URL string `json:"url"`
+	// HTTP headers.
+	Headers map[string]string `json:"headers,omitempty"`
 }
 
 //
@@ -58,6 +236,101 @@ func (r *PlanSpec) FindVM(ref ref.Ref) (v *plan.VM, found bool) {
 	return
 }
 
+//
+// The hooks for a VM.
+// Hooks listed on the VM and the plan hooks (by step) which
+// select the VM. A hook listed on the VM takes precedence.
+func (r *PlanSpec) VMHooks(vm *plan.VM) (hooks []plan.HookRef) {
+	steps := map[string]bool{}
+	for _, ref := range vm.Hooks {
+		steps[ref.Step] = true
+		hooks = append(hooks, ref)
+	}
+	for i := range r.Hooks {
+		selector := &r.Hooks[i]
+		if steps[selector.Step] || !selector.Match(vm) {
+			continue
+		}
+		steps[selector.Step] = true
+		hooks = append(hooks, selector.HookRef)
+	}
+
+	return
+}
+
+//
+// The target format of a disk.
+// The disk override format takes precedence.
+func (r *PlanSpec) DiskFormat(disk *plan.DiskMap) string {
+	if disk.Format != "" {
+		return disk.Format
+	}
+
+	return r.TargetDiskFormat
+}
+
+//
+// Whether the VM migration is warm.
+// The VM migration type overrides the plan migration type.
+func (r *PlanSpec) VMWarm(vm *plan.VM) bool {
+	if vm.WarmMigration != nil {
+		return *vm.WarmMigration
+	}
+
+	return r.Warm
+}
+
+//
+// The target node selector for a VM.
+// The VM node selector overrides the plan node selector.
+func (r *PlanSpec) VMNodeSelector(vm *plan.VM) map[string]string {
+	if len(vm.TargetNodeSelector) > 0 {
+		return vm.TargetNodeSelector
+	}
+
+	return r.TargetNodeSelector
+}
+
+//
+// The source provider for a VM.
+// Defaults to the plan source provider.
+func (r *PlanSpec) VMProvider(vm *plan.VM) core.ObjectReference {
+	if vm.Provider != nil {
+		return *vm.Provider
+	}
+
+	return r.Provider.Source
+}
+
+//
+// The target namespace for a VM.
+// The VM target namespace overrides the plan target namespace.
+func (r *PlanSpec) VMNamespace(vm *plan.VM) string {
+	if vm.TargetNamespace != "" {
+		return vm.TargetNamespace
+	}
+
+	return r.TargetNamespace
+}
+
+//
+// The (distinct) target namespaces.
+func (r *PlanSpec) Namespaces() (list []string) {
+	set := map[string]bool{}
+	for i := range r.VMs {
+		set[r.VMNamespace(&r.VMs[i])] = true
+	}
+	if len(set) == 0 {
+		set[r.TargetNamespace] = true
+	}
+	for ns := range set {
+		list = append(list, ns)
+	}
+	sort.Strings(list)
+
+	return
+}
+
 //
 // PlanStatus defines the observed state of Plan.
 type PlanStatus struct {
@@ -10,3 +10,12 @@ type Map struct {
 	// Storage.
 	Storage core.ObjectReference `json:"storage" ref:"StorageMap"`
 }
+
+//
+// Additional source provider.
+type Source struct {
+	// Provider.
+	Provider core.ObjectReference `json:"provider" ref:"Provider"`
+	// Resource mapping for the VMs of the provider.
+	Map Map `json:"map"`
+}
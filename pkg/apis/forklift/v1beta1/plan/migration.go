@@ -93,6 +93,8 @@ type Step struct {
 	Task `json:",inline"`
 	// Nested tasks.
 	Tasks []*Task `json:"tasks,omitempty"`
+	// The step error has been ignored (failure policy).
+	Ignored bool `json:"ignored,omitempty"`
 }
 
 //
@@ -150,6 +152,8 @@ type Task struct {
 	Phase string `json:"phase,omitempty"`
 	// Reason
 	Reason string `json:"reason,omitempty"`
+	// Message
+	Message string `json:"message,omitempty"`
 	// Progress.
 	Progress libitr.Progress `json:"progress"`
 	// Annotations.
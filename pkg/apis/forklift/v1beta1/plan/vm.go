@@ -6,18 +6,34 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"path"
+	"sort"
 )
 
 //
 // Plan hook.
 type HookRef struct {
-	// Pipeline step.
+	// Pipeline step (PreHook|PreImportHook|PostImportHook|PostHook).
 	Step string `json:"step"`
 	// Hook reference.
 	Hook core.ObjectReference `json:"hook" ref:"Hook"`
+	// Hook failure policy (Abort|Ignore). Abort (default): the
+	// VM migration fails. Ignore: the failure is reported as a
+	// warning and the migration continues.
+	// +kubebuilder:validation:Enum=Abort;Ignore
+	FailurePolicy string `json:"failurePolicy,omitempty"`
 }
 
+//
+// Hook failure policies.
+const (
+	// The VM migration fails.
+	HookAbort = "Abort"
+	// The failure is reported as a warning.
+	HookIgnore = "Ignore"
+)
+
 func (r *HookRef) String() string {
 	return fmt.Sprintf(
 		"%s @%s",
@@ -25,12 +41,183 @@ func (r *HookRef) String() string {
 		r.Step)
 }
 
+//
+// Plan hook applied to the VMs matched by the selector.
+type HookSelector struct {
+	HookRef `json:",inline"`
+	// Selects VMs by target labels.
+	Selector meta.LabelSelector `json:"selector"`
+}
+
+//
+// The VM is matched by the selector.
+// Matched against the VM target labels.
+func (r *HookSelector) Match(vm *VM) (matched bool) {
+	selector, err := meta.LabelSelectorAsSelector(&r.Selector)
+	if err != nil {
+		return
+	}
+	matched = selector.Matches(labels.Set(vm.TargetLabels))
+	return
+}
+
 //
 // A VM listed on the plan.
 type VM struct {
 	ref.Ref `json:",inline"`
+	// Source provider. Either the plan source provider
+	// or an additional source provider listed on the plan.
+	// Defaults to the plan source provider.
+	Provider *core.ObjectReference `json:"provider,omitempty"`
 	// Enable hooks.
 	Hooks []HookRef `json:"hooks,omitempty"`
+	// Target namespace. Overrides the plan target namespace.
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+	// Target VM name. Defaults to the source VM name.
+	TargetName string `json:"targetName,omitempty"`
+	// Labels applied to the target VM and DataVolumes.
+	TargetLabels map[string]string `json:"targetLabels,omitempty"`
+	// Annotations applied to the target VM and DataVolumes.
+	TargetAnnotations map[string]string `json:"targetAnnotations,omitempty"`
+	// Node selector applied to the target VM.
+	// Overrides the plan target node selector.
+	TargetNodeSelector map[string]string `json:"targetNodeSelector,omitempty"`
+	// Disk storage overrides.
+	// Overrides the plan storage mapping.
+	Disks []DiskMap `json:"disks,omitempty"`
+	// Network overrides.
+	// Overrides the plan network mapping.
+	Networks []NetworkMap `json:"networks,omitempty"`
+	// Disks excluded from the migration.
+	// The disk identifier as reported on the DiskTransfer task.
+	ExcludedDisks []string `json:"excludedDisks,omitempty"`
+	// Skip the image conversion (vSphere).
+	// Intended for VMs already compatible with KubeVirt.
+	// The image is converted by default.
+	SkipConversion bool `json:"skipConversion,omitempty"`
+	// Whether this is a warm migration.
+	// Overrides the plan (warm) migration type.
+	WarmMigration *bool `json:"warmMigration,omitempty"`
+	// Scheduling priority. Higher priority VMs are migrated
+	// first. Defaults to DefaultPriority.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Priority *int `json:"priority,omitempty"`
+}
+
+//
+// Scheduling priority.
+const (
+	DefaultPriority = 50
+)
+
+//
+// The scheduling priority.
+func (r *VM) SchedulingPriority() int {
+	if r.Priority != nil {
+		return *r.Priority
+	}
+
+	return DefaultPriority
+}
+
+//
+// The VMs ordered by scheduling priority (highest first).
+// The list order is preserved for VMs with the same priority.
+func Prioritized(vms []*VMStatus) (list []*VMStatus) {
+	list = append([]*VMStatus{}, vms...)
+	sort.SliceStable(
+		list,
+		func(i, j int) bool {
+			return list[i].SchedulingPriority() > list[j].SchedulingPriority()
+		})
+
+	return
+}
+
+//
+// Disk storage override.
+type DiskMap struct {
+	// The disk identifier as reported on the DiskTransfer task.
+	Disk string `json:"disk"`
+	// A storage class.
+	StorageClass string `json:"storageClass"`
+	// Volume mode.
+	// +kubebuilder:validation:Enum=Filesystem;Block
+	VolumeMode core.PersistentVolumeMode `json:"volumeMode,omitempty"`
+	// Access mode.
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadWriteMany;ReadOnlyMany
+	AccessMode core.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+	// Disk format.
+	// +kubebuilder:validation:Enum=raw;qcow2
+	Format string `json:"format,omitempty"`
+}
+
+//
+// Network override.
+// NICs are mapped by the source network.
+type NetworkMap struct {
+	// The source network.
+	Source ref.Ref `json:"source"`
+	// The network type.
+	// +kubebuilder:validation:Enum=pod;multus
+	Type string `json:"type"`
+	// The namespace (multus only).
+	Namespace string `json:"namespace,omitempty"`
+	// The name.
+	Name string `json:"name,omitempty"`
+}
+
+//
+// The target VM name.
+// Defaults to the source VM name.
+func (r *VM) TargetVMName() string {
+	if r.TargetName != "" {
+		return r.TargetName
+	}
+
+	return r.Name
+}
+
+//
+// Find a disk override.
+func (r *VM) FindDisk(disk string) (m *DiskMap, found bool) {
+	for i := range r.Disks {
+		if r.Disks[i].Disk == disk {
+			m = &r.Disks[i]
+			found = true
+			break
+		}
+	}
+
+	return
+}
+
+//
+// The disk has been excluded.
+func (r *VM) Excluded(disk string) (excluded bool) {
+	for _, id := range r.ExcludedDisks {
+		if id == disk {
+			excluded = true
+			break
+		}
+	}
+
+	return
+}
+
+//
+// Find a network override.
+func (r *VM) FindNetwork(id string) (m *NetworkMap, found bool) {
+	for i := range r.Networks {
+		if r.Networks[i].Source.ID == id {
+			m = &r.Networks[i]
+			found = true
+			break
+		}
+	}
+
+	return
 }
 
 //
@@ -56,15 +243,127 @@ type VMStatus struct {
 	Pipeline []*Step `json:"pipeline"`
 	// Phase
 	Phase string `json:"phase"`
+	// When the VM entered the current phase.
+	PhaseStarted *meta.Time `json:"phaseStarted,omitempty"`
 	// Errors
 	Error *Error `json:"error,omitempty"`
 	// Warm migration status
 	Warm *Warm `json:"warm,omitempty"`
+	// Shared (and RDM) disks skipped (not migrated).
+	SkippedDisks []string `json:"skippedDisks,omitempty"`
+	// Shared disks keyed by the disk identifier as reported on
+	// the DiskTransfer task. The value is the ID of the VM which
+	// owns (transfers) the disk.
+	SharedDisks map[string]string `json:"sharedDisks,omitempty"`
+	// The ImageConversion step has been skipped.
+	ConversionSkipped bool `json:"conversionSkipped,omitempty"`
+	// Disk transfer backend (CDI|InPlace).
+	Backend string `json:"backend,omitempty"`
+	// Source VM UUID recorded when the VM was added
+	// to the migration.
+	UUID string `json:"uuid,omitempty"`
+	// Source VM power state (On|Off) recorded
+	// when the migration started.
+	SourcePowerState string `json:"sourcePowerState,omitempty"`
+	// The source VM is a template.
+	Template bool `json:"template,omitempty"`
+	// Storage classes assigned to disks on storage mapped to
+	// multiple storage classes. Keyed by the disk identifier
+	// as reported on the DiskTransfer task.
+	StorageClasses map[string]string `json:"storageClasses,omitempty"`
+	// Source VM firmware (BIOS|UEFI|UEFI-SecureBoot) detected
+	// when the migration started.
+	Firmware string `json:"firmware,omitempty"`
+	// The resources created for the (canceled or failed)
+	// VM migration have been deleted.
+	Cleaned bool `json:"cleaned,omitempty"`
+	// Previous migration attempts (oldest first).
+	// Limited to the last MaxAttempts attempts.
+	History []Attempt `json:"history,omitempty"`
 
 	// Conditions.
 	libcnd.Conditions `json:",inline"`
 }
 
+//
+// Record (a summary of) the current attempt in the history
+// before the migration is reset. VMs which have not been
+// started have nothing to record.
+func (r *VMStatus) RecordAttempt(outcome string) {
+	if r.Started == nil {
+		return
+	}
+	attempt := Attempt{
+		Outcome: outcome,
+		Phase:   r.Phase,
+	}
+	r.Timed.DeepCopyInto(&attempt.Timed)
+	if r.Completed != nil {
+		attempt.Duration = int64(r.Completed.Sub(r.Started.Time).Seconds())
+	}
+	if r.Error != nil {
+		attempt.Error = r.Error.DeepCopy()
+	}
+	r.History = append(r.History, attempt)
+	if n := len(r.History); n > MaxAttempts {
+		r.History = r.History[n-MaxAttempts:]
+	}
+}
+
+//
+// Max number of attempts recorded in the VM history.
+const MaxAttempts = 10
+
+//
+// VM migration attempt.
+type Attempt struct {
+	Timed `json:",inline"`
+	// Outcome: Succeeded|Failed|Canceled.
+	// Not set when the attempt did not complete.
+	Outcome string `json:"outcome,omitempty"`
+	// The phase reached.
+	Phase string `json:"phase"`
+	// Duration (seconds).
+	Duration int64 `json:"duration,omitempty"`
+	// Errors.
+	Error *Error `json:"error,omitempty"`
+}
+
+//
+// Task annotations.
+const (
+	// Storage class assigned to the disk.
+	AnnStorageClass = "storageClass"
+	// Provider task which quiesces the guest.
+	AnnQuiesceTask = "quiesceTask"
+)
+
+//
+// Disk transfer backends.
+const (
+	// Disks are transferred by the importer (CDI) and the
+	// image converted (virt-v2v) as separate steps.
+	BackendCDI = "CDI"
+	// Disks are transferred and converted in place (virt-v2v)
+	// as a single step.
+	BackendInPlace = "InPlace"
+)
+
+//
+// Power states.
+const (
+	PowerOn  = "On"
+	PowerOff = "Off"
+)
+
+//
+// Firmware.
+const (
+	FirmwareBIOS       = "BIOS"
+	FirmwareUEFI       = "UEFI"
+	FirmwareSecureBoot = "UEFI-SecureBoot"
+)
+
 //
 // Warm Migration status
 type Warm struct {
@@ -73,12 +372,56 @@ type Warm struct {
 	ConsecutiveFailures int        `json:"consecutiveFailures"`
 	NextPrecopyAt       *meta.Time `json:"nextPrecopyAt,omitempty"`
 	Precopies           []Precopy  `json:"precopies,omitempty"`
+	// Cutover forced when the precopy limit was reached
+	// or the source VM was powered off.
+	Cutover *meta.Time `json:"cutover,omitempty"`
+	// Estimated cutover downtime (disk transfer).
+	// The duration of the last completed precopy.
+	EstimatedDowntime *meta.Duration `json:"estimatedDowntime,omitempty"`
+}
+
+//
+// The last completed precopy.
+func (r *Warm) LastPrecopy() (precopy *Precopy, found bool) {
+	for i := len(r.Precopies) - 1; i >= 0; i-- {
+		if r.Precopies[i].End != nil {
+			precopy = &r.Precopies[i]
+			found = true
+			break
+		}
+	}
+
+	return
 }
 
 // Precopy durations
 type Precopy struct {
 	Start *meta.Time `json:"start,omitempty"`
 	End   *meta.Time `json:"end,omitempty"`
+	// Duration. Partial while the precopy is in progress.
+	Duration *meta.Duration `json:"duration,omitempty"`
+	// Bytes transferred (estimated) by the precopy.
+	Bytes int64 `json:"bytes,omitempty"`
+	// Cumulative bytes transferred (estimated) by the precopies
+	// as reported by the importer.
+	Transferred int64 `json:"transferred,omitempty"`
+}
+
+//
+// Update the duration.
+// The duration of an in-progress precopy is
+// measured to now.
+func (r *Precopy) SetDuration() {
+	if r.Start == nil {
+		return
+	}
+	end := meta.Now()
+	if r.End != nil {
+		end = *r.End
+	}
+	r.Duration = &meta.Duration{
+		Duration: end.Sub(r.Start.Time),
+	}
 }
 
 //
@@ -116,7 +459,7 @@ func (r *VMStatus) ReflectPipeline() {
 		if step.MarkedCompleted() {
 			nCompleted++
 		}
-		if step.Error != nil {
+		if step.Error != nil && !step.Ignored {
 			r.AddError(step.Error.Reasons...)
 		}
 	}
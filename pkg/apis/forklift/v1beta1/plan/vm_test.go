@@ -0,0 +1,51 @@
+package plan
+
+import (
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func TestRecordAttempt(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	vm := &VMStatus{Phase: "Started"}
+	// Not started.
+	vm.RecordAttempt("")
+	g.Expect(vm.History).To(gomega.BeEmpty())
+	// Failed.
+	started := meta.NewTime(time.Now().Add(-time.Minute))
+	completed := meta.Now()
+	vm.Started = &started
+	vm.Completed = &completed
+	vm.Phase = "Completed"
+	vm.AddError("failed")
+	vm.RecordAttempt("Failed")
+	g.Expect(vm.History).To(gomega.HaveLen(1))
+	attempt := vm.History[0]
+	g.Expect(attempt.Outcome).To(gomega.Equal("Failed"))
+	g.Expect(attempt.Phase).To(gomega.Equal("Completed"))
+	g.Expect(attempt.Duration).To(gomega.Equal(int64(60)))
+	g.Expect(attempt.Error.Reasons).To(gomega.Equal([]string{"failed"}))
+	// Capped.
+	for i := 0; i < MaxAttempts+5; i++ {
+		vm.RecordAttempt("Canceled")
+	}
+	g.Expect(vm.History).To(gomega.HaveLen(MaxAttempts))
+	g.Expect(vm.History[0].Outcome).To(gomega.Equal("Canceled"))
+}
+
+func TestReflectPipelineIgnored(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	step := &Step{Task: Task{Name: "PostHook"}}
+	step.AddError("hook failed")
+	vm := &VMStatus{Pipeline: []*Step{step}}
+	// Abort.
+	vm.ReflectPipeline()
+	g.Expect(vm.Error).ToNot(gomega.BeNil())
+	// Ignored.
+	vm.Error = nil
+	step.Ignored = true
+	vm.ReflectPipeline()
+	g.Expect(vm.Error).To(gomega.BeNil())
+}
@@ -0,0 +1,155 @@
+package plan
+
+import (
+	"fmt"
+	liberr "github.com/konveyor/controller/pkg/error"
+	"strings"
+	"time"
+)
+
+//
+// Window time of day format (UTC).
+const WindowTimeFormat = "15:04"
+
+//
+// Recurring (weekly) time window.
+type Window struct {
+	// Days of the week (Sunday - Saturday).
+	// Every day when not specified.
+	Days []string `json:"days,omitempty"`
+	// Start time of day (HH:MM UTC).
+	Start string `json:"start"`
+	// End time of day (HH:MM UTC).
+	// A window which ends before it starts spans midnight.
+	End string `json:"end"`
+}
+
+//
+// Validate the window.
+func (r *Window) Validate() (err error) {
+	_, _, err = r.offsets()
+	if err != nil {
+		return
+	}
+	for _, day := range r.Days {
+		if _, found := r.weekday(day); !found {
+			err = liberr.New(
+				fmt.Sprintf(
+					"Day `%s` not valid.",
+					day))
+			return
+		}
+	}
+
+	return
+}
+
+//
+// The interval starting on the day (midnight) when
+// the window applies to the day of the week.
+func (r *Window) interval(day time.Time) (start, end time.Time, applies bool) {
+	applies = len(r.Days) == 0
+	for _, name := range r.Days {
+		if weekday, found := r.weekday(name); found && weekday == day.Weekday() {
+			applies = true
+			break
+		}
+	}
+	if !applies {
+		return
+	}
+	startAt, endAt, err := r.offsets()
+	if err != nil {
+		applies = false
+		return
+	}
+	start = day.Add(startAt)
+	end = day.Add(endAt)
+	if !end.After(start) {
+		end = end.Add(24 * time.Hour)
+	}
+
+	return
+}
+
+//
+// Start and end offsets from midnight.
+func (r *Window) offsets() (start, end time.Duration, err error) {
+	midnight, _ := time.Parse(WindowTimeFormat, "00:00")
+	for _, p := range []struct {
+		value  string
+		offset *time.Duration
+	}{
+		{r.Start, &start},
+		{r.End, &end},
+	} {
+		t, pErr := time.Parse(WindowTimeFormat, p.value)
+		if pErr != nil {
+			err = liberr.New(
+				fmt.Sprintf(
+					"Time `%s` not valid (HH:MM).",
+					p.value))
+			return
+		}
+		*p.offset = t.Sub(midnight)
+	}
+
+	return
+}
+
+//
+// Find the day of the week by name.
+func (r *Window) weekday(name string) (day time.Weekday, found bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), name) {
+			day = d
+			found = true
+			break
+		}
+	}
+
+	return
+}
+
+//
+// Determine whether any of the windows is open at the
+// specified time. The boundary is when the (open) window
+// closes or when the next window opens. The windows are
+// always open (with no boundary) when none are specified.
+func InWindow(windows []Window, now time.Time) (open bool, boundary time.Time) {
+	if len(windows) == 0 {
+		open = true
+		return
+	}
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	opens := time.Time{}
+	closes := time.Time{}
+	// Windows which started yesterday may span midnight.
+	for n := -1; n <= 7; n++ {
+		day := today.AddDate(0, 0, n)
+		for i := range windows {
+			start, end, applies := windows[i].interval(day)
+			if !applies {
+				continue
+			}
+			if !now.Before(start) && now.Before(end) {
+				if closes.IsZero() || end.Before(closes) {
+					closes = end
+				}
+				continue
+			}
+			if start.After(now) && (opens.IsZero() || start.Before(opens)) {
+				opens = start
+			}
+		}
+	}
+	if !closes.IsZero() {
+		open = true
+		boundary = closes
+	} else {
+		boundary = opens
+	}
+
+	return
+}
@@ -0,0 +1,41 @@
+package plan
+
+import (
+	"github.com/onsi/gomega"
+	"testing"
+	"time"
+)
+
+func TestInWindow(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	at := func(s string) time.Time {
+		t, _ := time.Parse(time.RFC3339, s)
+		return t
+	}
+	// Not specified.
+	open, _ := InWindow(nil, at("2021-06-07T12:00:00Z"))
+	g.Expect(open).To(gomega.BeTrue())
+	// Nightly (spans midnight).
+	nightly := []Window{{Start: "22:00", End: "06:00"}}
+	open, boundary := InWindow(nightly, at("2021-06-07T23:00:00Z"))
+	g.Expect(open).To(gomega.BeTrue())
+	g.Expect(boundary).To(gomega.Equal(at("2021-06-08T06:00:00Z")))
+	open, boundary = InWindow(nightly, at("2021-06-08T01:00:00Z"))
+	g.Expect(open).To(gomega.BeTrue())
+	g.Expect(boundary).To(gomega.Equal(at("2021-06-08T06:00:00Z")))
+	open, boundary = InWindow(nightly, at("2021-06-08T12:00:00Z"))
+	g.Expect(open).To(gomega.BeFalse())
+	g.Expect(boundary).To(gomega.Equal(at("2021-06-08T22:00:00Z")))
+	// Weekends (2021-06-07 is a Monday).
+	weekend := []Window{{Days: []string{"Saturday", "sunday"}, Start: "00:00", End: "00:00"}}
+	open, boundary = InWindow(weekend, at("2021-06-07T12:00:00Z"))
+	g.Expect(open).To(gomega.BeFalse())
+	g.Expect(boundary).To(gomega.Equal(at("2021-06-12T00:00:00Z")))
+	open, boundary = InWindow(weekend, at("2021-06-13T12:00:00Z"))
+	g.Expect(open).To(gomega.BeTrue())
+	g.Expect(boundary).To(gomega.Equal(at("2021-06-14T00:00:00Z")))
+	// Not valid.
+	g.Expect((&Window{Start: "25:00", End: "06:00"}).Validate()).ToNot(gomega.BeNil())
+	g.Expect((&Window{Days: []string{"Someday"}, Start: "22:00", End: "06:00"}).Validate()).ToNot(gomega.BeNil())
+	g.Expect(nightly[0].Validate()).To(gomega.BeNil())
+}
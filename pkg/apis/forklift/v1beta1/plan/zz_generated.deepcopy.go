@@ -20,7 +20,46 @@ limitations under the License.
 
 package plan
 
-import ()
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Attempt) DeepCopyInto(out *Attempt) {
+	*out = *in
+	in.Timed.DeepCopyInto(&out.Timed)
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = new(Error)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Attempt.
+func (in *Attempt) DeepCopy() *Attempt {
+	if in == nil {
+		return nil
+	}
+	out := new(Attempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskMap) DeepCopyInto(out *DiskMap) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskMap.
+func (in *DiskMap) DeepCopy() *DiskMap {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskMap)
+	in.DeepCopyInto(out)
+	return out
+}
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Error) DeepCopyInto(out *Error) {
@@ -42,6 +81,23 @@ func (in *Error) DeepCopy() *Error {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HookSelector) DeepCopyInto(out *HookSelector) {
+	*out = *in
+	out.HookRef = in.HookRef
+	in.Selector.DeepCopyInto(&out.Selector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookSelector.
+func (in *HookSelector) DeepCopy() *HookSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(HookSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HookRef) DeepCopyInto(out *HookRef) {
 	*out = *in
@@ -109,6 +165,22 @@ func (in *MigrationStatus) DeepCopy() *MigrationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkMap) DeepCopyInto(out *NetworkMap) {
+	*out = *in
+	out.Source = in.Source
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkMap.
+func (in *NetworkMap) DeepCopy() *NetworkMap {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Precopy) DeepCopyInto(out *Precopy) {
 	*out = *in
@@ -120,6 +192,11 @@ func (in *Precopy) DeepCopyInto(out *Precopy) {
 		in, out := &in.End, &out.End
 		*out = (*in).DeepCopy()
 	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Precopy.
@@ -201,6 +278,23 @@ func (in *SnapshotRefPair) DeepCopy() *SnapshotRefPair {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Source) DeepCopyInto(out *Source) {
+	*out = *in
+	out.Provider = in.Provider
+	out.Map = in.Map
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Source.
+func (in *Source) DeepCopy() *Source {
+	if in == nil {
+		return nil
+	}
+	out := new(Source)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Step) DeepCopyInto(out *Step) {
 	*out = *in
@@ -284,11 +378,62 @@ func (in *Timed) DeepCopy() *Timed {
 func (in *VM) DeepCopyInto(out *VM) {
 	*out = *in
 	out.Ref = in.Ref
+	if in.Provider != nil {
+		in, out := &in.Provider, &out.Provider
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
 		*out = make([]HookRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.TargetLabels != nil {
+		in, out := &in.TargetLabels, &out.TargetLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TargetAnnotations != nil {
+		in, out := &in.TargetAnnotations, &out.TargetAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TargetNodeSelector != nil {
+		in, out := &in.TargetNodeSelector, &out.TargetNodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DiskMap, len(*in))
+		copy(*out, *in)
+	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]NetworkMap, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedDisks != nil {
+		in, out := &in.ExcludedDisks, &out.ExcludedDisks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WarmMigration != nil {
+		in, out := &in.WarmMigration, &out.WarmMigration
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VM.
@@ -317,6 +462,10 @@ func (in *VMStatus) DeepCopyInto(out *VMStatus) {
 			}
 		}
 	}
+	if in.PhaseStarted != nil {
+		in, out := &in.PhaseStarted, &out.PhaseStarted
+		*out = (*in).DeepCopy()
+	}
 	if in.Error != nil {
 		in, out := &in.Error, &out.Error
 		*out = new(Error)
@@ -327,6 +476,32 @@ func (in *VMStatus) DeepCopyInto(out *VMStatus) {
 		*out = new(Warm)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SkippedDisks != nil {
+		in, out := &in.SkippedDisks, &out.SkippedDisks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SharedDisks != nil {
+		in, out := &in.SharedDisks, &out.SharedDisks
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]Attempt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.Conditions.DeepCopyInto(&out.Conditions)
 }
 
@@ -354,6 +529,15 @@ func (in *Warm) DeepCopyInto(out *Warm) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Cutover != nil {
+		in, out := &in.Cutover, &out.Cutover
+		*out = (*in).DeepCopy()
+	}
+	if in.EstimatedDowntime != nil {
+		in, out := &in.EstimatedDowntime, &out.EstimatedDowntime
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Warm.
@@ -365,3 +549,23 @@ func (in *Warm) DeepCopy() *Warm {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Window) DeepCopyInto(out *Window) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Window.
+func (in *Window) DeepCopy() *Window {
+	if in == nil {
+		return nil
+	}
+	out := new(Window)
+	in.DeepCopyInto(out)
+	return out
+}
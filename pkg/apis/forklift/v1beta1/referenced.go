@@ -25,6 +25,38 @@ type Referenced struct {
 	}
 	// Hooks.
 	Hooks []*Hook
+	// Hosts.
+	Hosts []*Host
+	// Additional sources.
+	Sources []*ReferencedSource
+}
+
+//
+// Referenced additional source.
+// +k8s:deepcopy-gen=false
+type ReferencedSource struct {
+	// Provider.
+	Provider *Provider
+	// Map
+	Map struct {
+		// Network
+		Network *NetworkMap
+		// Storage
+		Storage *StorageMap
+	}
+}
+
+//
+// Find host by ID or name.
+func (in *Referenced) FindHost(id, name string) (found bool, host *Host) {
+	for _, host = range in.Hosts {
+		if (id != "" && host.Spec.ID == id) || (name != "" && host.Spec.Name == name) {
+			found = true
+			break
+		}
+	}
+
+	return
 }
 
 //
@@ -40,6 +72,19 @@ func (in *Referenced) FindHook(ref core.ObjectReference) (found bool, hook *Hook
 	return
 }
 
+//
+// Find additional source by provider ref.
+func (in *Referenced) FindSource(ref core.ObjectReference) (found bool, source *ReferencedSource) {
+	for _, source = range in.Sources {
+		if source.Provider.Namespace == ref.Namespace && source.Provider.Name == ref.Name {
+			found = true
+			break
+		}
+	}
+
+	return
+}
+
 func (in *Referenced) DeepCopyInto(*Referenced) {
 }
 
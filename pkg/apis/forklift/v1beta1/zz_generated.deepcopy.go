@@ -65,6 +65,11 @@ func (in *DestinationNetwork) DeepCopy() *DestinationNetwork {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DestinationStorage) DeepCopyInto(out *DestinationStorage) {
 	*out = *in
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DestinationStorage.
@@ -553,6 +558,11 @@ func (in *PlanSpec) DeepCopyInto(out *PlanSpec) {
 	*out = *in
 	out.Provider = in.Provider
 	out.Map = in.Map
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]plan.Source, len(*in))
+		copy(*out, *in)
+	}
 	if in.VMs != nil {
 		in, out := &in.VMs, &out.VMs
 		*out = make([]plan.VM, len(*in))
@@ -560,11 +570,61 @@ func (in *PlanSpec) DeepCopyInto(out *PlanSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]plan.HookSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.TransferNetwork != nil {
 		in, out := &in.TransferNetwork, &out.TransferNetwork
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.TransferCA != nil {
+		in, out := &in.TransferCA, &out.TransferCA
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.TargetPVCLabels != nil {
+		in, out := &in.TargetPVCLabels, &out.TargetPVCLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TargetPVCAnnotations != nil {
+		in, out := &in.TargetPVCAnnotations, &out.TargetPVCAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TargetNodeSelector != nil {
+		in, out := &in.TargetNodeSelector, &out.TargetNodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CompletionWebhook != nil {
+		in, out := &in.CompletionWebhook, &out.CompletionWebhook
+		*out = new(Webhook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedWindows != nil {
+		in, out := &in.AllowedWindows, &out.AllowedWindows
+		*out = make([]plan.Window, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImporterResources != nil {
+		in, out := &in.ImporterResources, &out.ImporterResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanSpec.
@@ -855,7 +915,9 @@ func (in *StorageMapSpec) DeepCopyInto(out *StorageMapSpec) {
 	if in.Map != nil {
 		in, out := &in.Map, &out.Map
 		*out = make([]StoragePair, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -873,7 +935,7 @@ func (in *StorageMapSpec) DeepCopy() *StorageMapSpec {
 func (in *StoragePair) DeepCopyInto(out *StoragePair) {
 	*out = *in
 	out.Source = in.Source
-	out.Destination = in.Destination
+	in.Destination.DeepCopyInto(&out.Destination)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePair.
@@ -912,3 +974,25 @@ func (in *VolumeMode) DeepCopy() *VolumeMode {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Webhook) DeepCopyInto(out *Webhook) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Webhook.
+func (in *Webhook) DeepCopy() *Webhook {
+	if in == nil {
+		return nil
+	}
+	out := new(Webhook)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/provider"
 	"github.com/konveyor/forklift-controller/pkg/settings"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"time"
 )
 
 //
@@ -82,3 +83,18 @@ func AddToManager(m manager.Manager) error {
 
 	return nil
 }
+
+//
+// Graceful shutdown.
+// Wait for the reconciles in progress to end (and
+// persist status) after the manager has stopped.
+// Returns false when the timeout expired.
+func Shutdown() (ended bool) {
+	ended = true
+	if Settings.Role.Has(settings.MainRole) {
+		timeout := time.Second * time.Duration(Settings.Migration.ShutdownTimeout)
+		ended = plan.Shutdown.Wait(timeout)
+	}
+
+	return
+}
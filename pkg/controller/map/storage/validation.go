@@ -146,14 +146,15 @@ func (r *Reconciler) validateDestination(mp *api.StorageMap) (err error) {
 	notValid := []string{}
 	list := mp.Spec.Map
 	for _, entry := range list {
-		name := entry.Destination.StorageClass
-		_, pErr := inventory.Storage(&refapi.Ref{Name: name})
-		if pErr != nil {
-			if errors.As(pErr, &web.NotFoundError{}) {
-				notValid = append(notValid, entry.Destination.StorageClass)
-			} else {
-				err = pErr
-				return
+		for _, name := range entry.Destination.Classes() {
+			_, pErr := inventory.Storage(&refapi.Ref{Name: name})
+			if pErr != nil {
+				if errors.As(pErr, &web.NotFoundError{}) {
+					notValid = append(notValid, name)
+				} else {
+					err = pErr
+					return
+				}
 			}
 		}
 	}
@@ -6,6 +6,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	core "k8s.io/api/core/v1"
+	cnv "kubevirt.io/client-go/api/v1"
 	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
 )
@@ -32,8 +33,28 @@ type Builder interface {
 	Import(vmRef ref.Ref, object *vmio.VirtualMachineImportSpec) error
 	// Build tasks.
 	Tasks(vmRef ref.Ref) ([]*plan.Task, error)
+	// The disk transfer backend targeted by the tasks.
+	TransferBackend(vmRef ref.Ref) string
+	// Find shared (and RDM) disks which cannot be migrated.
+	SharedDisks(vmRef ref.Ref) ([]string, error)
+	// Find the source host and datastores in maintenance mode.
+	MaintenanceMode(vmRef ref.Ref) ([]string, error)
+	// Find the source networks and storage used by the VM
+	// which are not mapped.
+	Unmapped(vmRef ref.Ref) ([]string, error)
+	// Start quiescing the guest file systems of the source VM.
+	// Returns the task to be polled (empty when nothing has been
+	// started) and false when the guest cannot be quiesced.
+	Quiesce(vmRef ref.Ref) (string, bool, error)
+	// Poll the quiesce task. Returns the next task to be
+	// polled and true when the quiesce has completed.
+	QuiesceTask(vmRef ref.Ref, task string) (string, bool, error)
+	// Find the source VM power state (On|Off).
+	PowerState(vmRef ref.Ref) (string, error)
 	// Return a stable identifier for a DataVolume.
 	ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string
+	// Build the target VM CPU topology and placement.
+	CPU(vmRef ref.Ref, object *cnv.CPU) error
 }
 
 //
@@ -46,4 +67,30 @@ type Validator interface {
 	NetworksMapped(vmRef ref.Ref) (bool, error)
 	// Validate that a VM's Host isn't in maintenance mode.
 	MaintenanceMode(vmRef ref.Ref) (bool, error)
+	// Validate that a VM's shared (and RDM) disks may be skipped.
+	SharedDisks(vmRef ref.Ref) (bool, error)
+	// Validate that a VM's disk overrides reference disks on the VM.
+	DiskOverrides(vmRef ref.Ref) (bool, error)
+	// Validate that a VM's excluded disks reference disks on the VM.
+	ExcludedDisks(vmRef ref.Ref) (bool, error)
+	// Validate that changed block tracking (CBT) is enabled.
+	ChangeTracking(vmRef ref.Ref) (bool, error)
+	// Validate that the MTU of the source network used for disk
+	// transfer does not exceed the specified MTU. Returns the
+	// source MTU.
+	TransferMTU(vmRef ref.Ref, mtu int32) (bool, int32, error)
+	// Find VM devices which cannot be migrated.
+	// Returns the device kinds.
+	UnsupportedDevices(vmRef ref.Ref) ([]string, error)
+	// Find the (enabled) anti-affinity groups which include
+	// the VM. Returns the group names.
+	AntiAffinity(vmRef ref.Ref) ([]string, error)
+	// Validate that the importer supports disk transfer
+	// bandwidth throttling.
+	BandwidthLimit() bool
+	// Validate that the importer supports limiting the
+	// number of disks transferred concurrently for a VM.
+	DisksPerVM() bool
+	// Validate that the importer supports the target disk format.
+	DiskFormat(format string) bool
 }
@@ -13,9 +13,12 @@ import (
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
 	"gopkg.in/yaml.v2"
 	core "k8s.io/api/core/v1"
+	cnv "kubevirt.io/client-go/api/v1"
 	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strings"
 )
 
 //
@@ -30,13 +33,29 @@ type Builder struct {
 // Build the VMIO secret.
 func (r *Builder) Secret(vmRef ref.Ref, in, object *core.Secret) (err error) {
 	url := r.Source.Provider.Spec.URL
+	caCert := string(in.Data["cacert"])
+	if transferCA := r.Plan.Spec.TransferCA; transferCA != nil {
+		secret := &core.Secret{}
+		err = r.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: transferCA.Namespace,
+				Name:      transferCA.Name,
+			},
+			secret)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		caCert = strings.TrimSpace(caCert) + "\n" + string(secret.Data[api.TransferCAKey])
+	}
 
 	content, mErr := yaml.Marshal(
 		map[string]string{
 			"apiUrl":   url,
 			"username": string(in.Data["user"]),
 			"password": string(in.Data["password"]),
-			"caCert":   string(in.Data["cacert"]),
+			"caCert":   caCert,
 		})
 	if mErr != nil {
 		err = liberr.Wrap(mErr)
@@ -69,7 +88,11 @@ func (r *Builder) Import(vmRef ref.Ref, object *vmio.VirtualMachineImportSpec) (
 			ID: &vm.ID,
 		},
 	}
-	object.Source.Ovirt.Mappings, err = r.mapping(vm)
+	planVM, found := r.Plan.Spec.FindVM(vmRef)
+	if !found {
+		planVM = &plan.VM{}
+	}
+	object.Source.Ovirt.Mappings, err = r.mapping(vm, planVM)
 	if err != nil {
 		return
 	}
@@ -77,31 +100,40 @@ func (r *Builder) Import(vmRef ref.Ref, object *vmio.VirtualMachineImportSpec) (
 	return
 }
 
-func (r *Builder) mapping(vm *model.VM) (out *vmio.OvirtMappings, err error) {
+//
+// Build the VMIO ResourceMapping CR.
+// The VM (disk and network) overrides take precedence
+// over the plan mappings.
+func (r *Builder) mapping(vm *model.VM, planVM *plan.VM) (out *vmio.OvirtMappings, err error) {
 	netMap := []vmio.NetworkResourceMappingItem{}
 	storageMap := []vmio.StorageResourceMappingItem{}
-	netMapIn := r.Context.Map.Network.Spec.Map
-	for i := range netMapIn {
-		mapped := &netMapIn[i]
-		ref := mapped.Source
-		network := &model.Network{}
-		fErr := r.Source.Inventory.Find(network, ref)
-		if err != nil {
-			err = fErr
-			return
-		}
-		needed := false
-		profileId := ""
-		for _, nic := range vm.NICs {
-			if nic.Profile.Network == network.ID {
-				needed = true
-				profileId = nic.Profile.ID
-				break
-			}
-		}
-		if !needed {
+	diskMap := []vmio.StorageResourceMappingItem{}
+	netDestination, err := r.networkDestinations(planVM)
+	if err != nil {
+		return
+	}
+	// Each (distinct) vNIC profile used by the VM NICs is
+	// mapped to the destination of the profile network. An
+	// unmapped network is an error rather than a dropped interface.
+	profileMapped := map[string]bool{}
+	for _, nic := range vm.NICs {
+		profileId := nic.Profile.ID
+		if profileMapped[profileId] {
 			continue
 		}
+		profileMapped[profileId] = true
+		destination, found := netDestination[nic.Profile.Network]
+		if !found {
+			err = liberr.New(
+				"VM NIC network not mapped.",
+				"vm",
+				vm.ID,
+				"nic",
+				nic.ID,
+				"network",
+				nic.Profile.Network)
+			return
+		}
 		netMap = append(
 			netMap,
 			vmio.NetworkResourceMappingItem{
@@ -109,10 +141,10 @@ func (r *Builder) mapping(vm *model.VM) (out *vmio.OvirtMappings, err error) {
 					ID: &profileId,
 				},
 				Target: vmio.ObjectIdentifier{
-					Namespace: &mapped.Destination.Namespace,
-					Name:      mapped.Destination.Name,
+					Namespace: &destination.Namespace,
+					Name:      destination.Name,
 				},
-				Type: &mapped.Destination.Type,
+				Type: &destination.Type,
 			})
 	}
 	storageMapIn := r.Context.Map.Storage.Spec.Map
@@ -135,7 +167,8 @@ func (r *Builder) mapping(vm *model.VM) (out *vmio.OvirtMappings, err error) {
 		if !needed {
 			continue
 		}
-		mErr := r.defaultModes(&mapped.Destination)
+		destination := mapped.Destination
+		mErr := r.defaultModes(&destination)
 		if mErr != nil {
 			err = mErr
 			return
@@ -145,20 +178,159 @@ func (r *Builder) mapping(vm *model.VM) (out *vmio.OvirtMappings, err error) {
 				ID: &domain.ID,
 			},
 			Target: vmio.ObjectIdentifier{
-				Name: mapped.Destination.StorageClass,
+				Name: destination.StorageClass,
 			},
 		}
-		if mapped.Destination.VolumeMode != "" {
-			item.VolumeMode = &mapped.Destination.VolumeMode
+		if destination.VolumeMode != "" {
+			item.VolumeMode = &destination.VolumeMode
 		}
-		if mapped.Destination.AccessMode != "" {
-			item.AccessMode = &mapped.Destination.AccessMode
+		if destination.AccessMode != "" {
+			item.AccessMode = &destination.AccessMode
 		}
 		storageMap = append(storageMap, item)
 	}
+	for i := range vm.DiskAttachments {
+		disk := &vm.DiskAttachments[i].Disk
+		override, found := planVM.FindDisk(disk.ID)
+		if !found {
+			continue
+		}
+		destination := api.DestinationStorage{
+			StorageClass: override.StorageClass,
+			VolumeMode:   override.VolumeMode,
+			AccessMode:   override.AccessMode,
+		}
+		mErr := r.defaultModes(&destination)
+		if mErr != nil {
+			err = mErr
+			return
+		}
+		item := vmio.StorageResourceMappingItem{
+			Source: vmio.Source{
+				ID: &disk.ID,
+			},
+			Target: vmio.ObjectIdentifier{
+				Name: destination.StorageClass,
+			},
+		}
+		if destination.VolumeMode != "" {
+			item.VolumeMode = &destination.VolumeMode
+		}
+		if destination.AccessMode != "" {
+			item.AccessMode = &destination.AccessMode
+		}
+		diskMap = append(diskMap, item)
+	}
+	assigned, err := r.diskClasses(vm, planVM)
+	if err != nil {
+		return
+	}
+	for i := range vm.DiskAttachments {
+		disk := &vm.DiskAttachments[i].Disk
+		destination, found := assigned[disk.ID]
+		if !found {
+			continue
+		}
+		mErr := r.defaultModes(&destination)
+		if mErr != nil {
+			err = mErr
+			return
+		}
+		item := vmio.StorageResourceMappingItem{
+			Source: vmio.Source{
+				ID: &disk.ID,
+			},
+			Target: vmio.ObjectIdentifier{
+				Name: destination.StorageClass,
+			},
+		}
+		if destination.VolumeMode != "" {
+			item.VolumeMode = &destination.VolumeMode
+		}
+		if destination.AccessMode != "" {
+			item.AccessMode = &destination.AccessMode
+		}
+		diskMap = append(diskMap, item)
+	}
 	out = &vmio.OvirtMappings{
 		NetworkMappings: &netMap,
 		StorageMappings: &storageMap,
+		DiskMappings:    &diskMap,
+	}
+
+	return
+}
+
+//
+// Destination storage assigned to disks keyed by disk ID.
+// The disks on a storage domain mapped to multiple storage classes
+// are assigned to the classes round-robin in the order of the disk
+// ID so that the assignment is deterministic. Disks with an override
+// are not assigned.
+func (r *Builder) diskClasses(vm *model.VM, planVM *plan.VM) (assigned map[string]api.DestinationStorage, err error) {
+	assigned = map[string]api.DestinationStorage{}
+	if r.Context.Map.Storage == nil {
+		return
+	}
+	storageMapIn := r.Context.Map.Storage.Spec.Map
+	for i := range storageMapIn {
+		mapped := &storageMapIn[i]
+		if len(mapped.Destination.Classes()) < 2 {
+			continue
+		}
+		domain := &model.StorageDomain{}
+		fErr := r.Source.Inventory.Find(domain, mapped.Source)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		ids := []string{}
+		for _, da := range vm.DiskAttachments {
+			if da.Disk.StorageDomain != domain.ID {
+				continue
+			}
+			if _, found := planVM.FindDisk(da.Disk.ID); found {
+				continue
+			}
+			ids = append(ids, da.Disk.ID)
+		}
+		sort.Strings(ids)
+		for n, id := range ids {
+			assigned[id] = mapped.Destination.Assign(n)
+		}
+	}
+
+	return
+}
+
+//
+// Network destinations keyed by (source) network ID.
+// The plan network mapping with the VM overrides applied.
+func (r *Builder) networkDestinations(planVM *plan.VM) (destinations map[string]api.DestinationNetwork, err error) {
+	destinations = map[string]api.DestinationNetwork{}
+	netMapIn := r.Context.Map.Network.Spec.Map
+	for i := range netMapIn {
+		mapped := &netMapIn[i]
+		network := &model.Network{}
+		fErr := r.Source.Inventory.Find(network, mapped.Source)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		destinations[network.ID] = mapped.Destination
+	}
+	for _, m := range planVM.Networks {
+		network := &model.Network{}
+		fErr := r.Source.Inventory.Find(network, m.Source)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		destinations[network.ID] = api.DestinationNetwork{
+			Type:      m.Type,
+			Namespace: m.Namespace,
+			Name:      m.Name,
+		}
 	}
 
 	return
@@ -166,7 +338,15 @@ func (r *Builder) mapping(vm *model.VM) (out *vmio.OvirtMappings, err error) {
 
 //
 // Set volume and access modes.
+// Modes not specified by the mapping default to the
+// plan modes and then to the storage class provisioner.
 func (r *Builder) defaultModes(dm *api.DestinationStorage) (err error) {
+	if dm.VolumeMode == "" {
+		dm.VolumeMode = r.Plan.Spec.VolumeMode
+	}
+	if dm.AccessMode == "" {
+		dm.AccessMode = r.Plan.Spec.AccessMode
+	}
 	model := &ocp.StorageClass{}
 	ref := ref.Ref{Name: dm.StorageClass}
 	err = r.Destination.Inventory.Find(model, ref)
@@ -190,6 +370,13 @@ func (r *Builder) defaultModes(dm *api.DestinationStorage) (err error) {
 	return
 }
 
+//
+// The disk transfer backend targeted by the tasks.
+// Disks are transferred by CDI (imageio).
+func (r *Builder) TransferBackend(_ ref.Ref) string {
+	return plan.BackendCDI
+}
+
 //
 // Build tasks.
 func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
@@ -203,24 +390,198 @@ func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
 				pErr.Error()))
 		return
 	}
+	planVM, planned := r.Plan.Spec.FindVM(vmRef)
+	if !planned {
+		planVM = &plan.VM{}
+	}
+	assigned, err := r.diskClasses(vm, planVM)
+	if err != nil {
+		return
+	}
 	for _, da := range vm.DiskAttachments {
+		if r.Plan.Spec.SkipSharedDisks && da.Disk.Shared {
+			continue
+		}
+		if planVM.Excluded(da.Disk.ID) {
+			continue
+		}
 		mB := da.Disk.ProvisionedSize / 0x100000
-		list = append(
-			list,
-			&plan.Task{
-				Name: da.Disk.ID,
-				Progress: libitr.Progress{
-					Total: mB,
-				},
-				Annotations: map[string]string{
-					"unit": "MB",
-				},
-			})
+		task := &plan.Task{
+			Name: da.Disk.ID,
+			Progress: libitr.Progress{
+				Total: mB,
+			},
+			Annotations: map[string]string{
+				"unit": "MB",
+			},
+		}
+		if destination, found := assigned[da.Disk.ID]; found {
+			task.Annotations[plan.AnnStorageClass] = destination.StorageClass
+		}
+		list = append(list, task)
+	}
+
+	return
+}
+
+//
+// Find shared disks which cannot be migrated.
+func (r *Builder) SharedDisks(vmRef ref.Ref) (list []string, err error) {
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	for _, da := range vm.DiskAttachments {
+		if da.Disk.Shared {
+			list = append(list, da.Disk.ID)
+		}
+	}
+
+	return
+}
+
+//
+// Find the source VM power state.
+func (r *Builder) PowerState(vmRef ref.Ref) (state string, err error) {
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	state = plan.PowerOff
+	if vm.Status == "up" {
+		state = plan.PowerOn
+	}
+
+	return
+}
+
+//
+// Build the target VM CPU topology and placement.
+// CPU (or NUMA node) pinning is translated to
+// dedicated CPU placement.
+func (r *Builder) CPU(vmRef ref.Ref, object *cnv.CPU) (err error) {
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	if vm.CpuSockets > 0 {
+		object.Sockets = uint32(vm.CpuSockets)
+	}
+	if vm.CpuCores > 0 {
+		object.Cores = uint32(vm.CpuCores)
+	}
+	object.DedicatedCPUPlacement = len(vm.CpuAffinity) > 0 || len(vm.NumaNodeAffinity) > 0
+
+	return
+}
+
+//
+// Find the source networks and storage domains used by the
+// VM which are not mapped. Network overrides on the plan VM,
+// excluded (and skipped shared) disks are considered.
+func (r *Builder) Unmapped(vmRef ref.Ref) (list []string, err error) {
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	planVM, planned := r.Plan.Spec.FindVM(vmRef)
+	if !planned {
+		planVM = &plan.VM{}
+	}
+	checked := map[string]bool{}
+	for _, nic := range vm.NICs {
+		id := nic.Profile.Network
+		if checked[id] {
+			continue
+		}
+		checked[id] = true
+		if _, found := planVM.FindNetwork(id); found {
+			continue
+		}
+		netMap := r.Context.Map.Network
+		if netMap != nil && netMap.Status.Refs.Find(ref.Ref{ID: id}) {
+			continue
+		}
+		name := id
+		network := &model.Network{}
+		if r.Source.Inventory.Get(network, id) == nil {
+			name = network.Name
+		}
+		list = append(list, "network/"+name)
+	}
+	for _, da := range vm.DiskAttachments {
+		if r.Plan.Spec.SkipSharedDisks && da.Disk.Shared {
+			continue
+		}
+		if planVM.Excluded(da.Disk.ID) {
+			continue
+		}
+		id := da.Disk.StorageDomain
+		if checked[id] {
+			continue
+		}
+		checked[id] = true
+		sdMap := r.Context.Map.Storage
+		if sdMap != nil && sdMap.Status.Refs.Find(ref.Ref{ID: id}) {
+			continue
+		}
+		name := id
+		sd := &model.StorageDomain{}
+		if r.Source.Inventory.Get(sd, id) == nil {
+			name = sd.Name
+		}
+		list = append(list, "storagedomain/"+name)
 	}
 
 	return
 }
 
+//
+// Find the source host and storage domains in maintenance mode.
+// Not collected for oVirt.
+func (r *Builder) MaintenanceMode(vmRef ref.Ref) (list []string, err error) {
+	return
+}
+
+//
+// Quiesce the guest file systems of the source VM.
+// Not supported for oVirt.
+func (r *Builder) Quiesce(vmRef ref.Ref) (task string, quiesced bool, err error) {
+	return
+}
+
+//
+// Poll the quiesce task.
+// Not supported for oVirt.
+func (r *Builder) QuiesceTask(vmRef ref.Ref, task string) (next string, done bool, err error) {
+	done = true
+	return
+}
+
 //
 // Return a stable identifier for a DataVolume.
 func (r *Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
@@ -23,7 +23,7 @@ func (r *Validator) Load() (err error) {
 }
 
 //
-// Validate that a VM's networks have been mapped.
+// Validate that a VM's networks have been mapped (or overridden).
 func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 	if r.plan.Referenced.Map.Network == nil {
 		return
@@ -39,7 +39,13 @@ func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
+	planVM, _ := r.plan.Spec.FindVM(vmRef)
 	for _, nic := range vm.NICs {
+		if planVM != nil {
+			if _, found := planVM.FindNetwork(nic.Profile.Network); found {
+				continue
+			}
+		}
 		if !r.plan.Referenced.Map.Network.Status.Refs.Find(ref.Ref{ID: nic.Profile.Network}) {
 			return
 		}
@@ -79,3 +85,146 @@ func (r *Validator) MaintenanceMode(_ ref.Ref) (ok bool, err error) {
 	ok = true
 	return
 }
+
+//
+// Validate that a VM's shared disks may be skipped
+// or migrated.
+func (r *Validator) SharedDisks(vmRef ref.Ref) (ok bool, err error) {
+	if r.plan.Spec.SkipSharedDisks {
+		ok = true
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	for _, da := range vm.DiskAttachments {
+		if da.Disk.Shared && !r.plan.Spec.MigrateSharedDisks {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+//
+// Validate that changed block tracking (CBT) is enabled.
+// Not applicable to oVirt.
+func (r *Validator) ChangeTracking(vmRef ref.Ref) (ok bool, err error) {
+	ok = true
+	return
+}
+
+//
+// Validate that a VM's disk overrides reference disks on the VM.
+func (r *Validator) DiskOverrides(vmRef ref.Ref) (ok bool, err error) {
+	planVM, found := r.plan.Spec.FindVM(vmRef)
+	if !found || len(planVM.Disks) == 0 {
+		ok = true
+		return
+	}
+	disks, err := r.disks(vmRef)
+	if err != nil {
+		return
+	}
+	for _, m := range planVM.Disks {
+		if !disks[m.Disk] {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+//
+// Validate that a VM's excluded disks reference disks on the VM.
+func (r *Validator) ExcludedDisks(vmRef ref.Ref) (ok bool, err error) {
+	planVM, found := r.plan.Spec.FindVM(vmRef)
+	if !found || len(planVM.ExcludedDisks) == 0 {
+		ok = true
+		return
+	}
+	disks, err := r.disks(vmRef)
+	if err != nil {
+		return
+	}
+	for _, disk := range planVM.ExcludedDisks {
+		if !disks[disk] {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+//
+// The set of VM disk identifiers.
+func (r *Validator) disks(vmRef ref.Ref) (disks map[string]bool, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	disks = map[string]bool{}
+	for _, da := range vm.DiskAttachments {
+		disks[da.Disk.ID] = true
+	}
+
+	return
+}
+
+//
+// Validate the MTU of the source network used for disk transfer.
+// Not collected for oVirt.
+func (r *Validator) TransferMTU(_ ref.Ref, _ int32) (ok bool, sourceMTU int32, err error) {
+	ok = true
+	return
+}
+
+//
+// Find the (enabled) anti-affinity groups which include
+// the VM. Affinity groups are not collected for oVirt.
+func (r *Validator) AntiAffinity(vmRef ref.Ref) (groups []string, err error) {
+	return
+}
+
+//
+// Validate that the importer supports disk transfer
+// bandwidth throttling. Not supported by the imageio importer.
+func (r *Validator) BandwidthLimit() (ok bool) {
+	return
+}
+
+//
+// Validate that the importer supports limiting the number
+// of disks transferred concurrently. The DataVolumes are
+// created (and imported) together by the VMIO import.
+func (r *Validator) DisksPerVM() (ok bool) {
+	return
+}
+
+//
+// Validate that the importer supports the target disk format.
+// The disks are converted to raw by the importer (CDI).
+func (r *Validator) DiskFormat(format string) (ok bool) {
+	ok = format == api.DiskFormatRaw
+	return
+}
+
+//
+// Find VM devices which cannot be migrated.
+// Host devices are not collected for oVirt.
+func (r *Validator) UnsupportedDevices(vmRef ref.Ref) (kinds []string, err error) {
+	return
+}
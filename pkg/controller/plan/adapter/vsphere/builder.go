@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"regexp"
 
 	libcnd "github.com/konveyor/controller/pkg/condition"
 	liberr "github.com/konveyor/controller/pkg/error"
@@ -13,6 +12,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	vsphere "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
@@ -20,17 +20,14 @@ import (
 	"github.com/vmware/govmomi/vim25/types"
 	"gopkg.in/yaml.v2"
 	core "k8s.io/api/core/v1"
+	cnv "kubevirt.io/client-go/api/v1"
 	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
 	liburl "net/url"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
 )
 
-//
-// Regex which matches the snapshot identifier suffix of a
-// vSphere disk backing file.
-var backingFilePattern = regexp.MustCompile("-\\d+.vmdk")
-
 //
 // vSphere builder.
 type Builder struct {
@@ -100,7 +97,7 @@ func (r *Builder) Import(vmRef ref.Ref, object *vmio.VirtualMachineImportSpec) (
 				pErr.Error()))
 		return
 	}
-	if vm.IsTemplate {
+	if vm.IsTemplate && !r.Plan.Spec.MigrateTemplates {
 		err = liberr.New(
 			fmt.Sprintf(
 				"VM %s is a template",
@@ -114,7 +111,12 @@ func (r *Builder) Import(vmRef ref.Ref, object *vmio.VirtualMachineImportSpec) (
 				vmRef.String()))
 		return
 	}
-	if r.Plan.Spec.Warm && !vm.ChangeTrackingEnabled {
+	planVM, found := r.Plan.Spec.FindVM(vmRef)
+	if !found {
+		planVM = &plan.VM{}
+	}
+	warm := r.Plan.Spec.VMWarm(planVM)
+	if warm && !vm.ChangeTrackingEnabled {
 		err = liberr.New(
 			fmt.Sprintf(
 				"Changed Block Tracking (CBT) is disabled for VM %s",
@@ -123,9 +125,10 @@ func (r *Builder) Import(vmRef ref.Ref, object *vmio.VirtualMachineImportSpec) (
 	}
 	uuid := vm.UUID
 	object.TargetVMName = &vm.Name
-	if !r.Plan.Spec.Warm {
+	if !warm {
 		// object.StartVM left nil during a warm migration so that VMIO can manage it.
-		start := vm.PowerState == string(types.VirtualMachinePowerStatePoweredOn)
+		// Templates are never started.
+		start := !vm.IsTemplate && vm.PowerState == string(types.VirtualMachinePowerStatePoweredOn)
 		object.StartVM = &start
 	}
 	object.Source.Vmware = &vmio.VirtualMachineImportVmwareSourceSpec{
@@ -133,7 +136,7 @@ func (r *Builder) Import(vmRef ref.Ref, object *vmio.VirtualMachineImportSpec) (
 			ID: &uuid,
 		},
 	}
-	object.Source.Vmware.Mappings, err = r.mapping(vm)
+	object.Source.Vmware.Mappings, err = r.mapping(vm, planVM)
 	if err != nil {
 		return
 	}
@@ -141,6 +144,13 @@ func (r *Builder) Import(vmRef ref.Ref, object *vmio.VirtualMachineImportSpec) (
 	return
 }
 
+//
+// The disk transfer backend targeted by the tasks.
+// Disks are transferred by CDI and converted by virt-v2v.
+func (r *Builder) TransferBackend(_ ref.Ref) string {
+	return plan.BackendCDI
+}
+
 //
 // Build tasks.
 func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
@@ -154,28 +164,234 @@ func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
 				pErr.Error()))
 		return
 	}
+	planVM, planned := r.Plan.Spec.FindVM(vmRef)
+	if !planned {
+		planVM = &plan.VM{}
+	}
+	assigned, err := r.diskClasses(vm, planVM)
+	if err != nil {
+		return
+	}
 	for _, disk := range vm.Disks {
+		if r.Plan.Spec.SkipSharedDisks && r.shared(&disk.Disk) {
+			continue
+		}
+		name := vsphere.TrimBackingFileName(disk.File)
+		if planVM.Excluded(name) {
+			continue
+		}
 		mB := disk.Capacity / 0x100000
-		list = append(
-			list,
-			&plan.Task{
-				Name: r.trimBackingFileName(disk.File),
-				Progress: libitr.Progress{
-					Total: mB,
-				},
-				Annotations: map[string]string{
-					"unit": "MB",
-				},
-			})
+		task := &plan.Task{
+			Name: name,
+			Progress: libitr.Progress{
+				Total: mB,
+			},
+			Annotations: map[string]string{
+				"unit": "MB",
+			},
+		}
+		if destination, found := assigned[disk.ID]; found {
+			task.Annotations[plan.AnnStorageClass] = destination.StorageClass
+		}
+		list = append(list, task)
+	}
+
+	return
+}
+
+//
+// Find shared (and RDM) disks which cannot be migrated.
+func (r *Builder) SharedDisks(vmRef ref.Ref) (list []string, err error) {
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	for _, disk := range vm.Disks {
+		if r.shared(&disk.Disk) {
+			list = append(list, vsphere.TrimBackingFileName(disk.File))
+		}
+	}
+
+	return
+}
+
+//
+// Find the source VM power state.
+func (r *Builder) PowerState(vmRef ref.Ref) (state string, err error) {
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	state = plan.PowerOff
+	if vm.PowerState == string(types.VirtualMachinePowerStatePoweredOn) {
+		state = plan.PowerOn
+	}
+
+	return
+}
+
+//
+// Build the target VM CPU topology and placement.
+// CPU (or NUMA node) affinity is translated to
+// dedicated CPU placement.
+func (r *Builder) CPU(vmRef ref.Ref, object *cnv.CPU) (err error) {
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	cores := vm.CoresPerSocket
+	if cores < 1 {
+		cores = 1
+	}
+	object.Cores = uint32(cores)
+	object.Sockets = uint32(vm.CpuCount / cores)
+	if object.Sockets < 1 {
+		object.Sockets = 1
+	}
+	object.DedicatedCPUPlacement = len(vm.CpuAffinity) > 0 || len(vm.NumaNodeAffinity) > 0
+
+	return
+}
+
+//
+// Find the source networks and datastores used by the VM
+// which are not mapped. Network overrides on the plan VM,
+// excluded (and skipped shared) disks are considered.
+func (r *Builder) Unmapped(vmRef ref.Ref) (list []string, err error) {
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	planVM, planned := r.Plan.Spec.FindVM(vmRef)
+	if !planned {
+		planVM = &plan.VM{}
+	}
+	checked := map[string]bool{}
+	for _, net := range vm.Networks {
+		if checked[net.ID] {
+			continue
+		}
+		checked[net.ID] = true
+		if _, found := planVM.FindNetwork(net.ID); found {
+			continue
+		}
+		netMap := r.Context.Map.Network
+		if netMap != nil && netMap.Status.Refs.Find(ref.Ref{ID: net.ID}) {
+			continue
+		}
+		name := net.ID
+		network := &model.Network{}
+		if r.Source.Inventory.Get(network, net.ID) == nil {
+			name = network.Name
+		}
+		list = append(list, "network/"+name)
+	}
+	for _, disk := range vm.Disks {
+		if r.Plan.Spec.SkipSharedDisks && r.shared(&disk.Disk) {
+			continue
+		}
+		if planVM.Excluded(vsphere.TrimBackingFileName(disk.File)) {
+			continue
+		}
+		if checked[disk.Datastore.ID] {
+			continue
+		}
+		checked[disk.Datastore.ID] = true
+		dsMap := r.Context.Map.Storage
+		if dsMap != nil && dsMap.Status.Refs.Find(ref.Ref{ID: disk.Datastore.ID}) {
+			continue
+		}
+		name := disk.Datastore.ID
+		ds := &model.Datastore{}
+		if r.Source.Inventory.Get(ds, disk.Datastore.ID) == nil {
+			name = ds.Name
+		}
+		list = append(list, "datastore/"+name)
+	}
+
+	return
+}
+
+//
+// Find the source host and datastores in maintenance mode.
+func (r *Builder) MaintenanceMode(vmRef ref.Ref) (list []string, err error) {
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	host, err := r.host(vm.Host)
+	if err != nil {
+		return
+	}
+	if host.InMaintenanceMode {
+		list = append(list, "host/"+host.Name)
+	}
+	checked := map[string]bool{}
+	for _, disk := range vm.Disks {
+		if checked[disk.Datastore.ID] {
+			continue
+		}
+		checked[disk.Datastore.ID] = true
+		ds := &model.Datastore{}
+		pErr = r.Source.Inventory.Get(ds, disk.Datastore.ID)
+		if pErr != nil {
+			err = liberr.New(
+				fmt.Sprintf(
+					"Datastore %s lookup failed: %s",
+					disk.Datastore.ID,
+					pErr.Error()))
+			return
+		}
+		switch ds.MaintenanceMode {
+		case "", string(types.DatastoreSummaryMaintenanceModeStateNormal):
+		default:
+			list = append(list, "datastore/"+ds.Name)
+		}
 	}
 
 	return
 }
 
+//
+// Disk is shared or RDM.
+func (r *Builder) shared(disk *vsphere.Disk) bool {
+	return disk.Shared || disk.RDM
+}
+
 //
 // Return a stable identifier for a VDDK DataVolume.
 func (r *Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
-	return r.trimBackingFileName(dv.Spec.Source.VDDK.BackingFile)
+	return vsphere.TrimBackingFileName(dv.Spec.Source.VDDK.BackingFile)
 }
 
 //
@@ -314,29 +530,41 @@ func (r *Builder) host(hostID string) (host *model.Host, err error) {
 
 //
 // Build the VMIO ResourceMapping CR.
-func (r *Builder) mapping(vm *model.VM) (out *vmio.VmwareMappings, err error) {
+// The VM (disk and network) overrides take precedence
+// over the plan mappings.
+func (r *Builder) mapping(vm *model.VM, planVM *plan.VM) (out *vmio.VmwareMappings, err error) {
 	netMap := []vmio.NetworkResourceMappingItem{}
 	dsMap := []vmio.StorageResourceMappingItem{}
-	netMapIn := r.Context.Map.Network.Spec.Map
-	for i := range netMapIn {
-		mapped := &netMapIn[i]
-		ref := mapped.Source
+	diskMap := []vmio.StorageResourceMappingItem{}
+	netDestination, err := r.networkDestinations(planVM)
+	if err != nil {
+		return
+	}
+	// Each (distinct) network used by the VM NICs is
+	// mapped to its own destination. An unmapped network
+	// is an error rather than a dropped interface.
+	netMapped := map[string]bool{}
+	for _, net := range vm.Networks {
+		if netMapped[net.ID] {
+			continue
+		}
+		netMapped[net.ID] = true
+		destination, found := netDestination[net.ID]
+		if !found {
+			err = liberr.New(
+				"VM network not mapped.",
+				"vm",
+				vm.ID,
+				"network",
+				net.ID)
+			return
+		}
 		network := &model.Network{}
-		fErr := r.Source.Inventory.Find(network, ref)
+		fErr := r.Source.Inventory.Find(network, ref.Ref{ID: net.ID})
 		if fErr != nil {
 			err = fErr
 			return
 		}
-		needed := false
-		for _, net := range vm.Networks {
-			if net.ID == network.ID {
-				needed = true
-				break
-			}
-		}
-		if !needed {
-			continue
-		}
 		id, pErr := r.networkID(vm, network)
 		if pErr != nil {
 			err = pErr
@@ -349,10 +577,10 @@ func (r *Builder) mapping(vm *model.VM) (out *vmio.VmwareMappings, err error) {
 					ID: &id,
 				},
 				Target: vmio.ObjectIdentifier{
-					Namespace: &mapped.Destination.Namespace,
-					Name:      mapped.Destination.Name,
+					Namespace: &destination.Namespace,
+					Name:      destination.Name,
 				},
-				Type: &mapped.Destination.Type,
+				Type: &destination.Type,
 			})
 	}
 	dsMapIn := r.Context.Map.Storage.Spec.Map
@@ -380,7 +608,8 @@ func (r *Builder) mapping(vm *model.VM) (out *vmio.VmwareMappings, err error) {
 			err = pErr
 			return
 		}
-		mErr := r.defaultModes(&mapped.Destination)
+		destination := mapped.Destination
+		mErr := r.defaultModes(&destination)
 		if mErr != nil {
 			err = mErr
 			return
@@ -390,21 +619,159 @@ func (r *Builder) mapping(vm *model.VM) (out *vmio.VmwareMappings, err error) {
 				ID: &id,
 			},
 			Target: vmio.ObjectIdentifier{
-				Name: mapped.Destination.StorageClass,
+				Name: destination.StorageClass,
 			},
 		}
-		if mapped.Destination.VolumeMode != "" {
-			item.VolumeMode = &mapped.Destination.VolumeMode
+		if destination.VolumeMode != "" {
+			item.VolumeMode = &destination.VolumeMode
+		}
+		if destination.AccessMode != "" {
+			item.AccessMode = &destination.AccessMode
 		}
-		/* VMIO > 0.2.5 needed.
-		if mapped.Destination.AccessMode != "" {
-			item = &mapped.Destination.AccessMode
-		}*/
 		dsMap = append(dsMap, item)
 	}
+	for i := range vm.Disks {
+		disk := &vm.Disks[i]
+		override, found := planVM.FindDisk(vsphere.TrimBackingFileName(disk.File))
+		if !found {
+			continue
+		}
+		destination := api.DestinationStorage{
+			StorageClass: override.StorageClass,
+			VolumeMode:   override.VolumeMode,
+			AccessMode:   override.AccessMode,
+		}
+		mErr := r.defaultModes(&destination)
+		if mErr != nil {
+			err = mErr
+			return
+		}
+		item := vmio.StorageResourceMappingItem{
+			Source: vmio.Source{
+				ID: &disk.ID,
+			},
+			Target: vmio.ObjectIdentifier{
+				Name: destination.StorageClass,
+			},
+		}
+		if destination.VolumeMode != "" {
+			item.VolumeMode = &destination.VolumeMode
+		}
+		if destination.AccessMode != "" {
+			item.AccessMode = &destination.AccessMode
+		}
+		diskMap = append(diskMap, item)
+	}
+	assigned, err := r.diskClasses(vm, planVM)
+	if err != nil {
+		return
+	}
+	for i := range vm.Disks {
+		disk := &vm.Disks[i]
+		destination, found := assigned[disk.ID]
+		if !found {
+			continue
+		}
+		mErr := r.defaultModes(&destination)
+		if mErr != nil {
+			err = mErr
+			return
+		}
+		item := vmio.StorageResourceMappingItem{
+			Source: vmio.Source{
+				ID: &disk.ID,
+			},
+			Target: vmio.ObjectIdentifier{
+				Name: destination.StorageClass,
+			},
+		}
+		if destination.VolumeMode != "" {
+			item.VolumeMode = &destination.VolumeMode
+		}
+		if destination.AccessMode != "" {
+			item.AccessMode = &destination.AccessMode
+		}
+		diskMap = append(diskMap, item)
+	}
 	out = &vmio.VmwareMappings{
 		NetworkMappings: &netMap,
 		StorageMappings: &dsMap,
+		DiskMappings:    &diskMap,
+	}
+
+	return
+}
+
+//
+// Destination storage assigned to disks keyed by disk ID.
+// The disks on a datastore mapped to multiple storage classes are
+// assigned to the classes round-robin in the order of the disk ID
+// so that the assignment is deterministic. Disks with an override
+// are not assigned.
+func (r *Builder) diskClasses(vm *model.VM, planVM *plan.VM) (assigned map[string]api.DestinationStorage, err error) {
+	assigned = map[string]api.DestinationStorage{}
+	if r.Context.Map.Storage == nil {
+		return
+	}
+	dsMapIn := r.Context.Map.Storage.Spec.Map
+	for i := range dsMapIn {
+		mapped := &dsMapIn[i]
+		if len(mapped.Destination.Classes()) < 2 {
+			continue
+		}
+		ds := &model.Datastore{}
+		fErr := r.Source.Inventory.Find(ds, mapped.Source)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		ids := []string{}
+		for _, disk := range vm.Disks {
+			if disk.Datastore.ID != ds.ID {
+				continue
+			}
+			if _, found := planVM.FindDisk(vsphere.TrimBackingFileName(disk.File)); found {
+				continue
+			}
+			ids = append(ids, disk.ID)
+		}
+		sort.Strings(ids)
+		for n, id := range ids {
+			assigned[id] = mapped.Destination.Assign(n)
+		}
+	}
+
+	return
+}
+
+//
+// Network destinations keyed by (source) network ID.
+// The plan network mapping with the VM overrides applied.
+func (r *Builder) networkDestinations(planVM *plan.VM) (destinations map[string]api.DestinationNetwork, err error) {
+	destinations = map[string]api.DestinationNetwork{}
+	netMapIn := r.Context.Map.Network.Spec.Map
+	for i := range netMapIn {
+		mapped := &netMapIn[i]
+		network := &model.Network{}
+		fErr := r.Source.Inventory.Find(network, mapped.Source)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		destinations[network.ID] = mapped.Destination
+	}
+	for _, m := range planVM.Networks {
+		network := &model.Network{}
+		fErr := r.Source.Inventory.Find(network, m.Source)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		destinations[network.ID] = api.DestinationNetwork{
+			Type:      m.Type,
+			Namespace: m.Namespace,
+			Name:      m.Name,
+		}
 	}
 
 	return
@@ -490,7 +857,15 @@ func (r *Builder) esxHost(vm *model.VM) (esxHost *EsxHost, found bool, err error
 
 //
 // Set volume and access modes.
+// Modes not specified by the mapping default to the
+// plan modes and then to the storage class provisioner.
 func (r *Builder) defaultModes(dm *api.DestinationStorage) (err error) {
+	if dm.VolumeMode == "" {
+		dm.VolumeMode = r.Plan.Spec.VolumeMode
+	}
+	if dm.AccessMode == "" {
+		dm.AccessMode = r.Plan.Spec.AccessMode
+	}
 	model := &ocp.StorageClass{}
 	ref := ref.Ref{Name: dm.StorageClass}
 	err = r.Destination.Inventory.Find(model, ref)
@@ -513,12 +888,3 @@ func (r *Builder) defaultModes(dm *api.DestinationStorage) (err error) {
 
 	return
 }
-
-//
-// Trims the snapshot suffix from a disk backing file name if there is one.
-//	Example:
-// 	Input: 	[datastore13] my-vm/disk-name-000015.vmdk
-//	Output: [datastore13] my-vm/disk-name.vmdk
-func (r *Builder) trimBackingFileName(fileName string) string {
-	return backingFilePattern.ReplaceAllString(fileName, ".vmdk")
-}
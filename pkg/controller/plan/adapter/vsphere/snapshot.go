@@ -0,0 +1,190 @@
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	liberr "github.com/konveyor/controller/pkg/error"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"time"
+)
+
+//
+// Snapshot.
+const (
+	// Snapshot name.
+	SnapshotName = "forklift-migration"
+	// Snapshot (vCenter) request timeout.
+	SnapshotTimeout = time.Minute
+	// VM powered on.
+	PoweredOn = "poweredOn"
+	// Guest tools running.
+	ToolsRunning = "guestToolsRunning"
+)
+
+//
+// Quiesce the guest file systems of the source VM.
+// Requires VMware Tools running in the guest. The file systems
+// are synced by a quiesced snapshot which is removed. The task
+// which creates the snapshot is started (not waited on) and is
+// polled using QuiesceTask(). A VM not powered on is considered
+// quiesced and no task is started.
+// Returns quiesced=false when the tools are not running.
+func (r *Builder) Quiesce(vmRef ref.Ref) (task string, quiesced bool, err error) {
+	vm := &model.VM{}
+	pErr := r.Source.Inventory.Find(vm, vmRef)
+	if pErr != nil {
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s lookup failed: %s",
+				vmRef.String(),
+				pErr.Error()))
+		return
+	}
+	if vm.PowerState != PoweredOn {
+		quiesced = true
+		return
+	}
+	if vm.ToolsRunningStatus != ToolsRunning {
+		return
+	}
+	task, err = r.createSnapshot(vm)
+	if err != nil {
+		return
+	}
+	quiesced = true
+
+	return
+}
+
+//
+// Poll the quiesce task.
+// When the (quiesced) snapshot has been created, the task
+// which removes the snapshot is started and returned as the
+// next task to be polled.
+// Returns done=true when the snapshot has been removed.
+func (r *Builder) QuiesceTask(vmRef ref.Ref, task string) (next string, done bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), SnapshotTimeout)
+	defer cancel()
+	provider := &EsxHost{
+		URL:    r.Source.Provider.Spec.URL,
+		Secret: r.Source.Secret,
+	}
+	err = provider.connect(ctx)
+	if err != nil {
+		return
+	}
+	defer provider.close()
+	taskObject := mo.Task{}
+	err = property.DefaultCollector(provider.client.Client).RetrieveOne(
+		ctx,
+		types.ManagedObjectReference{
+			Type:  "Task",
+			Value: task,
+		},
+		[]string{"info"},
+		&taskObject)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	info := taskObject.Info
+	switch info.State {
+	case types.TaskInfoStateSuccess:
+	case types.TaskInfoStateError:
+		reason := ""
+		if info.Error != nil {
+			reason = info.Error.LocalizedMessage
+		}
+		err = liberr.New(
+			fmt.Sprintf(
+				"VM %s snapshot task %s failed: %s",
+				vmRef.String(),
+				task,
+				reason))
+		return
+	default:
+		next = task
+		return
+	}
+	snapshot, created := info.Result.(types.ManagedObjectReference)
+	if created && snapshot.Type == "VirtualMachineSnapshot" {
+		next, err = r.removeSnapshot(ctx, provider, snapshot.Value)
+		return
+	}
+
+	done = true
+
+	return
+}
+
+//
+// Start the task which creates a snapshot of the source VM.
+// The guest file system is quiesced when the VM is powered on
+// and the guest tools are running.
+// Returns the task ID.
+func (r *Builder) createSnapshot(vm *model.VM) (task string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), SnapshotTimeout)
+	defer cancel()
+	provider := &EsxHost{
+		URL:    r.Source.Provider.Spec.URL,
+		Secret: r.Source.Secret,
+	}
+	err = provider.connect(ctx)
+	if err != nil {
+		return
+	}
+	defer provider.close()
+	vmObject := object.NewVirtualMachine(
+		provider.client.Client,
+		types.ManagedObjectReference{
+			Type:  "VirtualMachine",
+			Value: vm.ID,
+		})
+	taskObject, err := vmObject.CreateSnapshot(
+		ctx,
+		SnapshotName,
+		fmt.Sprintf("Migration plan: %s/%s", r.Plan.Namespace, r.Plan.Name),
+		false,
+		vm.PowerState == PoweredOn && vm.ToolsRunningStatus == ToolsRunning)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	task = taskObject.Reference().Value
+
+	return
+}
+
+//
+// Start the task which removes a snapshot of the source VM.
+// The snapshot is consolidated into the (parent) disks.
+// Returns the task ID.
+func (r *Builder) removeSnapshot(ctx context.Context, provider *EsxHost, id string) (task string, err error) {
+	consolidate := true
+	response, err := methods.RemoveSnapshot_Task(
+		ctx,
+		provider.client.Client,
+		&types.RemoveSnapshot_Task{
+			This: types.ManagedObjectReference{
+				Type:  "VirtualMachineSnapshot",
+				Value: id,
+			},
+			RemoveChildren: false,
+			Consolidate:    &consolidate,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	task = response.Returnval.Value
+
+	return
+}
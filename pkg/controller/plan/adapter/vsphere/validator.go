@@ -4,8 +4,11 @@ import (
 	liberr "github.com/konveyor/controller/pkg/error"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	vsphere "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	"strings"
 )
 
 //
@@ -23,7 +26,7 @@ func (r *Validator) Load() (err error) {
 }
 
 //
-// Validate that a VM's networks have been mapped.
+// Validate that a VM's networks have been mapped (or overridden).
 func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 	if r.plan.Referenced.Map.Network == nil {
 		return
@@ -39,7 +42,13 @@ func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
+	planVM, _ := r.plan.Spec.FindVM(vmRef)
 	for _, net := range vm.Networks {
+		if planVM != nil {
+			if _, found := planVM.FindNetwork(net.ID); found {
+				continue
+			}
+		}
 		if !r.plan.Referenced.Map.Network.Status.Refs.Find(ref.Ref{ID: net.ID}) {
 			return
 		}
@@ -105,3 +114,279 @@ func (r *Validator) MaintenanceMode(vmRef ref.Ref) (ok bool, err error) {
 	ok = !host.InMaintenanceMode
 	return
 }
+
+//
+// Find the (enabled) DRS anti-affinity rules which include
+// the VM. Returns the rule names qualified by the cluster.
+func (r *Validator) AntiAffinity(vmRef ref.Ref) (groups []string, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	host := &model.Host{}
+	hostRef := ref.Ref{ID: vm.Host}
+	err = r.inventory.Find(host, hostRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"Host not found in inventory.",
+			"vm",
+			vmRef.String(),
+			"host",
+			hostRef.String())
+		return
+	}
+	if host.Cluster == "" {
+		return
+	}
+	cluster := &model.Cluster{}
+	clusterRef := ref.Ref{ID: host.Cluster}
+	err = r.inventory.Find(cluster, clusterRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"Cluster not found in inventory.",
+			"vm",
+			vmRef.String(),
+			"cluster",
+			clusterRef.String())
+		return
+	}
+	for _, rule := range cluster.AntiAffinity {
+		if !rule.Enabled {
+			continue
+		}
+		for _, ref := range rule.VMs {
+			if ref.ID == vm.ID {
+				groups = append(groups, cluster.Name+"/"+rule.Name)
+				break
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Validate that a VM's shared (and RDM) disks may be skipped
+// or the shared disks migrated.
+func (r *Validator) SharedDisks(vmRef ref.Ref) (ok bool, err error) {
+	if r.plan.Spec.SkipSharedDisks {
+		ok = true
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	for _, disk := range vm.Disks {
+		if disk.RDM || (disk.Shared && !r.plan.Spec.MigrateSharedDisks) {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+//
+// Validate that changed block tracking (CBT) is enabled.
+func (r *Validator) ChangeTracking(vmRef ref.Ref) (ok bool, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	ok = vm.ChangeTrackingEnabled
+	return
+}
+
+//
+// Validate that a VM's disk overrides reference disks on the VM.
+func (r *Validator) DiskOverrides(vmRef ref.Ref) (ok bool, err error) {
+	planVM, found := r.plan.Spec.FindVM(vmRef)
+	if !found || len(planVM.Disks) == 0 {
+		ok = true
+		return
+	}
+	disks, err := r.disks(vmRef)
+	if err != nil {
+		return
+	}
+	for _, m := range planVM.Disks {
+		if !disks[m.Disk] {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+//
+// Validate that a VM's excluded disks reference disks on the VM.
+func (r *Validator) ExcludedDisks(vmRef ref.Ref) (ok bool, err error) {
+	planVM, found := r.plan.Spec.FindVM(vmRef)
+	if !found || len(planVM.ExcludedDisks) == 0 {
+		ok = true
+		return
+	}
+	disks, err := r.disks(vmRef)
+	if err != nil {
+		return
+	}
+	for _, disk := range planVM.ExcludedDisks {
+		if !disks[disk] {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+//
+// The set of VM disk identifiers.
+func (r *Validator) disks(vmRef ref.Ref) (disks map[string]bool, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	disks = map[string]bool{}
+	for _, disk := range vm.Disks {
+		disks[vsphere.TrimBackingFileName(disk.File)] = true
+	}
+
+	return
+}
+
+//
+// Validate that the MTU of the source host VNIC used for disk
+// transfer does not exceed the specified MTU. The VNIC with the
+// IP address specified by the Host CR is used for disk transfer.
+// Otherwise, the management (vmk0) VNIC.
+func (r *Validator) TransferMTU(vmRef ref.Ref, mtu int32) (ok bool, sourceMTU int32, err error) {
+	ok = true
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	host := &model.Host{}
+	hostRef := ref.Ref{ID: vm.Host}
+	err = r.inventory.Find(host, hostRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"Host not found in inventory.",
+			"vm",
+			vmRef.String(),
+			"host",
+			hostRef.String())
+		return
+	}
+	ipAddress := ""
+	if found, hostCR := r.plan.Referenced.FindHost(host.ID, host.Name); found {
+		ipAddress = hostCR.Spec.IpAddress
+	}
+	var vNIC *vsphere.VNIC
+	for i := range host.Network.VNICs {
+		candidate := &host.Network.VNICs[i]
+		if ipAddress != "" {
+			if candidate.IpAddress == ipAddress {
+				vNIC = candidate
+				break
+			}
+			continue
+		}
+		if strings.HasSuffix(candidate.Key, "vmk0") {
+			vNIC = candidate
+			break
+		}
+	}
+	if vNIC == nil {
+		return
+	}
+	sourceMTU = vNIC.MTU
+	ok = sourceMTU <= mtu
+
+	return
+}
+
+//
+// Validate that the importer supports disk transfer
+// bandwidth throttling. Not supported by the VDDK importer.
+func (r *Validator) BandwidthLimit() (ok bool) {
+	return
+}
+
+//
+// Validate that the importer supports limiting the number
+// of disks transferred concurrently. The DataVolumes are
+// created (and imported) together by the VMIO import.
+func (r *Validator) DisksPerVM() (ok bool) {
+	return
+}
+
+//
+// Validate that the importer supports the target disk format.
+// The disks are converted to raw by the importer (CDI).
+func (r *Validator) DiskFormat(format string) (ok bool) {
+	ok = format == api.DiskFormatRaw
+	return
+}
+
+//
+// Find VM devices (GPU, passthrough, USB, serial) which
+// cannot be migrated. The (configurable) unsupported kinds
+// are defined by settings.
+func (r *Validator) UnsupportedDevices(vmRef ref.Ref) (kinds []string, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	unsupported := map[string]bool{}
+	for _, kind := range settings.Settings.Migration.UnsupportedDevices {
+		unsupported[kind] = true
+	}
+	found := map[string]bool{}
+	for _, device := range vm.Devices {
+		if unsupported[device.Kind] && !found[device.Kind] {
+			found[device.Kind] = true
+			kinds = append(kinds, device.Kind)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,133 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libref "github.com/konveyor/controller/pkg/ref"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"path"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//
+// Plan spec validation error.
+type SpecError struct {
+	// The (JSON) path of the field.
+	Field string `json:"field"`
+	// Reason: NotSet|NotUnique|NotFound.
+	Reason string `json:"reason"`
+	// Message.
+	Message string `json:"message"`
+}
+
+//
+// Error description.
+func (e SpecError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+//
+// Validate the plan spec.
+// Checks (only) for an empty VM list, duplicate VM refs,
+// a resolvable source provider and that the referenced
+// mappings exist. Intended to be run before any other
+// validation or work and may back a validating webhook.
+// The returned `err` is set only when validation could
+// not be performed.
+func ValidateSpec(kClient client.Client, plan *api.Plan) (errs []SpecError, err error) {
+	errs = []SpecError{}
+	//
+	// VM list.
+	if len(plan.Spec.VMs) == 0 {
+		errs = append(
+			errs,
+			SpecError{
+				Field:   "spec.vms",
+				Reason:  NotSet,
+				Message: "VM list is empty.",
+			})
+	}
+	// VMs are unique by source provider.
+	setOf := map[string]bool{}
+	for i := range plan.Spec.VMs {
+		ref := &plan.Spec.VMs[i].Ref
+		key := ref.ID
+		if key == "" {
+			key = ref.Name
+		}
+		if key == "" {
+			errs = append(
+				errs,
+				SpecError{
+					Field:   fmt.Sprintf("spec.vms[%d]", i),
+					Reason:  NotSet,
+					Message: "VM ref (id or name) not set.",
+				})
+			continue
+		}
+		provider := plan.Spec.VMProvider(&plan.Spec.VMs[i])
+		key = path.Join(provider.Namespace, provider.Name, key)
+		if setOf[key] {
+			errs = append(
+				errs,
+				SpecError{
+					Field:   fmt.Sprintf("spec.vms[%d]", i),
+					Reason:  NotUnique,
+					Message: fmt.Sprintf("VM ref `%s` is duplicated.", ref.String()),
+				})
+			continue
+		}
+		setOf[key] = true
+	}
+	//
+	// Referenced resources.
+	referenced := []struct {
+		field  string
+		ref    core.ObjectReference
+		object runtime.Object
+	}{
+		{"spec.provider.source", plan.Spec.Provider.Source, &api.Provider{}},
+		{"spec.map.network", plan.Spec.Map.Network, &api.NetworkMap{}},
+		{"spec.map.storage", plan.Spec.Map.Storage, &api.StorageMap{}},
+	}
+	for _, r := range referenced {
+		ref := r.ref
+		if !libref.RefSet(&ref) {
+			errs = append(
+				errs,
+				SpecError{
+					Field:   r.field,
+					Reason:  NotSet,
+					Message: "Reference not set.",
+				})
+			continue
+		}
+		gErr := kClient.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: ref.Namespace,
+				Name:      ref.Name,
+			},
+			r.object)
+		if gErr != nil {
+			if k8serr.IsNotFound(gErr) {
+				errs = append(
+					errs,
+					SpecError{
+						Field:   r.field,
+						Reason:  NotFound,
+						Message: fmt.Sprintf("`%s/%s` not found.", ref.Namespace, ref.Name),
+					})
+				continue
+			}
+			err = liberr.Wrap(gErr)
+			return
+		}
+	}
+
+	return
+}
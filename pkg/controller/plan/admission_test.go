@@ -0,0 +1,111 @@
+package plan
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+func TestValidateSpec(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	scheme := runtime.NewScheme()
+	err := api.SchemeBuilder.AddToScheme(scheme)
+	g.Expect(err).To(gomega.BeNil())
+
+	objectRef := func(name string) core.ObjectReference {
+		return core.ObjectReference{Namespace: "test", Name: name}
+	}
+	objectMeta := func(name string) meta.ObjectMeta {
+		return meta.ObjectMeta{Namespace: "test", Name: name}
+	}
+	kClient := fake.NewFakeClientWithScheme(
+		scheme,
+		&api.Provider{ObjectMeta: objectMeta("source")},
+		&api.NetworkMap{ObjectMeta: objectMeta("network")},
+		&api.StorageMap{ObjectMeta: objectMeta("storage")})
+	newPlan := func() *api.Plan {
+		p := &api.Plan{}
+		p.Spec.Provider.Source = objectRef("source")
+		p.Spec.Map.Network = objectRef("network")
+		p.Spec.Map.Storage = objectRef("storage")
+		p.Spec.VMs = []plan.VM{
+			{Ref: ref.Ref{ID: "vm-1"}},
+			{Ref: ref.Ref{Name: "vm2"}},
+		}
+		return p
+	}
+	reasons := func(errs []SpecError) (list []string) {
+		for _, e := range errs {
+			list = append(list, e.Field+"="+e.Reason)
+		}
+		return
+	}
+
+	// Valid.
+	errs, err := ValidateSpec(kClient, newPlan())
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(errs).To(gomega.BeEmpty())
+
+	// Empty VM list.
+	p := newPlan()
+	p.Spec.VMs = nil
+	errs, err = ValidateSpec(kClient, p)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(reasons(errs)).To(gomega.ConsistOf("spec.vms=NotSet"))
+
+	// Duplicate refs.
+	p = newPlan()
+	p.Spec.VMs = append(
+		p.Spec.VMs,
+		plan.VM{Ref: ref.Ref{ID: "vm-1"}},
+		plan.VM{Ref: ref.Ref{Name: "vm2"}})
+	errs, err = ValidateSpec(kClient, p)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(reasons(errs)).To(
+		gomega.ConsistOf(
+			"spec.vms[2]=NotUnique",
+			"spec.vms[3]=NotUnique"))
+
+	// Same ref on other source providers.
+	other := objectRef("other")
+	source := objectRef("source")
+	p = newPlan()
+	p.Spec.VMs = append(
+		p.Spec.VMs,
+		plan.VM{Ref: ref.Ref{ID: "vm-1"}, Provider: &other},
+		plan.VM{Ref: ref.Ref{ID: "vm-1"}, Provider: &source})
+	errs, err = ValidateSpec(kClient, p)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(reasons(errs)).To(gomega.ConsistOf("spec.vms[3]=NotUnique"))
+
+	// Source provider not set.
+	p = newPlan()
+	p.Spec.Provider.Source = core.ObjectReference{}
+	errs, err = ValidateSpec(kClient, p)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(reasons(errs)).To(gomega.ConsistOf("spec.provider.source=NotSet"))
+
+	// Source provider not found.
+	p = newPlan()
+	p.Spec.Provider.Source = objectRef("missing")
+	errs, err = ValidateSpec(kClient, p)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(reasons(errs)).To(gomega.ConsistOf("spec.provider.source=NotFound"))
+
+	// Mappings not found.
+	p = newPlan()
+	p.Spec.Map.Network = objectRef("missing")
+	p.Spec.Map.Storage = objectRef("missing")
+	errs, err = ValidateSpec(kClient, p)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(reasons(errs)).To(
+		gomega.ConsistOf(
+			"spec.map.network=NotFound",
+			"spec.map.storage=NotFound"))
+}
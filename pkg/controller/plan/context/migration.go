@@ -93,6 +93,29 @@ func (r *Context) build() (err error) {
 	return
 }
 
+//
+// A copy of the context for an additional source.
+// The copy has the source provider and maps of the
+// additional source. The plan is shared.
+func (r *Context) WithSource(source *api.ReferencedSource) (ctx *Context, err error) {
+	copied := *r
+	copied.Map.Network = source.Map.Network
+	copied.Map.Storage = source.Map.Storage
+	if copied.Map.Network == nil || copied.Map.Storage == nil {
+		err = liberr.Wrap(NotEnoughDataError{})
+		return
+	}
+	copied.Source = Source{}
+	err = copied.Source.with(&copied, source.Provider)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	ctx = &copied
+
+	return
+}
+
 //
 // Set the migration.
 // This will update the logger context.
@@ -124,7 +147,16 @@ type Source struct {
 // Returns: NotEnoughDataError when:
 //   Plan.Referenced.Source is not complete.
 func (r *Source) build(ctx *Context) (err error) {
-	r.Provider = ctx.Plan.Referenced.Provider.Source
+	err = r.with(ctx, ctx.Plan.Referenced.Provider.Source)
+	return
+}
+
+//
+// Build using the specified provider.
+// Returns: NotEnoughDataError when:
+//   The provider is nil.
+func (r *Source) with(ctx *Context, provider *api.Provider) (err error) {
+	r.Provider = provider
 	if r.Provider == nil {
 		err = liberr.Wrap(NotEnoughDataError{})
 		return
@@ -178,6 +178,12 @@ func (r Reconciler) Reconcile(request reconcile.Request) (result reconcile.Resul
 		names.SimpleNameGenerator.GenerateName(Name+"|"),
 		"plan",
 		request)
+	// Not started while shutting down.
+	if !Shutdown.Begin() {
+		r.Log.Info("Shutting down; reconcile not started.")
+		return
+	}
+	defer Shutdown.End()
 	r.Started()
 	defer func() {
 		result.RequeueAfter = r.Ended(
@@ -219,6 +225,17 @@ func (r Reconciler) Reconcile(request reconcile.Request) (result reconcile.Resul
 		return
 	}
 
+	// Paused condition.
+	if plan.Spec.Paused {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     Paused,
+			Status:   True,
+			Category: Advisory,
+			Reason:   UserRequested,
+			Message:  "The plan execution is PAUSED.",
+		})
+	}
+
 	// Ready condition.
 	if !plan.Status.HasBlockerCondition() {
 		plan.Status.SetCondition(libcnd.Condition{
@@ -323,10 +340,17 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 	//
 	// Cancel.
 	runner := Migration{Context: ctx}
-	err = runner.Cancel()
+	cleanupPending, err := runner.Cancel()
 	if err != nil {
 		return
 	}
+	if cleanupPending {
+		defer func() {
+			if reQ == NoReQ {
+				reQ = base.FastReQ
+			}
+		}()
+	}
 	//
 	// Find pending migrations.
 	pending := []*api.Migration{}
@@ -84,17 +84,20 @@ func (r *Handler) changed(vm *ocp.VM) {
 		if !r.MatchProvider(ref) {
 			continue
 		}
-		if plan.Spec.TargetNamespace == vm.Namespace {
-			log.V(3).Info(
-				"Queue reconcile event.",
-				"plan",
-				path.Join(
-					plan.Namespace,
-					plan.Name))
-			r.Enqueue(event.GenericEvent{
-				Meta:   &plan.ObjectMeta,
-				Object: plan,
-			})
+		for _, ns := range plan.Spec.Namespaces() {
+			if ns == vm.Namespace {
+				log.V(3).Info(
+					"Queue reconcile event.",
+					"plan",
+					path.Join(
+						plan.Namespace,
+						plan.Name))
+				r.Enqueue(event.GenericEvent{
+					Meta:   &plan.ObjectMeta,
+					Object: plan,
+				})
+				break
+			}
 		}
 	}
 }
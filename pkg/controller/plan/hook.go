@@ -3,6 +3,7 @@ package plan
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	libcnd "github.com/konveyor/controller/pkg/condition"
 	liberr "github.com/konveyor/controller/pkg/error"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
@@ -81,6 +82,59 @@ func (r *HookRunner) Run(vm *planapi.VMStatus) (err error) {
 	return
 }
 
+//
+// Validate the hooks referenced by the VM.
+// The hook must exist and specify an image. The playbook
+// (when specified) must be base64 encoded YAML.
+// Returns: a reason for each hook not valid.
+func (r *HookRunner) Validate(vm *planapi.VM) (reasons []string) {
+	for i := range vm.Hooks {
+		ref := &vm.Hooks[i]
+		hook, found := r.FindHook(ref.Hook)
+		if !found {
+			reasons = append(
+				reasons,
+				fmt.Sprintf(
+					"Hook %s not found.",
+					ref.String()))
+			continue
+		}
+		if hook.Spec.Image == "" {
+			reasons = append(
+				reasons,
+				fmt.Sprintf(
+					"Hook %s image not specified.",
+					ref.String()))
+			continue
+		}
+		r.hook = hook
+		playbook, err := r.playbook()
+		if err != nil {
+			reasons = append(
+				reasons,
+				fmt.Sprintf(
+					"Hook %s playbook not base64 encoded.",
+					ref.String()))
+			continue
+		}
+		if len(playbook) == 0 {
+			continue
+		}
+		parsed := []interface{}{}
+		err = yaml.Unmarshal([]byte(playbook), &parsed)
+		if err != nil {
+			reasons = append(
+				reasons,
+				fmt.Sprintf(
+					"Hook %s playbook not valid: %s",
+					ref.String(),
+					err.Error()))
+		}
+	}
+
+	return
+}
+
 //
 // Ensure the job.
 func (r *HookRunner) ensureJob() (job *batch.Job, err error) {
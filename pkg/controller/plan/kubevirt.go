@@ -2,10 +2,13 @@ package plan
 
 import (
 	"context"
+	"fmt"
 	"path"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	libcnd "github.com/konveyor/controller/pkg/condition"
 	liberr "github.com/konveyor/controller/pkg/error"
@@ -14,10 +17,14 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1alpha1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	cnv "kubevirt.io/client-go/api/v1"
 	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -30,6 +37,17 @@ const (
 	annDefaultNetwork = "v1.multus-cni.io/default-network"
 )
 
+// Importer pod name prefix.
+const (
+	importerPrefix = "importer-"
+)
+
+// Grace period for the (normal) deletion of import
+// resources before they are force deleted.
+const (
+	ForceCleanupGrace = time.Minute * 2
+)
+
 // Labels
 const (
 	// migration label (value=UID)
@@ -72,13 +90,28 @@ func (r *KubeVirt) ImportMap() (mp ImportMap, err error) {
 // Each VmImport represents a VMIO VirtualMachineImport
 // with associated DataVolumes.
 func (r *KubeVirt) ListImports() ([]VmImport, error) {
+	list := []VmImport{}
+	for _, ns := range r.Plan.Spec.Namespaces() {
+		nsList, err := r.listImports(ns)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, nsList...)
+	}
+
+	return list, nil
+}
+
+//
+// List import CRs in the specified namespace.
+func (r *KubeVirt) listImports(namespace string) ([]VmImport, error) {
 	vList := &vmio.VirtualMachineImportList{}
 	err := r.Destination.Client.List(
 		context.TODO(),
 		vList,
 		&client.ListOptions{
 			LabelSelector: labels.SelectorFromSet(r.planLabels()),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     namespace,
 		},
 	)
 	if err != nil {
@@ -98,7 +131,7 @@ func (r *KubeVirt) ListImports() ([]VmImport, error) {
 		context.TODO(),
 		dvList,
 		&client.ListOptions{
-			Namespace: r.Plan.Spec.TargetNamespace,
+			Namespace: namespace,
 		},
 	)
 	if err != nil {
@@ -124,7 +157,7 @@ func (r *KubeVirt) ListImports() ([]VmImport, error) {
 //
 // Create the VMIO CR on the destination.
 func (r *KubeVirt) EnsureImport(vm *plan.VMStatus) (err error) {
-	secret, err := r.ensureSecret(vm.Ref)
+	secret, err := r.ensureSecret(&vm.VM)
 	if err != nil {
 		return
 	}
@@ -138,7 +171,7 @@ func (r *KubeVirt) EnsureImport(vm *plan.VMStatus) (err error) {
 		list,
 		&client.ListOptions{
 			LabelSelector: labels.SelectorFromSet(r.vmLabels(vm.Ref)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.Spec.VMNamespace(&vm.VM),
 		},
 	)
 	if err != nil {
@@ -169,18 +202,34 @@ func (r *KubeVirt) EnsureImport(vm *plan.VMStatus) (err error) {
 	} else {
 		vmImport = newImport
 		err = r.Destination.Client.Create(context.TODO(), vmImport)
-		if err != nil {
-			err = liberr.Wrap(err)
-			return
+		if err == nil {
+			r.Log.Info(
+				"Created VM Import.",
+				"import",
+				path.Join(
+					vmImport.Namespace,
+					vmImport.Name),
+				"vm",
+				vm.String())
+		} else {
+			// Created (by a previous reconcile) but not listed.
+			if !k8serr.IsAlreadyExists(err) {
+				err = liberr.Wrap(err)
+				return
+			}
+			vmImport = &vmio.VirtualMachineImport{}
+			err = r.Destination.Client.Get(
+				context.TODO(),
+				client.ObjectKey{
+					Namespace: newImport.Namespace,
+					Name:      newImport.Name,
+				},
+				vmImport)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
 		}
-		r.Log.Info(
-			"Created VM Import.",
-			"import",
-			path.Join(
-				vmImport.Namespace,
-				vmImport.Name),
-			"vm",
-			vm.String())
 	}
 	err = k8sutil.SetOwnerReference(vmImport, secret, scheme.Scheme)
 	if err != nil {
@@ -205,7 +254,7 @@ func (r *KubeVirt) DeleteImport(vm *plan.VMStatus) (err error) {
 		list,
 		&client.ListOptions{
 			LabelSelector: labels.SelectorFromSet(r.vmLabels(vm.Ref)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.Spec.VMNamespace(&vm.VM),
 		},
 	)
 	if err != nil {
@@ -235,19 +284,890 @@ func (r *KubeVirt) DeleteImport(vm *plan.VMStatus) (err error) {
 	return
 }
 
+//
+// Delete the target VM and DataVolumes created by the
+// VMIO CR for the migration on the destination. Only objects
+// reported on the import status are deleted so that objects
+// not created by the migration are never deleted. Objects
+// which have not been created (or already deleted) are ignored.
+func (r *KubeVirt) DeleteVM(vm *plan.VMStatus) (err error) {
+	vmImport, found, err := r.findImport(vm)
+	if err != nil || !found {
+		return
+	}
+	objects := []metaObject{}
+	if vmImport.Status.TargetVMName != "" {
+		objects = append(
+			objects,
+			metaObject{
+				Object: &cnv.VirtualMachine{},
+				name:   vmImport.Status.TargetVMName,
+			})
+	}
+	for _, dv := range vmImport.Status.DataVolumes {
+		objects = append(
+			objects,
+			metaObject{
+				Object: &cdi.DataVolume{},
+				name:   dv.Name,
+			})
+	}
+	for _, object := range objects {
+		object.Object.SetNamespace(vmImport.Namespace)
+		object.Object.SetName(object.name)
+		err = r.Destination.Client.Delete(context.TODO(), object.Object)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info(
+			"Deleted target object.",
+			"object",
+			path.Join(
+				vmImport.Namespace,
+				object.name),
+			"kind",
+			reflect.TypeOf(object.Object).Elem().Name(),
+			"vm",
+			vm.String())
+	}
+
+	return
+}
+
+//
+// Delete the VMIO CR and the DataVolumes not used by the
+// target VM after the migration has succeeded. The DataVolumes
+// used by the target VM are kept and no longer owned by the
+// import so they are not (cascade) deleted with the import.
+func (r *KubeVirt) CleanupImport(vm *plan.VMStatus) (err error) {
+	list, err := r.listImports(r.Plan.Spec.VMNamespace(&vm.VM))
+	if err != nil {
+		return
+	}
+	for _, vmImport := range list {
+		if vmImport.Labels[kVM] != vm.ID {
+			continue
+		}
+		var used map[string]bool
+		used, err = r.targetVolumes(vmImport.VirtualMachineImport)
+		if err != nil {
+			return
+		}
+		for _, dv := range vmImport.DataVolumes {
+			if used[dv.Name] {
+				err = r.disown(dv.DataVolume, vmImport.VirtualMachineImport)
+				if err != nil {
+					return
+				}
+				continue
+			}
+			err = r.Destination.Client.Delete(context.TODO(), dv.DataVolume)
+			if err != nil {
+				if k8serr.IsNotFound(err) {
+					err = nil
+					continue
+				}
+				err = liberr.Wrap(err)
+				return
+			}
+			r.Log.Info(
+				"Cleanup: deleted DataVolume.",
+				"dv",
+				path.Join(
+					dv.Namespace,
+					dv.Name),
+				"vm",
+				vm.String())
+		}
+		err = r.Destination.Client.Delete(context.TODO(), vmImport.VirtualMachineImport)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info(
+			"Cleanup: deleted VM Import.",
+			"import",
+			path.Join(
+				vmImport.Namespace,
+				vmImport.Name),
+			"vm",
+			vm.String())
+	}
+
+	return
+}
+
+//
+// The names of the DataVolumes (and PVCs) used by
+// the target VM created by the import.
+func (r *KubeVirt) targetVolumes(vmImport *vmio.VirtualMachineImport) (used map[string]bool, err error) {
+	used = map[string]bool{}
+	if vmImport.Status.TargetVMName == "" {
+		return
+	}
+	object := &cnv.VirtualMachine{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: vmImport.Namespace,
+			Name:      vmImport.Status.TargetVMName,
+		},
+		object)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	for _, template := range object.Spec.DataVolumeTemplates {
+		used[template.Name] = true
+	}
+	if object.Spec.Template == nil {
+		return
+	}
+	for _, volume := range object.Spec.Template.Spec.Volumes {
+		switch {
+		case volume.DataVolume != nil:
+			used[volume.DataVolume.Name] = true
+		case volume.PersistentVolumeClaim != nil:
+			used[volume.PersistentVolumeClaim.ClaimName] = true
+		}
+	}
+
+	return
+}
+
+//
+// Use the DataVolumes transferred by the owner VMs for the
+// shared disks on the target VM. The target VM volumes which
+// reference the DataVolumes created by the import for the
+// shared disks are replaced and the (duplicate) DataVolumes
+// are deleted. The owner and dependent VMs are migrated to
+// the same namespace.
+func (r *KubeVirt) AttachSharedDisks(vm *plan.VMStatus) (err error) {
+	list, err := r.listImports(r.Plan.Spec.VMNamespace(&vm.VM))
+	if err != nil {
+		return
+	}
+	imports := map[string]VmImport{}
+	for _, vmImport := range list {
+		imports[vmImport.Labels[kVM]] = vmImport
+	}
+	dependent, found := imports[vm.ID]
+	if !found || dependent.Status.TargetVMName == "" {
+		err = liberr.New("VM import not found.")
+		return
+	}
+	replaced := map[string]string{}
+	for _, dv := range dependent.DataVolumes {
+		disk := r.Builder.ResolveDataVolumeIdentifier(dv.DataVolume)
+		ownerID, shared := vm.SharedDisks[disk]
+		if !shared || ownerID == vm.ID {
+			continue
+		}
+		owner, found := imports[ownerID]
+		if !found {
+			err = liberr.New(
+				fmt.Sprintf(
+					"Import for VM %s (owner of shared disk %s) not found.",
+					ownerID,
+					disk))
+			return
+		}
+		for _, ownerDv := range owner.DataVolumes {
+			if r.Builder.ResolveDataVolumeIdentifier(ownerDv.DataVolume) == disk {
+				replaced[dv.Name] = ownerDv.Name
+				break
+			}
+		}
+	}
+	if len(replaced) == 0 {
+		return
+	}
+	object := &cnv.VirtualMachine{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: dependent.Namespace,
+			Name:      dependent.Status.TargetVMName,
+		},
+		object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	patch := object.DeepCopy()
+	changed := false
+	templates := patch.Spec.DataVolumeTemplates[:0]
+	for _, template := range patch.Spec.DataVolumeTemplates {
+		if _, found := replaced[template.Name]; found {
+			changed = true
+			continue
+		}
+		templates = append(templates, template)
+	}
+	patch.Spec.DataVolumeTemplates = templates
+	if patch.Spec.Template != nil {
+		for i := range patch.Spec.Template.Spec.Volumes {
+			volume := &patch.Spec.Template.Spec.Volumes[i]
+			switch {
+			case volume.DataVolume != nil:
+				if name, found := replaced[volume.DataVolume.Name]; found {
+					volume.DataVolume.Name = name
+					changed = true
+				}
+			case volume.PersistentVolumeClaim != nil:
+				if name, found := replaced[volume.PersistentVolumeClaim.ClaimName]; found {
+					volume.PersistentVolumeClaim.ClaimName = name
+					changed = true
+				}
+			}
+		}
+	}
+	if changed {
+		err = r.Destination.Client.Patch(context.TODO(), patch, client.MergeFrom(object))
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info(
+			"Target VM using shared disks.",
+			"vm",
+			vm.String(),
+			"replaced",
+			replaced)
+	}
+	for name := range replaced {
+		dv := &cdi.DataVolume{}
+		dv.Namespace = dependent.Namespace
+		dv.Name = name
+		err = r.Destination.Client.Delete(context.TODO(), dv)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info(
+			"Deleted (shared disk) DataVolume.",
+			"dv",
+			path.Join(
+				dv.Namespace,
+				dv.Name),
+			"vm",
+			vm.String())
+	}
+
+	return
+}
+
+//
+// Remove the owner reference to the import.
+func (r *KubeVirt) disown(object k8sObject, owner *vmio.VirtualMachineImport) (err error) {
+	kept := []meta.OwnerReference{}
+	for _, ref := range object.GetOwnerReferences() {
+		if ref.UID != owner.UID {
+			kept = append(kept, ref)
+		}
+	}
+	if len(kept) == len(object.GetOwnerReferences()) {
+		return
+	}
+	patch := object.DeepCopyObject().(k8sObject)
+	patch.SetOwnerReferences(kept)
+	err = r.Destination.Client.Patch(context.TODO(), patch, client.MergeFrom(object))
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+	}
+
+	return
+}
+
+//
+// Force delete the VMIO CR and DataVolumes for the migration
+// on the destination. Resources not deleted within the grace
+// period (after deletion was requested) have their finalizers
+// removed and are deleted without a grace period. This bypasses
+// the normal cleanup and may orphan resources managed by the
+// importer.
+func (r *KubeVirt) ForceDeleteImport(vm *plan.VMStatus) (err error) {
+	list, err := r.listImports(r.Plan.Spec.VMNamespace(&vm.VM))
+	if err != nil {
+		return
+	}
+	for _, vmImport := range list {
+		if vmImport.Labels[kVM] != vm.ID {
+			continue
+		}
+		for _, dv := range vmImport.DataVolumes {
+			err = r.forceDelete(dv.DataVolume, vm)
+			if err != nil {
+				return
+			}
+		}
+		err = r.forceDelete(vmImport.VirtualMachineImport, vm)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+//
+// Force delete an object.
+// Deleted (normally) when deletion has not been requested.
+func (r *KubeVirt) forceDelete(object k8sObject, vm *plan.VMStatus) (err error) {
+	deleted := object.GetDeletionTimestamp()
+	if deleted == nil {
+		err = r.Destination.Client.Delete(context.TODO(), object)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+			} else {
+				err = liberr.Wrap(err)
+			}
+		}
+		return
+	}
+	if time.Since(deleted.Time) < ForceCleanupGrace {
+		return
+	}
+	if len(object.GetFinalizers()) > 0 {
+		patch := object.DeepCopyObject().(k8sObject)
+		patch.SetFinalizers(nil)
+		err = r.Destination.Client.Patch(context.TODO(), patch, client.MergeFrom(object))
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+			} else {
+				err = liberr.Wrap(err)
+			}
+			return
+		}
+	}
+	err = r.Destination.Client.Delete(
+		context.TODO(),
+		object,
+		client.GracePeriodSeconds(0))
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	r.Log.Info(
+		"FORCE deleted (finalizers removed); normal cleanup bypassed.",
+		"object",
+		path.Join(
+			object.GetNamespace(),
+			object.GetName()),
+		"kind",
+		reflect.TypeOf(object).Elem().Name(),
+		"vm",
+		vm.String())
+
+	return
+}
+
+//
+// Set the run strategy of the target VM.
+func (r *KubeVirt) SetRunning(vm *plan.VMStatus, running bool) (err error) {
+	vmImport, found, err := r.findImport(vm)
+	if err != nil {
+		return
+	}
+	if !found || vmImport.Status.TargetVMName == "" {
+		err = liberr.New("VM import not found.")
+		return
+	}
+	object := &cnv.VirtualMachine{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: vmImport.Namespace,
+			Name:      vmImport.Status.TargetVMName,
+		},
+		object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	strategy := cnv.RunStrategyHalted
+	if running {
+		strategy = cnv.RunStrategyAlways
+	}
+	patch := object.DeepCopy()
+	patch.Spec.Running = nil
+	patch.Spec.RunStrategy = &strategy
+	err = r.Destination.Client.Patch(context.TODO(), patch, client.MergeFrom(object))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Updated VM run strategy.",
+		"vm",
+		vm.String(),
+		"strategy",
+		strategy)
+
+	return
+}
+
+//
+// Apply the (user specified) labels and annotations to the
+// target VM and DataVolumes created by the import. The plan
+// target PVC labels and annotations are also applied to the
+// DataVolumes and PVCs. Applied as the objects are created.
+func (r *KubeVirt) EnsureTargetMetadata(vm *plan.VMStatus) (err error) {
+	pvcLabels := r.Plan.Spec.TargetPVCLabels
+	pvcAnnotations := r.Plan.Spec.TargetPVCAnnotations
+	if len(vm.TargetLabels) == 0 && len(vm.TargetAnnotations) == 0 &&
+		len(pvcLabels) == 0 && len(pvcAnnotations) == 0 {
+		return
+	}
+	vmImport, found, err := r.findImport(vm)
+	if err != nil || !found {
+		return
+	}
+	objects := []metaObject{}
+	if vmImport.Status.TargetVMName != "" {
+		objects = append(
+			objects,
+			metaObject{
+				Object:      &cnv.VirtualMachine{},
+				name:        vmImport.Status.TargetVMName,
+				labels:      vm.TargetLabels,
+				annotations: vm.TargetAnnotations,
+			})
+	}
+	dvLabels := union(vm.TargetLabels, pvcLabels)
+	dvAnnotations := union(vm.TargetAnnotations, pvcAnnotations)
+	for _, dv := range vmImport.Status.DataVolumes {
+		objects = append(
+			objects,
+			metaObject{
+				Object:      &cdi.DataVolume{},
+				name:        dv.Name,
+				labels:      dvLabels,
+				annotations: dvAnnotations,
+			})
+		if len(pvcLabels) > 0 || len(pvcAnnotations) > 0 {
+			objects = append(
+				objects,
+				metaObject{
+					Object:      &core.PersistentVolumeClaim{},
+					name:        dv.Name,
+					labels:      pvcLabels,
+					annotations: pvcAnnotations,
+				})
+		}
+	}
+	for _, object := range objects {
+		err = r.Destination.Client.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: vmImport.Namespace,
+				Name:      object.name,
+			},
+			object.Object)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		original := object.Object.DeepCopyObject()
+		if !object.merge(object.labels, object.annotations) {
+			continue
+		}
+		err = r.Destination.Client.Patch(
+			context.TODO(),
+			object.Object,
+			client.MergeFrom(original))
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info(
+			"Updated target metadata.",
+			"object",
+			path.Join(
+				vmImport.Namespace,
+				object.name),
+			"vm",
+			vm.String())
+	}
+
+	return
+}
+
+//
+// Apply the source VM CPU topology and placement to the
+// target VM created by the import (when requested).
+// Applied once the target VM has been created.
+func (r *KubeVirt) EnsureTargetCPU(vm *plan.VMStatus) (err error) {
+	if !r.Plan.Spec.PreserveCPUTopology {
+		return
+	}
+	vmImport, found, err := r.findImport(vm)
+	if err != nil || !found || vmImport.Status.TargetVMName == "" {
+		return
+	}
+	object := &cnv.VirtualMachine{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: vmImport.Namespace,
+			Name:      vmImport.Status.TargetVMName,
+		},
+		object)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	if object.Spec.Template == nil {
+		return
+	}
+	cpu := &cnv.CPU{}
+	if current := object.Spec.Template.Spec.Domain.CPU; current != nil {
+		cpu = current.DeepCopy()
+	}
+	err = r.Builder.CPU(vm.Ref, cpu)
+	if err != nil {
+		return
+	}
+	current := object.Spec.Template.Spec.Domain.CPU
+	if current != nil &&
+		current.Sockets == cpu.Sockets &&
+		current.Cores == cpu.Cores &&
+		current.DedicatedCPUPlacement == cpu.DedicatedCPUPlacement {
+		return
+	}
+	patch := object.DeepCopy()
+	patch.Spec.Template.Spec.Domain.CPU = cpu
+	err = r.Destination.Client.Patch(context.TODO(), patch, client.MergeFrom(object))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Updated target CPU topology.",
+		"vm",
+		vm.String(),
+		"sockets",
+		cpu.Sockets,
+		"cores",
+		cpu.Cores,
+		"dedicated",
+		cpu.DedicatedCPUPlacement)
+
+	return
+}
+
+//
+// Apply the node selector (recorded on the VM status) to
+// the target VM created by the import.
+// Applied once the target VM has been created.
+func (r *KubeVirt) EnsureTargetPlacement(vm *plan.VMStatus) (err error) {
+	if len(vm.TargetNodeSelector) == 0 {
+		return
+	}
+	vmImport, found, err := r.findImport(vm)
+	if err != nil || !found || vmImport.Status.TargetVMName == "" {
+		return
+	}
+	object := &cnv.VirtualMachine{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: vmImport.Namespace,
+			Name:      vmImport.Status.TargetVMName,
+		},
+		object)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	if object.Spec.Template == nil {
+		return
+	}
+	if reflect.DeepEqual(object.Spec.Template.Spec.NodeSelector, vm.TargetNodeSelector) {
+		return
+	}
+	patch := object.DeepCopy()
+	patch.Spec.Template.Spec.NodeSelector = vm.TargetNodeSelector
+	err = r.Destination.Client.Patch(context.TODO(), patch, client.MergeFrom(object))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Updated target node selector.",
+		"vm",
+		vm.String(),
+		"selector",
+		vm.TargetNodeSelector)
+
+	return
+}
+
+//
+// Find the VMIO CR for the VM.
+func (r *KubeVirt) findImport(vm *plan.VMStatus) (object *vmio.VirtualMachineImport, found bool, err error) {
+	list := &vmio.VirtualMachineImportList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			LabelSelector: labels.SelectorFromSet(r.vmLabels(vm.Ref)),
+			Namespace:     r.Plan.Spec.VMNamespace(&vm.VM),
+		},
+	)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(list.Items) > 0 {
+		object = &list.Items[0]
+		found = true
+	}
+
+	return
+}
+
+//
+// A target object (VM or DataVolume).
+type metaObject struct {
+	Object      k8sObject
+	name        string
+	labels      map[string]string
+	annotations map[string]string
+}
+
+//
+// Kubernetes (runtime and meta) object.
+type k8sObject interface {
+	runtime.Object
+	meta.Object
+}
+
+//
+// Merge labels and annotations.
+// Returns true when changed.
+func (r *metaObject) merge(labels, annotations map[string]string) (changed bool) {
+	objLabels := r.Object.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	for k, v := range labels {
+		if objLabels[k] != v {
+			objLabels[k] = v
+			changed = true
+		}
+	}
+	r.Object.SetLabels(objLabels)
+	objAnnotations := r.Object.GetAnnotations()
+	if objAnnotations == nil {
+		objAnnotations = map[string]string{}
+	}
+	for k, v := range annotations {
+		if objAnnotations[k] != v {
+			objAnnotations[k] = v
+			changed = true
+		}
+	}
+	r.Object.SetAnnotations(objAnnotations)
+
+	return
+}
+
+//
+// Union of maps.
+// Later maps take precedence.
+func union(maps ...map[string]string) (merged map[string]string) {
+	merged = map[string]string{}
+	for _, m := range maps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+
+	return
+}
+
+//
+// List the events for a DataVolume and the associated
+// importer pod. Ordered by most recent.
+func (r *KubeVirt) DataVolumeEvents(dv *cdi.DataVolume) (list []core.Event, err error) {
+	for _, name := range []string{dv.Name, importerPrefix + dv.Name} {
+		eventList := &core.EventList{}
+		err = r.Destination.Client.List(
+			context.TODO(),
+			eventList,
+			&client.ListOptions{
+				Namespace:     dv.Namespace,
+				FieldSelector: fields.OneTermEqualSelector("involvedObject.name", name),
+			},
+		)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		list = append(list, eventList.Items...)
+	}
+	sort.Slice(
+		list,
+		func(i, j int) bool {
+			return list[j].LastTimestamp.Before(&list[i].LastTimestamp)
+		})
+
+	return
+}
+
+//
+// Available capacity (bytes) of the storage classes on
+// the destination as reported by the CSI drivers.
+// The capacity is reported per topology segment and a volume
+// is provisioned in a single segment so only the largest
+// segment accessible from a destination node is counted for
+// each class. Not known when no capacity has been reported
+// for any of the classes or the capacity (or nodes) cannot
+// be listed, for example, when the capacity API is not served.
+func (r *KubeVirt) StorageCapacity(classes []string) (capacity int64, known bool, err error) {
+	list := &storage.CSIStorageCapacityList{}
+	err = r.Destination.Client.List(context.TODO(), list)
+	if err != nil {
+		r.Log.V(1).Info(
+			"Storage capacity not listed.",
+			"reason",
+			err.Error())
+		err = nil
+		return
+	}
+	nodes := &core.NodeList{}
+	err = r.Destination.Client.List(context.TODO(), nodes)
+	if err != nil {
+		r.Log.V(1).Info(
+			"Nodes not listed.",
+			"reason",
+			err.Error())
+		err = nil
+		return
+	}
+	wanted := map[string]bool{}
+	for _, name := range classes {
+		wanted[name] = true
+	}
+	largest := map[string]int64{}
+	for i := range list.Items {
+		object := &list.Items[i]
+		if !wanted[object.StorageClassName] || object.Capacity == nil {
+			continue
+		}
+		accessible, aErr := r.accessible(object.NodeTopology, nodes.Items)
+		if aErr != nil {
+			err = aErr
+			return
+		}
+		if !accessible {
+			continue
+		}
+		value := object.Capacity.Value()
+		if n, found := largest[object.StorageClassName]; !found || value > n {
+			largest[object.StorageClassName] = value
+		}
+	}
+	for _, n := range largest {
+		capacity += n
+		known = true
+	}
+
+	return
+}
+
+//
+// Determine if the topology segment is accessible from
+// any of the nodes. A segment without a topology is not
+// accessible from any node.
+func (r *KubeVirt) accessible(topology *meta.LabelSelector, nodes []core.Node) (accessible bool, err error) {
+	if topology == nil {
+		return
+	}
+	selector, err := meta.LabelSelectorAsSelector(topology)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, node := range nodes {
+		if selector.Matches(labels.Set(node.Labels)) {
+			accessible = true
+			break
+		}
+	}
+
+	return
+}
+
+//
+// Ensure the target namespaces exist on the destination.
+func (r *KubeVirt) EnsureNamespaces() (err error) {
+	for _, name := range r.Plan.Spec.Namespaces() {
+		err = r.ensureNamespace(name)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
 //
 // Ensure the namespace exists on the destination.
-func (r *KubeVirt) EnsureNamespace() (err error) {
+func (r *KubeVirt) ensureNamespace(name string) (err error) {
 	ns := &core.Namespace{
 		ObjectMeta: meta.ObjectMeta{
-			Name: r.Plan.Spec.TargetNamespace,
+			Name: name,
 		},
 	}
 	err = r.Destination.Client.Create(context.TODO(), ns)
 	if err != nil {
 		if k8serr.IsAlreadyExists(err) {
 			err = nil
+		} else {
+			err = liberr.Wrap(err)
 		}
+		return
 	}
 	r.Log.Info(
 		"Created namespace.",
@@ -259,12 +1179,13 @@ func (r *KubeVirt) EnsureNamespace() (err error) {
 
 //
 // Ensure the VMIO secret exists on the destination.
-func (r *KubeVirt) ensureSecret(vmRef ref.Ref) (secret *core.Secret, err error) {
+func (r *KubeVirt) ensureSecret(vm *plan.VM) (secret *core.Secret, err error) {
+	vmRef := vm.Ref
 	_, err = r.Source.Inventory.VM(&vmRef)
 	if err != nil {
 		return
 	}
-	newSecret, err := r.secret(vmRef)
+	newSecret, err := r.secret(vm)
 	if err != nil {
 		return
 	}
@@ -274,7 +1195,7 @@ func (r *KubeVirt) ensureSecret(vmRef ref.Ref) (secret *core.Secret, err error)
 		list,
 		&client.ListOptions{
 			LabelSelector: labels.SelectorFromSet(r.vmLabels(vmRef)),
-			Namespace:     r.Plan.Spec.TargetNamespace,
+			Namespace:     r.Plan.Spec.VMNamespace(vm),
 		},
 	)
 	if err != nil {
@@ -301,8 +1222,29 @@ func (r *KubeVirt) ensureSecret(vmRef ref.Ref) (secret *core.Secret, err error)
 		secret = newSecret
 		err = r.Destination.Client.Create(context.TODO(), secret)
 		if err != nil {
-			err = liberr.Wrap(err)
-			return
+			// Created (by a previous reconcile) but not listed.
+			if !k8serr.IsAlreadyExists(err) {
+				err = liberr.Wrap(err)
+				return
+			}
+			secret = &core.Secret{}
+			err = r.Destination.Client.Get(
+				context.TODO(),
+				client.ObjectKey{
+					Namespace: newSecret.Namespace,
+					Name:      newSecret.Name,
+				},
+				secret)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			secret.StringData = newSecret.StringData
+			err = r.Destination.Client.Update(context.TODO(), secret)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
 		}
 		r.Log.V(1).Info(
 			"Secret created.",
@@ -333,14 +1275,10 @@ func (r *KubeVirt) vmImport(
 	}
 	object = &vmio.VirtualMachineImport{
 		ObjectMeta: meta.ObjectMeta{
-			Namespace:   r.Plan.Spec.TargetNamespace,
+			Namespace:   r.Plan.Spec.VMNamespace(&vm.VM),
 			Labels:      r.vmLabels(vm.Ref),
 			Annotations: annotations,
-			GenerateName: strings.Join(
-				[]string{
-					r.Plan.Name,
-					vm.ID},
-				"-") + "-",
+			Name:        r.vmResourceName(vm.Ref),
 		},
 		Spec: vmio.VirtualMachineImportSpec{
 			ProviderCredentialsSecret: vmio.ObjectIdentifier{
@@ -356,11 +1294,25 @@ func (r *KubeVirt) vmImport(
 	if vm.Name != "" {
 		object.Spec.TargetVMName = &vm.Name
 	}
+	if vm.TargetName != "" {
+		object.Spec.TargetVMName = &vm.TargetName
+	}
+	// the target VM is powered on (as needed) by the
+	// controller when the migration has succeeded.
+	if r.Plan.Spec.PowerOnAfterMigration != "" {
+		start := false
+		object.Spec.StartVM = &start
+	}
 
 	// the value set on the migration, if any, takes precedence over the value set on the plan.
-	if r.Plan.Spec.Warm {
+	if r.Plan.Spec.VMWarm(&vm.VM) {
 		object.Spec.Warm = true
 		object.Spec.FinalizeDate = r.Migration.Spec.Cutover
+		if vm.Warm != nil && vm.Warm.Cutover != nil {
+			if object.Spec.FinalizeDate == nil || vm.Warm.Cutover.Before(object.Spec.FinalizeDate) {
+				object.Spec.FinalizeDate = vm.Warm.Cutover
+			}
+		}
 	}
 
 	return
@@ -368,23 +1320,38 @@ func (r *KubeVirt) vmImport(
 
 //
 // Build the VMIO secret.
-func (r *KubeVirt) secret(vmRef ref.Ref) (object *core.Secret, err error) {
+func (r *KubeVirt) secret(vm *plan.VM) (object *core.Secret, err error) {
 	object = &core.Secret{
 		ObjectMeta: meta.ObjectMeta{
-			Labels:    r.vmLabels(vmRef),
-			Namespace: r.Plan.Spec.TargetNamespace,
-			GenerateName: strings.Join(
-				[]string{
-					r.Plan.Name,
-					vmRef.ID},
-				"-") + "-",
+			Labels:    r.vmLabels(vm.Ref),
+			Namespace: r.Plan.Spec.VMNamespace(vm),
+			Name:      r.vmResourceName(vm.Ref),
 		},
 	}
-	err = r.Builder.Secret(vmRef, r.Source.Secret, object)
+	err = r.Builder.Secret(vm.Ref, r.Source.Secret, object)
 
 	return
 }
 
+//
+// Name of the resources (import and secret) created for a VM.
+// Deterministic so that a resource created before a restart
+// (but not yet listed) is not created again. Unique to the
+// migration so that resources of a previous migration are
+// not reused.
+func (r *KubeVirt) vmResourceName(vmRef ref.Ref) string {
+	uid := string(r.Migration.UID)
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	return strings.Join(
+		[]string{
+			r.Plan.Name,
+			vmRef.ID,
+			uid},
+		"-")
+}
+
 //
 // Labels for plan and migration.
 func (r *KubeVirt) planLabels() map[string]string {
@@ -441,6 +1408,20 @@ func (r *DataVolume) PercentComplete() (pct float64) {
 	return
 }
 
+//
+// Estimate the bytes transferred using the progress
+// and requested storage.
+func (r *DataVolume) BytesTransferred() (n int64) {
+	if r.Spec.PVC == nil {
+		return
+	}
+	if storage, found := r.Spec.PVC.Resources.Requests[core.ResourceStorage]; found {
+		n = int64(float64(storage.Value()) * r.PercentComplete())
+	}
+
+	return
+}
+
 //
 // Represents VMIO VirtualMachineImport with associated DataVolumes.
 type VmImport struct {
@@ -461,6 +1442,16 @@ func (r *VmImport) Owner(dv *cdi.DataVolume) bool {
 	return false
 }
 
+//
+// Estimate the bytes transferred by the DataVolumes.
+func (r *VmImport) BytesTransferred() (n int64) {
+	for i := range r.DataVolumes {
+		n += r.DataVolumes[i].BytesTransferred()
+	}
+
+	return
+}
+
 //
 // Get conditions.
 func (r *VmImport) Conditions() (cnd *libcnd.Conditions) {
@@ -0,0 +1,189 @@
+package plan
+
+import (
+	"context"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+func TestEnsureImportAfterRestart(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	err := vmio.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+	err = cdi.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+
+	vm := &plan.VMStatus{
+		VM: plan.VM{
+			Ref: ref.Ref{ID: "vm-1", Name: "vm1"},
+		},
+	}
+	destination := fake.NewFakeClientWithScheme(scheme.Scheme)
+	newKubeVirt := func(c client.Client) *KubeVirt {
+		ctx := &plancontext.Context{
+			Plan: &api.Plan{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      "plan",
+					UID:       "p1234567-0000",
+				},
+			},
+			Migration: &api.Migration{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      "migration",
+					UID:       "m1234567-0000",
+				},
+			},
+			Log: log,
+		}
+		ctx.Plan.Spec.TargetNamespace = "test"
+		ctx.Source.Inventory = &fakeInventory{}
+		ctx.Destination.Client = c
+		return &KubeVirt{
+			Context: ctx,
+			Builder: &fakeBuilder{},
+		}
+	}
+
+	// CreateImport.
+	kubevirt := newKubeVirt(destination)
+	err = kubevirt.EnsureImport(vm)
+	g.Expect(err).To(gomega.BeNil())
+
+	// Restart (new controller) before ImportCreated.
+	// The import is not yet listed (cache not synced).
+	kubevirt = newKubeVirt(&staleClient{Client: destination})
+	err = kubevirt.EnsureImport(vm)
+	g.Expect(err).To(gomega.BeNil())
+
+	// Restart (cache synced).
+	kubevirt = newKubeVirt(destination)
+	err = kubevirt.EnsureImport(vm)
+	g.Expect(err).To(gomega.BeNil())
+	importMap, err := kubevirt.ImportMap()
+	g.Expect(err).To(gomega.BeNil())
+	_, found := importMap[vm.ID]
+	g.Expect(found).To(gomega.BeTrue())
+
+	imports := &vmio.VirtualMachineImportList{}
+	err = destination.List(context.TODO(), imports)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(imports.Items)).To(gomega.Equal(1))
+	secrets := &core.SecretList{}
+	err = destination.List(context.TODO(), secrets)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(secrets.Items)).To(gomega.Equal(1))
+}
+
+//
+// Client with a cache that has not been synced.
+// Lists are empty.
+func TestStorageCapacity(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	segment := func(name, class, zone string, gi int64) *storage.CSIStorageCapacity {
+		object := &storage.CSIStorageCapacity{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: "test",
+				Name:      name,
+			},
+			StorageClassName: class,
+			Capacity:         resource.NewQuantity(gi*0x40000000, resource.BinarySI),
+		}
+		if zone != "" {
+			object.NodeTopology = &meta.LabelSelector{
+				MatchLabels: map[string]string{"zone": zone},
+			}
+		}
+		return object
+	}
+	ctx := &plancontext.Context{
+		Plan: &api.Plan{},
+		Log:  log,
+	}
+	ctx.Destination.Client = fake.NewFakeClientWithScheme(
+		scheme.Scheme,
+		&core.Node{
+			ObjectMeta: meta.ObjectMeta{
+				Name:   "node-1",
+				Labels: map[string]string{"zone": "a"},
+			},
+		},
+		&core.Node{
+			ObjectMeta: meta.ObjectMeta{
+				Name:   "node-2",
+				Labels: map[string]string{"zone": "b"},
+			},
+		},
+		segment("cap-1", "fast", "a", 10),
+		segment("cap-2", "fast", "b", 20),
+		segment("cap-3", "fast", "c", 50),
+		segment("cap-4", "fast", "", 50),
+		segment("cap-5", "slow", "a", 5),
+		segment("cap-6", "other", "a", 100))
+	kubevirt := KubeVirt{Context: ctx}
+	// The largest accessible segment of each class.
+	capacity, known, err := kubevirt.StorageCapacity([]string{"fast", "slow"})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(known).To(gomega.BeTrue())
+	g.Expect(capacity).To(gomega.Equal(int64(25 * 0x40000000)))
+	// Not reported.
+	_, known, err = kubevirt.StorageCapacity([]string{"none"})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(known).To(gomega.BeFalse())
+	// Not served.
+	ctx.Destination.Client = fake.NewFakeClientWithScheme(runtime.NewScheme())
+	_, known, err = kubevirt.StorageCapacity([]string{"fast"})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(known).To(gomega.BeFalse())
+}
+
+type staleClient struct {
+	client.Client
+}
+
+func (r *staleClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	return nil
+}
+
+//
+// Fake inventory.
+type fakeInventory struct {
+	web.Client
+}
+
+func (r *fakeInventory) VM(ref *ref.Ref) (object interface{}, err error) {
+	return
+}
+
+//
+// Fake builder.
+type fakeBuilder struct {
+	adapter.Builder
+}
+
+func (r *fakeBuilder) Secret(vmRef ref.Ref, in, object *core.Secret) (err error) {
+	object.StringData = map[string]string{"user": "admin"}
+	return
+}
+
+func (r *fakeBuilder) Import(vmRef ref.Ref, object *vmio.VirtualMachineImportSpec) (err error) {
+	return
+}
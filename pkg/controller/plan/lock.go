@@ -0,0 +1,62 @@
+package plan
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"path"
+	"sync"
+)
+
+//
+// Plan locks (singleton).
+var Locks = &PlanLocks{}
+
+//
+// Per-plan locks.
+// Contract: only one Migration.Run (or Cancel) executes at a
+// time for a given plan. Both update the plan status (including
+// the VM status list) and create (or delete) the import CRs
+// using the import map built by the run. Reconciles of the same
+// plan are normally serialized by the work queue but concurrent
+// runs of the same plan (for example: overlapping reconciles
+// after rapid updates) would race on the status and may create
+// duplicate imports. The lock is not blocking: a run that cannot
+// acquire the lock is skipped and requeued so it runs using a
+// fresh copy of the plan rather than the (stale) copy it has.
+// Plans are keyed by namespace and name.
+type PlanLocks struct {
+	mutex sync.Mutex
+	// Locked plans.
+	locked map[string]bool
+}
+
+//
+// Try to lock the plan.
+// Returns false when already locked.
+func (r *PlanLocks) TryLock(plan *api.Plan) (locked bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.locked == nil {
+		r.locked = map[string]bool{}
+	}
+	key := r.key(plan)
+	if r.locked[key] {
+		return
+	}
+	r.locked[key] = true
+	locked = true
+	return
+}
+
+//
+// Unlock the plan.
+func (r *PlanLocks) Unlock(plan *api.Plan) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.locked, r.key(plan))
+}
+
+//
+// Plan key.
+func (r *PlanLocks) key(plan *api.Plan) string {
+	return path.Join(plan.Namespace, plan.Name)
+}
@@ -0,0 +1,254 @@
+package plan
+
+import (
+	"context"
+	libcnd "github.com/konveyor/controller/pkg/condition"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunLocked(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	err := api.SchemeBuilder.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+	err = vmio.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+	err = cdi.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "plan",
+			UID:       "p1234567-0000",
+		},
+	}
+	p.Spec.TargetNamespace = "test"
+	p.Spec.SkipMappingValidation = true
+	p.Spec.AllowMaintenanceMode = true
+	p.Spec.AllowUnvalidated = true
+	p.Spec.Provider.Source = core.ObjectReference{Namespace: "test", Name: "source"}
+	p.Spec.VMs = []plan.VM{
+		{Ref: ref.Ref{ID: "vm-1", Name: "vm1"}},
+	}
+	// A migration in progress.
+	p.Status.Migration.NewSnapshot(plan.Snapshot{})
+	p.Status.Migration.ActiveSnapshot().SetCondition(
+		libcnd.Condition{
+			Type:   Executing,
+			Status: True,
+		})
+	vm := &plan.VMStatus{
+		VM:    p.Spec.VMs[0],
+		Phase: CreateImport,
+	}
+	vm.MarkStarted()
+	p.Status.Migration.VMs = []*plan.VMStatus{vm}
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, p.DeepCopy())
+	newRunner := func() *Migration {
+		ctx := &plancontext.Context{
+			Client: c,
+			Plan:   p.DeepCopy(),
+			Migration: &api.Migration{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      "migration",
+					UID:       "m1234567-0000",
+				},
+			},
+			Log: log,
+		}
+		ctx.Map.Network = &api.NetworkMap{}
+		ctx.Map.Storage = &api.StorageMap{}
+		ctx.Source.Provider = &api.Provider{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: "test",
+				Name:      "source",
+			},
+			Spec: api.ProviderSpec{Type: api.OVirt},
+		}
+		ctx.Source.Secret = &core.Secret{}
+		ctx.Source.Inventory = &ovirtInventory{}
+		ctx.Destination.Client = c
+		return &Migration{Context: ctx}
+	}
+
+	// Concurrent runs of the same plan.
+	// Only one run creates the import. Other runs
+	// are skipped and requeued.
+	wg := sync.WaitGroup{}
+	runners := []*Migration{newRunner(), newRunner()}
+	reQ := make([]time.Duration, len(runners))
+	errList := make([]error, len(runners))
+	start := make(chan int)
+	for i := range runners {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			reQ[i], errList[i] = runners[i].Run()
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	for i := range runners {
+		g.Expect(errList[i]).To(gomega.BeNil())
+	}
+	list := &vmio.VirtualMachineImportList{}
+	err = c.List(context.TODO(), list)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(list.Items).To(gomega.HaveLen(1))
+	g.Expect(Locks.TryLock(p)).To(gomega.BeTrue())
+	Locks.Unlock(p)
+
+	// Locked by plan.
+	other := p.DeepCopy()
+	other.Name = "other"
+	g.Expect(Locks.TryLock(p)).To(gomega.BeTrue())
+	g.Expect(Locks.TryLock(other)).To(gomega.BeTrue())
+	Locks.Unlock(other)
+	reQ[0], err = newRunner().Run()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(reQ[0]).To(gomega.Equal(base.FastReQ))
+	Locks.Unlock(p)
+}
+
+func TestEndLocked(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	err := vmio.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+	err = cdi.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "plan",
+			UID:       "p1234567-0000",
+		},
+	}
+	p.Spec.TargetNamespace = "test"
+	p.Status.Migration.NewSnapshot(plan.Snapshot{})
+	vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}}
+	vm.MarkStarted()
+	vm.MarkCompleted()
+	vm.SetCondition(libcnd.Condition{Type: Failed, Status: True})
+	p.Status.Migration.VMs = []*plan.VMStatus{vm}
+	ctx := &plancontext.Context{
+		Plan: p,
+		Migration: &api.Migration{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: "test",
+				Name:      "migration",
+				UID:       "m1234567-0000",
+			},
+		},
+		Log: log,
+	}
+	ctx.Source.Provider = &api.Provider{
+		Spec: api.ProviderSpec{Type: api.OVirt},
+	}
+	ctx.Destination.Client = fake.NewFakeClientWithScheme(scheme.Scheme)
+	migration := Migration{
+		Context:  ctx,
+		kubevirt: KubeVirt{Context: ctx, Builder: &fakeBuilder{}},
+	}
+
+	// The run holds the lock.
+	// The failed VMs are cleaned up.
+	g.Expect(Locks.TryLock(p)).To(gomega.BeTrue())
+	defer Locks.Unlock(p)
+	completed, err := migration.end()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(completed).To(gomega.BeTrue())
+	g.Expect(vm.Cleaned).To(gomega.BeTrue())
+}
+
+func TestPlanLocks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	err := vmio.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "plan",
+		},
+	}
+	c := fake.NewFakeClientWithScheme(scheme.Scheme)
+	locks := &PlanLocks{}
+
+	// Each (locked) run creates the import when not found.
+	wg := sync.WaitGroup{}
+	errList := make([]error, 10)
+	for i := range errList {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if !locks.TryLock(p) {
+				return
+			}
+			defer locks.Unlock(p)
+			key := client.ObjectKey{Namespace: "test", Name: "vm-1"}
+			imp := &vmio.VirtualMachineImport{}
+			gErr := c.Get(context.TODO(), key, imp)
+			if !k8serr.IsNotFound(gErr) {
+				errList[i] = gErr
+				return
+			}
+			imp.Namespace = key.Namespace
+			imp.Name = key.Name
+			errList[i] = c.Create(context.TODO(), imp)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errList {
+		g.Expect(err).To(gomega.BeNil())
+	}
+	list := &vmio.VirtualMachineImportList{}
+	err = c.List(context.TODO(), list)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(list.Items).To(gomega.HaveLen(1))
+}
+
+//
+// Fake oVirt inventory.
+// VMs are found by ref.
+type ovirtInventory struct {
+	web.Client
+}
+
+func (r *ovirtInventory) Find(resource interface{}, rf ref.Ref) (err error) {
+	switch vm := resource.(type) {
+	case *ovirt.VM:
+		vm.ID = rf.ID
+		vm.Name = rf.Name
+	default:
+		err = web.NotFoundError{Ref: rf}
+	}
+	return
+}
+
+func (r *ovirtInventory) VM(rf *ref.Ref) (object interface{}, err error) {
+	vm := &ovirt.VM{}
+	err = r.Find(vm, *rf)
+	object = vm
+	return
+}
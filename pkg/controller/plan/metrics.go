@@ -0,0 +1,180 @@
+package plan
+
+import (
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+//
+// Metric labels.
+const (
+	NamespaceLabel = "namespace"
+	PlanLabel      = "plan"
+	ProviderLabel  = "provider"
+	PhaseLabel     = "phase"
+	StepLabel      = "step"
+	OutcomeLabel   = "outcome"
+)
+
+//
+// Metrics.
+var (
+	// VMs by migration phase.
+	vmPhaseGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "forklift_migration_vms",
+			Help: "Number of VMs by migration phase.",
+		},
+		[]string{
+			NamespaceLabel,
+			PlanLabel,
+			ProviderLabel,
+			PhaseLabel,
+		})
+	// VM migration outcomes.
+	vmOutcomeCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "forklift_migration_vm_outcomes_total",
+			Help: "Number of VM migrations by outcome.",
+		},
+		[]string{
+			NamespaceLabel,
+			PlanLabel,
+			ProviderLabel,
+			OutcomeLabel,
+		})
+	// Plan execution outcomes.
+	planOutcomeCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "forklift_migration_outcomes_total",
+			Help: "Number of plan executions by outcome.",
+		},
+		[]string{
+			NamespaceLabel,
+			PlanLabel,
+			ProviderLabel,
+			OutcomeLabel,
+		})
+	// Disk bytes transferred.
+	bytesCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "forklift_migration_transferred_bytes_total",
+			Help: "Number of disk bytes transferred.",
+		},
+		[]string{
+			NamespaceLabel,
+			PlanLabel,
+			ProviderLabel,
+		})
+	// Pipeline step durations.
+	stepDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "forklift_migration_step_duration_seconds",
+			Help:    "Time spent in each VM migration pipeline step.",
+			Buckets: prometheus.ExponentialBuckets(10, 2, 12),
+		},
+		[]string{
+			NamespaceLabel,
+			PlanLabel,
+			ProviderLabel,
+			StepLabel,
+		})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		vmPhaseGauge,
+		vmOutcomeCounter,
+		planOutcomeCounter,
+		bytesCounter,
+		stepDuration)
+}
+
+//
+// Metric labels for the migration.
+func (r *Migration) labels() prometheus.Labels {
+	return prometheus.Labels{
+		NamespaceLabel: r.Plan.Namespace,
+		PlanLabel:      r.Plan.Name,
+		ProviderLabel:  r.Type(),
+	}
+}
+
+//
+// Metric labels for the migration with
+// an additional label.
+func (r *Migration) labelsWith(name, value string) (labels prometheus.Labels) {
+	labels = r.labels()
+	labels[name] = value
+	return
+}
+
+//
+// Update the number of VMs by phase.
+// All phases are set so that VMs leaving a
+// phase are reflected.
+func (r *Migration) updatePhaseMetrics() {
+	count := map[string]int{}
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		count[vm.Phase]++
+	}
+	for _, step := range itinerary.Pipeline {
+		vmPhaseGauge.With(
+			r.labelsWith(PhaseLabel, step.Name)).Set(
+			float64(count[step.Name]))
+	}
+}
+
+//
+// Record the VM outcome.
+// Called on transition only.
+func (r *Migration) recordOutcome(outcome string) {
+	vmOutcomeCounter.With(r.labelsWith(OutcomeLabel, outcome)).Inc()
+}
+
+//
+// Record the plan execution outcome.
+// Called on transition only.
+func (r *Migration) recordPlanOutcome(outcome string) {
+	planOutcomeCounter.With(r.labelsWith(OutcomeLabel, outcome)).Inc()
+}
+
+//
+// Record bytes transferred.
+func (r *Migration) recordBytes(n int64) {
+	if n > 0 {
+		bytesCounter.With(r.labels()).Add(float64(n))
+	}
+}
+
+//
+// Record the duration of pipeline steps
+// completed since the `before` snapshot.
+func (r *Migration) recordSteps(vm *plan.VMStatus, before map[string]bool) {
+	for _, step := range vm.Pipeline {
+		if before[step.Name] || !step.MarkedCompleted() {
+			continue
+		}
+		if step.Started == nil {
+			continue
+		}
+		d := step.Completed.Sub(step.Started.Time)
+		stepDuration.With(
+			r.labelsWith(StepLabel, step.Name)).Observe(
+			d.Seconds())
+	}
+}
+
+//
+// The names of completed pipeline steps.
+func completedSteps(vm *plan.VMStatus) (set map[string]bool) {
+	set = map[string]bool{}
+	for _, step := range vm.Pipeline {
+		if step.MarkedCompleted() {
+			set[step.Name] = true
+		}
+	}
+
+	return
+}
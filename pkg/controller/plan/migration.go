@@ -8,11 +8,21 @@ import (
 	libitr "github.com/konveyor/controller/pkg/itinerary"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
+	vsphere "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	"github.com/vmware/govmomi/vim25/types"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"path"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -23,22 +33,37 @@ const (
 	PollReQ = time.Second * 3
 )
 
+//
+// Inventory lookup retry.
+const (
+	// Attempts for transient inventory lookup failures.
+	InventoryRetries = 3
+	// Delay before the first retry. Doubled on each retry.
+	InventoryRetryDelay = time.Millisecond * 500
+)
+
 //
 // Predicates.
 var (
-	HasPreHook  libitr.Flag = 0x01
-	HasPostHook libitr.Flag = 0x02
+	HasPreHook        libitr.Flag = 0x01
+	HasPostHook       libitr.Flag = 0x02
+	HasPreImportHook  libitr.Flag = 0x04
+	HasPostImportHook libitr.Flag = 0x08
+	Quiesce           libitr.Flag = 0x10
 )
 
 //
 // Phases.
 const (
-	Started       = "Started"
-	PreHook       = "PreHook"
-	CreateImport  = "CreateImport"
-	ImportCreated = "ImportCreated"
-	PostHook      = "PostHook"
-	Completed     = "Completed"
+	Started        = "Started"
+	PreHook        = "PreHook"
+	QuiesceGuest   = "QuiesceGuest"
+	PreImportHook  = "PreImportHook"
+	CreateImport   = "CreateImport"
+	ImportCreated  = "ImportCreated"
+	PostImportHook = "PostImportHook"
+	PostHook       = "PostHook"
+	Completed      = "Completed"
 )
 
 //
@@ -48,14 +73,24 @@ const (
 	ImageConversion = "ImageConversion"
 )
 
+//
+// Max number of (warning) events reported
+// on a blocked task.
+const (
+	MaxBlockedEvents = 3
+)
+
 var (
 	itinerary = libitr.Itinerary{
 		Name: "",
 		Pipeline: libitr.Pipeline{
 			{Name: Started},
 			{Name: PreHook, All: HasPreHook},
+			{Name: QuiesceGuest, All: Quiesce},
+			{Name: PreImportHook, All: HasPreImportHook},
 			{Name: CreateImport},
 			{Name: ImportCreated},
+			{Name: PostImportHook, All: HasPostImportHook},
 			{Name: PostHook, All: HasPostHook},
 			{Name: Completed},
 		},
@@ -72,8 +107,12 @@ type Migration struct {
 	kubevirt KubeVirt
 	// VM import CRs.
 	importMap ImportMap
+	// VMs which own shared disks (snapshot).
+	owners map[string]*plan.VMStatus
 	// VM scheduler
 	scheduler scheduler.Scheduler
+	// Additional sources (keyed by provider).
+	sources map[string]KubeVirt
 }
 
 //
@@ -84,42 +123,231 @@ func (r *Migration) Type() string {
 
 //
 // Run the migration.
+// Skipped (and requeued) when the plan is locked
+// by another run. See: PlanLocks.
 func (r *Migration) Run() (reQ time.Duration, err error) {
+	if !Locks.TryLock(r.Plan) {
+		r.Log.Info("Migration [SKIPPED] plan locked by another run.")
+		reQ = base.FastReQ
+		return
+	}
+	defer Locks.Unlock(r.Plan)
 	reQ = PollReQ
+	defer func() {
+		if errors.As(err, &web.ProviderNotReadyError{}) {
+			r.providerNotReady()
+			reQ = base.LongReQ
+			err = nil
+		}
+	}()
 	err = r.init()
 	if err != nil {
 		err = liberr.Wrap(err)
 		return
 	}
+	// Provider not ready.
+	// Detected once (instead of by each VM) and the
+	// migration is resumed when the provider is ready.
+	err = r.probeProvider()
+	if err != nil {
+		return
+	}
+	r.Plan.Status.DeleteCondition(ProviderNotReady)
 	err = r.begin()
 	if err != nil {
 		err = liberr.Wrap(err)
 		return
 	}
+	// Not started.
+	// The preflight storage capacity check failed.
+	if r.Plan.Status.HasCondition(StorageCapacity) {
+		reQ = base.SlowReQ
+		return
+	}
+	// Paused.
+	// VMs are not stepped and new VMs are not scheduled.
+	// The import CRs are left in place. Warm precopies are
+	// not paused (not supported by VMIO).
+	// Requeued when the plan is unpaused.
+	if r.Plan.Spec.Paused {
+		r.Log.Info("Migration [PAUSED]")
+		reQ = NoReQ
+		return
+	}
 
-	r.resolveCanceledRefs()
-
-	for _, vm := range r.runningVMs() {
-		err = r.step(vm)
-		if err != nil {
+	// Outside the allowed windows.
+	// New VMs are not scheduled. Running VMs are not stepped
+	// when paused outside the window. Requeued when the next
+	// window opens.
+	open, boundary := plan.InWindow(r.Plan.Spec.AllowedWindows, time.Now())
+	if open {
+		r.Plan.Status.DeleteCondition(WaitingForWindow)
+	} else {
+		r.waitingForWindow(boundary)
+		if r.Plan.Spec.PauseOutsideWindow && !r.Context.Migration.Spec.CancelAll {
+			r.Log.Info("Migration [PAUSED] outside the allowed windows.")
+			reQ = time.Until(boundary) + time.Second
 			return
 		}
 	}
 
-	vm, hasNext, err := r.scheduler.Next()
+	r.resolveCanceledRefs()
+	r.owners = r.sharedDiskOwners()
+
+	err = r.stepAll(r.runningVMs())
 	if err != nil {
 		return
 	}
-	if hasNext {
-		err = r.step(vm)
+
+	// Shutting down.
+	// New VMs are not scheduled. The status of the
+	// VMs stepped is persisted when the reconcile returns.
+	if Shutdown.Stopping() {
+		r.Log.Info("Migration [STOPPED] shutting down.")
+		return
+	}
+
+	if r.Context.Migration.Spec.CancelAll {
+		// No new VMs are scheduled. VMs which
+		// have not been started are canceled.
+		err = r.stepAll(r.pendingVMs())
+		if err != nil {
+			return
+		}
+	} else if open {
+		var vm *plan.VMStatus
+		var hasNext bool
+		vm, hasNext, err = r.scheduler.Next()
 		if err != nil {
 			return
 		}
+		if hasNext {
+			err = r.step(vm)
+			if err != nil {
+				return
+			}
+		}
 	}
 
+	r.updatePhaseMetrics()
+
 	completed, err := r.end()
 	if completed {
 		reQ = NoReQ
+		return
+	}
+	if !open && len(r.runningVMs()) == 0 {
+		reQ = time.Until(boundary) + time.Second
+	}
+
+	return
+}
+
+//
+// Probe the source providers (inventory).
+// Each provider is probed using the first VM of the provider.
+// Returns ProviderNotReadyError when not ready.
+func (r *Migration) probeProvider() (err error) {
+	probed := map[string]bool{}
+	for i := range r.Plan.Spec.VMs {
+		vm := &r.Plan.Spec.VMs[i]
+		provider := r.Plan.Spec.VMProvider(vm)
+		key := path.Join(provider.Namespace, provider.Name)
+		if probed[key] {
+			continue
+		}
+		probed[key] = true
+		vmRef := vm.Ref
+		_, err = r.forVM(vm).Source.Inventory.VM(&vmRef)
+		if errors.As(err, &web.ProviderNotReadyError{}) {
+			return
+		}
+		err = nil
+	}
+
+	return
+}
+
+//
+// Reflect the source provider not ready on the plan.
+// Durable until the provider is ready.
+func (r *Migration) providerNotReady() {
+	r.Plan.Status.SetCondition(
+		libcnd.Condition{
+			Type:     ProviderNotReady,
+			Status:   True,
+			Category: Warn,
+			Reason:   NotReady,
+			Message:  "The source provider inventory is not ready; the migration will resume when ready.",
+			Durable:  true,
+		})
+	r.Log.Info("Migration [POSTPONED] provider not ready.")
+}
+
+//
+// Reflect waiting for the next allowed window on the plan.
+// Durable until a window opens.
+func (r *Migration) waitingForWindow(opens time.Time) {
+	r.Plan.Status.SetCondition(
+		libcnd.Condition{
+			Type:     WaitingForWindow,
+			Status:   True,
+			Category: Advisory,
+			Reason:   OutsideWindow,
+			Message: fmt.Sprintf(
+				"Waiting for the next allowed window: %s.",
+				opens.Format(time.RFC3339)),
+			Durable: true,
+		})
+}
+
+//
+// Step the VMs using a bounded pool of workers.
+// Each step updates only the VM status. The shared
+// import map is built before the workers are started
+// and is read-only while the VMs are stepped.
+// The first error (in VM order) is returned.
+func (r *Migration) stepAll(vms []*plan.VMStatus) (err error) {
+	if len(vms) == 0 || Shutdown.Stopping() {
+		return
+	}
+	r.importMap, err = r.kubevirt.ImportMap()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	workers := Settings.Migration.StepWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(vms) {
+		workers = len(vms)
+	}
+	errList := make([]error, len(vms))
+	input := make(chan int)
+	wg := sync.WaitGroup{}
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range input {
+				if Shutdown.Stopping() {
+					continue
+				}
+				errList[i] = r.step(vms[i])
+			}
+		}()
+	}
+	for i := range vms {
+		input <- i
+	}
+	close(input)
+	wg.Wait()
+	for _, stepErr := range errList {
+		if stepErr != nil {
+			err = stepErr
+			return
+		}
 	}
 
 	return
@@ -129,8 +357,15 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 // Steps a VM through the migration itinerary
 // and updates its status.
 func (r *Migration) step(vm *plan.VMStatus) (err error) {
+	if runner := r.forVM(&vm.VM); runner != r {
+		err = runner.step(vm)
+		return
+	}
 	// check whether the VM has been canceled by the user
 	if r.Context.Migration.Spec.Canceled(vm.Ref) {
+		if !vm.HasCondition(Canceled) {
+			r.recordOutcome(Canceled)
+		}
 		vm.SetCondition(
 			libcnd.Condition{
 				Type:     Canceled,
@@ -147,9 +382,8 @@ func (r *Migration) step(vm *plan.VMStatus) (err error) {
 			vm.String())
 		return
 	}
-	itinerary.Predicate = &Predicate{
-		vm: &vm.VM,
-	}
+	completed := completedSteps(vm)
+	defer r.phaseTiming(vm, vm.Phase, time.Now())
 
 	r.Log.Info(
 		"Migration [RUN]",
@@ -165,21 +399,71 @@ func (r *Migration) step(vm *plan.VMStatus) (err error) {
 	switch vm.Phase {
 	case Started:
 		vm.MarkStarted()
-		vm.Phase = r.next(vm.Phase)
-	case PreHook, PostHook:
+		vm.Phase = r.next(vm)
+	case PreHook, PreImportHook, PostImportHook, PostHook:
 		runner := HookRunner{Context: r.Context}
 		err = runner.Run(vm)
 		if err != nil {
 			return
 		}
 		if step, found := vm.FindStep(vm.Phase); found {
-			if step.MarkedCompleted() && step.Error == nil {
-				vm.Phase = r.next(vm.Phase)
+			r.hookFailurePolicy(vm, step)
+			if step.MarkedCompleted() && (step.Error == nil || step.Ignored) {
+				vm.Phase = r.next(vm)
 			}
 		} else {
 			vm.Phase = Completed
 		}
+	case QuiesceGuest:
+		if r.quiesceGuest(vm) {
+			vm.Phase = r.next(vm)
+		}
 	case CreateImport:
+		deleted, dErr := r.sourceDeleted(vm)
+		if dErr != nil {
+			err = liberr.Wrap(dErr)
+			return
+		}
+		if deleted {
+			// Nothing has been transferred.
+			vm.AddError("The source VM has been deleted.")
+			break
+		}
+		if r.template(vm) {
+			break
+		}
+		blocked, bErr := r.unmapped(vm)
+		if bErr != nil {
+			err = liberr.Wrap(bErr)
+			return
+		}
+		if blocked {
+			break
+		}
+		blocked, bErr = r.maintenanceMode(vm)
+		if bErr != nil {
+			err = liberr.Wrap(bErr)
+			return
+		}
+		if blocked {
+			break
+		}
+		blocked, bErr = r.notValidated(vm)
+		if bErr != nil {
+			err = liberr.Wrap(bErr)
+			return
+		}
+		if blocked {
+			break
+		}
+		// not set when begun by an earlier release.
+		if vm.Backend != "" && vm.Backend != plan.BackendCDI {
+			vm.AddError(
+				fmt.Sprintf(
+					"Disk transfer backend `%s` not supported.",
+					vm.Backend))
+			break
+		}
 		err = r.kubevirt.EnsureImport(vm)
 		if err != nil {
 			if !errors.As(err, &web.ProviderNotReadyError{}) {
@@ -190,18 +474,31 @@ func (r *Migration) step(vm *plan.VMStatus) (err error) {
 				return
 			}
 		}
-		vm.Phase = r.next(vm.Phase)
+		vm.Phase = r.next(vm)
 	case ImportCreated:
+		deleted, dErr := r.sourceDeleted(vm)
+		if dErr != nil {
+			err = liberr.Wrap(dErr)
+			return
+		}
+		if deleted && !r.Plan.Spec.ContinueSourceDeleted {
+			vm.AddError("The source VM has been deleted.")
+			break
+		}
 		// update the VM if the cutover
-		// changed on the Migration
-		err = r.kubevirt.EnsureImport(vm)
-		if err != nil {
-			if !errors.As(err, &web.ProviderNotReadyError{}) {
-				vm.AddError(err.Error())
-				err = nil
-				break
-			} else {
-				return
+		// changed on the Migration.
+		// The import cannot be (re)built once
+		// the source VM has been deleted.
+		if !deleted {
+			err = r.kubevirt.EnsureImport(vm)
+			if err != nil {
+				if !errors.As(err, &web.ProviderNotReadyError{}) {
+					vm.AddError(err.Error())
+					err = nil
+					break
+				} else {
+					return
+				}
 			}
 		}
 		rErr := r.updateVM(vm)
@@ -209,26 +506,63 @@ func (r *Migration) step(vm *plan.VMStatus) (err error) {
 			err = liberr.Wrap(rErr)
 			return
 		}
+		err = r.kubevirt.EnsureTargetMetadata(vm)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = r.kubevirt.EnsureTargetCPU(vm)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = r.kubevirt.EnsureTargetPlacement(vm)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		if r.Plan.Spec.VMWarm(&vm.VM) {
+			r.precopyLimits(vm)
+			if !deleted {
+				err = r.cutoverOnShutdown(vm)
+				if err != nil {
+					err = liberr.Wrap(err)
+					return
+				}
+			}
+			r.cutoverReady(vm)
+		}
 		// vSphere VMs require image conversion, other VMs are
 		// complete after the disk transfer is finished.
-		if step, found := vm.FindStep(ImageConversion); found {
-			if step.MarkedCompleted() {
-				if step.Error == nil {
-					vm.Phase = r.next(vm.Phase)
-				} else {
-					vm.Phase = Completed
-				}
+		step, found := vm.FindStep(ImageConversion)
+		if !found {
+			step, found = vm.FindStep(DiskTransfer)
+		}
+		if found && step.MarkedCompleted() && r.conversionDone(vm) {
+			if step.Error != nil {
+				vm.Phase = Completed
+				break
 			}
-		} else if step, found = vm.FindStep(DiskTransfer); found {
-			if step.MarkedCompleted() {
-				if step.Error == nil {
-					vm.Phase = r.next(vm.Phase)
-				} else {
-					vm.Phase = Completed
-				}
+			ready, rErr := r.sharedDisksReady(vm)
+			if rErr != nil {
+				err = liberr.Wrap(rErr)
+				return
+			}
+			if ready {
+				vm.Phase = r.next(vm)
 			}
 		}
 	case Completed:
+		// The target VM is powered on only
+		// when the migration has succeeded.
+		if vm.Error == nil && !vm.HasAnyCondition(Canceled, Failed) {
+			r.powerOn(vm)
+			// The import is kept when shared disks owned by the
+			// VM are used by other (dependent) VMs.
+			if vm.Error == nil && r.Plan.Spec.CleanupAfterSuccess && !r.hasDependents(vm) {
+				r.cleanup(vm)
+			}
+		}
 		vm.MarkCompleted()
 		r.Log.Info(
 			"Migration [COMPLETED]",
@@ -246,7 +580,11 @@ func (r *Migration) step(vm *plan.VMStatus) (err error) {
 				vm.Phase))
 	}
 	vm.ReflectPipeline()
+	r.recordSteps(vm, completed)
 	if vm.Phase == Completed && vm.Error == nil {
+		if !vm.HasCondition(Succeeded) {
+			r.recordOutcome(Succeeded)
+		}
 		vm.SetCondition(
 			libcnd.Condition{
 				Type:     Succeeded,
@@ -257,6 +595,9 @@ func (r *Migration) step(vm *plan.VMStatus) (err error) {
 			})
 	} else if vm.Error != nil {
 		vm.Phase = Completed
+		if !vm.HasCondition(Failed) {
+			r.recordOutcome(Failed)
+		}
 		vm.SetCondition(
 			libcnd.Condition{
 				Type:     Failed,
@@ -271,21 +612,73 @@ func (r *Migration) step(vm *plan.VMStatus) (err error) {
 
 //
 // Cancel the migration.
-// Delete resources associated with VMs that have failed or been marked canceled.
-func (r *Migration) Cancel() (err error) {
+// Delete resources (including the target VM) associated with VMs
+// that have failed or been marked canceled. The VMs are cleaned up
+// in batches (across reconciles) to bound the load on the API server.
+// Returns pending=true when VMs remain to be cleaned up.
+// Skipped (pending) when the plan is locked by another run.
+func (r *Migration) Cancel() (pending bool, err error) {
+	if !Locks.TryLock(r.Plan) {
+		pending = true
+		return
+	}
+	defer Locks.Unlock(r.Plan)
 	err = r.init()
 	if err != nil {
 		err = liberr.Wrap(err)
 		return
 	}
+	pending, err = r.cancel()
+	return
+}
 
+//
+// Delete resources associated with VMs that have failed
+// or been marked canceled.
+// The caller must hold the plan lock.
+func (r *Migration) cancel() (pending bool, err error) {
+	batch := Settings.Migration.CancelBatch
+	if batch < 1 {
+		batch = 1
+	}
 	for _, vm := range r.Plan.Status.Migration.VMs {
-		if vm.HasAnyCondition(Canceled, Failed) {
+		if vm.HasAnyCondition(Canceled, Failed) && !vm.Cleaned {
+			if batch == 0 {
+				pending = true
+				break
+			}
+			batch--
+			// The target VM is found using the import
+			// and must be deleted first.
+			err = r.kubevirt.DeleteVM(vm)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
 			err = r.kubevirt.DeleteImport(vm)
 			if err != nil {
 				err = liberr.Wrap(err)
 				return
 			}
+			if r.Plan.Spec.ForceCleanup {
+				err = r.kubevirt.ForceDeleteImport(vm)
+				if err != nil {
+					err = liberr.Wrap(err)
+					return
+				}
+			}
+			// Cleaned when the import has been deleted.
+			var found bool
+			_, found, err = r.kubevirt.findImport(vm)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			if found {
+				pending = true
+			} else {
+				vm.Cleaned = true
+			}
 			vm.MarkCompleted()
 			for _, step := range vm.Pipeline {
 				if step.MarkedStarted() {
@@ -299,156 +692,1128 @@ func (r *Migration) Cancel() (err error) {
 }
 
 //
-// Best effort attempt to resolve canceled refs.
-func (r *Migration) resolveCanceledRefs() {
-	for i := range r.Context.Migration.Spec.Cancel {
-		// resolve the VM ref in place
-		ref := &r.Context.Migration.Spec.Cancel[i]
-		_, _ = r.Source.Inventory.VM(ref)
+// Track the time the VM entered the current phase and
+// log (diagnostic) phase timing. The time in phase is
+// reported for the phase that was processed.
+func (r *Migration) phaseTiming(vm *plan.VMStatus, phase string, started time.Time) {
+	now := time.Now()
+	if vm.PhaseStarted == nil {
+		vm.PhaseStarted = &meta.Time{Time: started}
+	}
+	inPhase := now.Sub(vm.PhaseStarted.Time)
+	if vm.Phase != phase {
+		vm.PhaseStarted = &meta.Time{Time: now}
+	}
+	r.Log.V(1).Info(
+		"Migration [TIMING]",
+		"vm",
+		vm.String(),
+		"phase",
+		phase,
+		"duration",
+		now.Sub(started).String(),
+		"next",
+		vm.Phase,
+		"inPhase",
+		inPhase.String())
+}
+
+//
+// Best effort attempt to resolve canceled refs.
+func (r *Migration) resolveCanceledRefs() {
+	for i := range r.Context.Migration.Spec.Cancel {
+		// resolve the VM ref in place
+		ref := &r.Context.Migration.Spec.Cancel[i]
+		err := r.retry(func() (err error) {
+			_, err = r.Source.Inventory.VM(ref)
+			return
+		})
+		if err != nil && !errors.As(err, &web.NotFoundError{}) {
+			r.Log.Info(
+				"Canceled VM ref not resolved.",
+				"vm",
+				ref.String(),
+				"error",
+				err.Error())
+		}
+	}
+}
+
+//
+// Call the inventory lookup with a bounded retry (and backoff)
+// of transient failures. The VM not found, ref not unique and
+// provider not ready (or supported) errors are not transient
+// and are returned without retry.
+func (r *Migration) retry(lookup func() error) (err error) {
+	delay := InventoryRetryDelay
+	for attempt := 1; ; attempt++ {
+		err = lookup()
+		if err == nil || !r.transient(err) || attempt >= InventoryRetries {
+			return
+		}
+		r.Log.Info(
+			"Inventory lookup failed; retrying.",
+			"attempt",
+			attempt,
+			"error",
+			err.Error())
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+//
+// Determine whether an inventory lookup error is transient.
+func (r *Migration) transient(err error) bool {
+	return !(errors.As(err, &web.NotFoundError{}) ||
+		errors.As(err, &web.RefNotUniqueError{}) ||
+		errors.As(err, &web.ProviderNotReadyError{}) ||
+		errors.As(err, &web.ProviderNotSupportedError{}))
+}
+
+//
+func (r *Migration) runningVMs() (vms []*plan.VMStatus) {
+	vms = make([]*plan.VMStatus, 0)
+	for i := range r.Plan.Status.Migration.VMs {
+		vm := r.Plan.Status.Migration.VMs[i]
+		if vm.Running() {
+			vms = append(vms, vm)
+		}
+	}
+	return
+}
+
+//
+// VMs which have not been started.
+func (r *Migration) pendingVMs() (vms []*plan.VMStatus) {
+	vms = make([]*plan.VMStatus, 0)
+	for i := range r.Plan.Status.Migration.VMs {
+		vm := r.Plan.Status.Migration.VMs[i]
+		if !vm.MarkedStarted() && !vm.MarkedCompleted() {
+			vms = append(vms, vm)
+		}
+	}
+	return
+}
+
+//
+// Get/Build resources.
+func (r *Migration) init() (err error) {
+	pAdapter, err := adapter.New(r.Context.Source.Provider)
+	if err != nil {
+		return
+	}
+
+	r.builder, err = pAdapter.Builder(r.Context)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.kubevirt = KubeVirt{
+		Context: r.Context,
+		Builder: r.builder,
+	}
+	r.sources = map[string]KubeVirt{}
+	for _, source := range r.Plan.Referenced.Sources {
+		ctx, wErr := r.Context.WithSource(source)
+		if wErr != nil {
+			err = liberr.Wrap(wErr)
+			return
+		}
+		sAdapter, aErr := adapter.New(source.Provider)
+		if aErr != nil {
+			err = aErr
+			return
+		}
+		builder, bErr := sAdapter.Builder(ctx)
+		if bErr != nil {
+			err = liberr.Wrap(bErr)
+			return
+		}
+		r.sources[path.Join(source.Provider.Namespace, source.Provider.Name)] = KubeVirt{
+			Context: ctx,
+			Builder: builder,
+		}
+	}
+	r.scheduler, err = scheduler.New(r.Context)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+//
+// The runner for a VM.
+// VMs of an additional source provider are run by a
+// (shallow) copy of the migration using the context and
+// builder of the source. Otherwise, the migration. The
+// copy has no sources so it runs the VM itself.
+func (r *Migration) forVM(vm *plan.VM) (runner *Migration) {
+	runner = r
+	if vm.Provider == nil {
+		return
+	}
+	source, found := r.sources[path.Join(vm.Provider.Namespace, vm.Provider.Name)]
+	if !found {
+		return
+	}
+	copied := *r
+	copied.Context = source.Context
+	copied.builder = source.Builder
+	copied.kubevirt = source
+	copied.sources = nil
+	runner = &copied
+
+	return
+}
+
+//
+// The itinerary for a VM.
+// A copy with the VM predicate so that VMs may
+// be stepped concurrently.
+func (r *Migration) vmItinerary(vm *plan.VM) (itr libitr.Itinerary) {
+	itr = itinerary
+	itr.Predicate = &Predicate{
+		vm:      vm,
+		quiesce: r.quiesce(vm),
+	}
+	return
+}
+
+//
+// The guest file systems are quiesced before the
+// disks are transferred.
+// Cold migration of vSphere VMs only.
+func (r *Migration) quiesce(vm *plan.VM) bool {
+	return r.Plan.Spec.Quiesce &&
+		!r.Plan.Spec.VMWarm(vm) &&
+		r.forVM(vm).Source.Provider.Type() == api.VSphere
+}
+
+//
+// Next step in the VM itinerary.
+func (r *Migration) next(vm *plan.VMStatus) (next string) {
+	itr := r.vmItinerary(&vm.VM)
+	step, done, err := itr.Next(vm.Phase)
+	if done || err != nil {
+		next = Completed
+		if err != nil {
+			r.Log.Error(err, "Next phase failed.")
+		}
+	} else {
+		next = step.Name
+	}
+
+	return
+}
+
+//
+// Determine whether the migration has begun (or ended).
+func (r *Migration) begun() bool {
+	snapshot := r.Plan.Status.Migration.ActiveSnapshot()
+	return snapshot.HasAnyCondition(Executing, Succeeded, Failed, Canceled)
+}
+
+//
+// Begin the migration.
+// A strict no-op once the migration has begun. The VM list is
+// built using copies of the VM status and replaced (and the
+// migration marked started) only after all of the VMs have been
+// reset so that a failed (and retried) begin does not leave a
+// partially initialized migration or reset a VM twice.
+func (r *Migration) begin() (err error) {
+	if r.begun() {
+		return
+	}
+	sufficient, err := r.checkCapacity()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if !sufficient {
+		return
+	}
+	err = r.kubevirt.EnsureNamespaces()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	//
+	// Delete
+	// The VM refs are resolved (in batch). VMs not
+	// found in the inventory are dropped unless started
+	// by a previous run; the status is preserved and the
+	// VM is reported as deleted from the source.
+	// The refs are resolved using the VM source provider.
+	// The resolved VMs are used by the checks below so
+	// that each VM is found in the inventory once.
+	refs := map[string][]*ref.Ref{}
+	runners := map[string]*Migration{}
+	for i := range r.Plan.Spec.VMs {
+		vm := &r.Plan.Spec.VMs[i]
+		runner := r.forVM(vm)
+		provider := runner.Source.Provider
+		key := path.Join(provider.Namespace, provider.Name)
+		vmRef := vm.Ref
+		refs[key] = append(refs[key], &vmRef)
+		runners[key] = runner
+	}
+	resolved := map[string]interface{}{}
+	for key, runner := range runners {
+		var found map[ref.Ref]interface{}
+		err = r.retry(func() (err error) {
+			found, err = runner.Source.Inventory.VMs(refs[key])
+			return
+		})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for vmRef, object := range found {
+			resolved[vmRef.ID] = object
+		}
+	}
+	kept := map[string]*plan.VMStatus{}
+	for _, status := range r.Plan.Status.Migration.VMs {
+		if _, found := r.Plan.Spec.FindVM(status.Ref); !found {
+			continue
+		}
+		status = status.DeepCopy()
+		if _, found := resolved[status.ID]; !found {
+			r.Log.Info(
+				"VM not found in the inventory.",
+				"vm",
+				status.String())
+			if !status.MarkedStarted() {
+				continue
+			}
+			r.markSourceDeleted(status)
+		}
+		kept[status.ID] = status
+	}
+	//
+	// Add/Update.
+	list := []*plan.VMStatus{}
+	reset := map[string]bool{}
+	for _, vm := range r.Plan.Spec.VMs {
+		var status *plan.VMStatus
+		vm.Hooks = r.Plan.Spec.VMHooks(&vm)
+		itr := r.vmItinerary(&vm)
+		step, _ := itr.First()
+		if current, found := kept[vm.ID]; !found {
+			status = &plan.VMStatus{VM: vm}
+		} else {
+			status = current
+		}
+		if status.HasCondition(SourceDeleted) {
+			log.Info(
+				"Pipeline preserved (source deleted).",
+				"vm",
+				vm.String())
+			list = append(list, status)
+			continue
+		}
+		if status.Phase != Completed || status.HasAnyCondition(Canceled, Failed) {
+			runner := r.forVM(&vm)
+			pipeline, pErr := r.buildPipeline(&vm)
+			if pErr != nil {
+				err = liberr.Wrap(pErr)
+				return
+			}
+			status.RecordAttempt(r.outcome(status))
+			status.DeleteCondition(
+				Canceled,
+				Failed,
+				HookNotValid,
+				VMUUIDChanged,
+				CutoverForced,
+				PrecopyFailed,
+				VMFirmwareUEFI,
+				VMSecureBoot,
+				HookFailed,
+				GuestNotQuiesced,
+				PowerOnFailed)
+			status.MarkReset()
+			status.Pipeline = pipeline
+			status.Backend = runner.builder.TransferBackend(vm.Ref)
+			_, converted := status.FindStep(ImageConversion)
+			status.ConversionSkipped = status.Backend == plan.BackendCDI &&
+				runner.Source.Provider.Type() == api.VSphere &&
+				!converted
+			status.StorageClasses = r.storageClasses(pipeline)
+			status.Phase = step.Name
+			status.PhaseStarted = nil
+			status.Error = nil
+			status.Warm = nil
+			status.Hooks = vm.Hooks
+			status.TargetNamespace = vm.TargetNamespace
+			status.TargetName = vm.TargetName
+			status.TargetLabels = vm.TargetLabels
+			status.TargetAnnotations = vm.TargetAnnotations
+			status.TargetNodeSelector = r.Plan.Spec.VMNodeSelector(&vm)
+			status.ExcludedDisks = vm.ExcludedDisks
+			status.WarmMigration = vm.WarmMigration
+			status.Priority = vm.Priority
+			status.Provider = vm.Provider
+			status.SkippedDisks = nil
+			status.SharedDisks = nil
+			reset[status.ID] = true
+			status.Cleaned = false
+			object, found := resolved[vm.ID]
+			if !found {
+				err = liberr.New(
+					fmt.Sprintf(
+						"VM %s not found in the inventory.",
+						vm.String()))
+				return
+			}
+			runner.sourceState(status, object)
+			runner.template(status)
+			_, err = runner.unmapped(status)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			if r.Plan.Spec.SkipSharedDisks {
+				status.SkippedDisks, err = runner.builder.SharedDisks(vm.Ref)
+				if err != nil {
+					err = liberr.Wrap(err)
+					return
+				}
+			}
+			_, err = runner.maintenanceMode(status)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			runner.revisionNotValidated(status, object)
+			r.validateHooks(status)
+			runner.validateFirmware(status, object)
+			runner.validateUUID(status, object)
+			log.Info(
+				"Pipeline reset.",
+				"vm",
+				vm.String())
+		} else {
+			log.Info(
+				"Pipeline preserved.",
+				"vm",
+				vm.String())
+		}
+		list = append(list, status)
+	}
+	err = r.electSharedDiskOwners(list, reset)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	r.Plan.Status.Migration.VMs = list
+	r.Plan.Status.Migration.MarkReset()
+	r.Plan.Status.Migration.MarkStarted()
+	snapshot := r.Plan.Status.Migration.ActiveSnapshot()
+	snapshot.SetCondition(
+		libcnd.Condition{
+			Type:     Executing,
+			Status:   True,
+			Category: Advisory,
+			Message:  "The plan is EXECUTING.",
+			Durable:  true,
+		})
+
+	r.Log.Info("Migration [STARTED]")
+
+	return
+}
+
+//
+// Elect the owner of each shared disk.
+// The owner is the first VM (in plan order) which shares the
+// disk and is migrated to the same target namespace. Owners
+// recorded on VMs with a preserved pipeline are kept.
+func (r *Migration) electSharedDiskOwners(list []*plan.VMStatus, reset map[string]bool) (err error) {
+	if !r.Plan.Spec.MigrateSharedDisks || r.Plan.Spec.SkipSharedDisks {
+		return
+	}
+	key := func(vm *plan.VMStatus, disk string) string {
+		return path.Join(r.Plan.Spec.VMNamespace(&vm.VM), disk)
+	}
+	owners := map[string]string{}
+	for _, vm := range list {
+		if reset[vm.ID] {
+			continue
+		}
+		for disk, owner := range vm.SharedDisks {
+			if owner == vm.ID {
+				owners[key(vm, disk)] = owner
+			}
+		}
+	}
+	for _, vm := range list {
+		if !reset[vm.ID] {
+			continue
+		}
+		var disks []string
+		disks, err = r.forVM(&vm.VM).builder.SharedDisks(vm.Ref)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, disk := range disks {
+			owner, found := owners[key(vm, disk)]
+			if !found {
+				owner = vm.ID
+				owners[key(vm, disk)] = owner
+			}
+			if vm.SharedDisks == nil {
+				vm.SharedDisks = map[string]string{}
+			}
+			vm.SharedDisks[disk] = owner
+			if owner != vm.ID {
+				r.Log.Info(
+					"Shared disk owned by another VM.",
+					"vm",
+					vm.String(),
+					"disk",
+					disk,
+					"owner",
+					owner)
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Snapshot of the VMs which own shared disks keyed by ID.
+// Dependent VMs are stepped concurrently with the owners.
+func (r *Migration) sharedDiskOwners() (owners map[string]*plan.VMStatus) {
+	owners = map[string]*plan.VMStatus{}
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		for _, owner := range vm.SharedDisks {
+			if owner == vm.ID {
+				owners[vm.ID] = vm.DeepCopy()
+				break
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Determine whether other VMs use shared disks owned by the VM.
+func (r *Migration) hasDependents(vm *plan.VMStatus) bool {
+	for _, other := range r.Plan.Status.Migration.VMs {
+		if other.ID == vm.ID {
+			continue
+		}
+		for _, owner := range other.SharedDisks {
+			if owner == vm.ID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+//
+// Determine whether the shared disks owned by other VMs are
+// ready to be used by the target VM. Ready when the owners have
+// succeeded and the target VM has been updated to use the owner
+// DataVolumes. The VM fails when an owner has failed.
+func (r *Migration) sharedDisksReady(vm *plan.VMStatus) (ready bool, err error) {
+	for disk, id := range vm.SharedDisks {
+		if id == vm.ID {
+			continue
+		}
+		owner, found := r.owners[id]
+		if !found || owner.HasAnyCondition(Failed, Canceled) {
+			vm.AddError(
+				fmt.Sprintf(
+					"The migration of VM %s which owns shared disk %s has failed.",
+					id,
+					disk))
+			return
+		}
+		if !owner.HasCondition(Succeeded) {
+			r.Log.Info(
+				"Waiting for the shared disk owner.",
+				"vm",
+				vm.String(),
+				"disk",
+				disk,
+				"owner",
+				owner.String())
+			return
+		}
+	}
+	err = r.kubevirt.AttachSharedDisks(vm)
+	if err != nil {
+		return
+	}
+	ready = true
+
+	return
+}
+
+//
+// Determine whether the import has finished when the
+// ImageConversion step has been skipped. The importer may
+// still run the conversion (within the import) after the
+// disk transfer has completed so the VM is not completed
+// until the import has finished. An import failure is
+// reported as a VM error.
+func (r *Migration) conversionDone(vm *plan.VMStatus) (done bool) {
+	if !vm.ConversionSkipped {
+		done = true
+		return
+	}
+	imp, found := r.importMap[vm.ID]
+	if !found {
+		return
+	}
+	conditions := imp.Conditions()
+	cnd := conditions.FindCondition("Succeeded")
+	if cnd == nil {
+		return
+	}
+	if cnd.Status != True {
+		vm.AddError(cnd.Message)
+	}
+	done = true
+
+	return
+}
+
+//
+// The outcome of the VM migration.
+// Not set when the migration did not complete.
+func (r *Migration) outcome(vm *plan.VMStatus) (outcome string) {
+	for _, cndType := range []string{Succeeded, Failed, Canceled} {
+		if vm.HasCondition(cndType) {
+			outcome = cndType
+			break
+		}
+	}
+
+	return
+}
+
+//
+// Preflight check of the target storage capacity.
+// The total capacity of the disks to be transferred (as
+// used to build the pipeline) is compared against the capacity
+// available to the mapped storage classes. The check is skipped
+// when requested (thin-provisioned storage) or the available
+// capacity is not known. The migration is not started while
+// the capacity is insufficient.
+func (r *Migration) checkCapacity() (sufficient bool, err error) {
+	sufficient = true
+	if r.Plan.Spec.SkipCapacityCheck || r.Plan.Referenced.Map.Storage == nil {
+		return
+	}
+	required := int64(0)
+	for _, vm := range r.Plan.Spec.VMs {
+		if current, found := r.Plan.Status.Migration.FindVM(vm.Ref); found {
+			if current.Phase == Completed && !current.HasAnyCondition(Canceled, Failed) {
+				continue
+			}
+		}
+		tasks, tErr := r.forVM(&vm).builder.Tasks(vm.Ref)
+		if tErr != nil {
+			err = liberr.Wrap(tErr)
+			return
+		}
+		for _, task := range tasks {
+			required += task.Progress.Total
+		}
+	}
+	classes := []string{}
+	for _, pair := range r.Plan.Referenced.Map.Storage.Spec.Map {
+		classes = append(classes, pair.Destination.Classes()...)
+	}
+	for _, source := range r.sources {
+		for _, pair := range source.Map.Storage.Spec.Map {
+			classes = append(classes, pair.Destination.Classes()...)
+		}
+	}
+	capacity, known, err := r.kubevirt.StorageCapacity(classes)
+	if err != nil || !known {
+		return
+	}
+	available := capacity / 0x100000
+	if required <= available {
+		return
+	}
+	sufficient = false
+	r.Plan.Status.SetCondition(
+		libcnd.Condition{
+			Type:     StorageCapacity,
+			Status:   True,
+			Category: Critical,
+			Reason:   NotValid,
+			Message: fmt.Sprintf(
+				"The target storage capacity is not sufficient: %d MB required, %d MB available, %d MB short.",
+				required,
+				available,
+				required-available),
+		})
+	r.Log.Info(
+		"Migration [BLOCKED] storage capacity not sufficient.",
+		"requiredMB",
+		required,
+		"availableMB",
+		available)
+
+	return
+}
+
+//
+// Validate the hooks referenced by the VM.
+// VMs with hooks not valid fail before the disk transfer
+// is started.
+func (r *Migration) validateHooks(vm *plan.VMStatus) {
+	runner := HookRunner{Context: r.Context}
+	reasons := runner.Validate(&vm.VM)
+	if len(reasons) == 0 {
+		return
+	}
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     HookNotValid,
+			Status:   True,
+			Category: Critical,
+			Reason:   NotValid,
+			Message:  "VM hooks not valid.",
+			Items:    reasons,
+		})
+	vm.AddError(reasons...)
+}
+
+//
+// Validate the source VM UUID.
+// Records the UUID when the VM is added to the migration.
+// Afterwards, a different UUID indicates that the ref has
+// been reused (vSphere moref) by another VM and the VM fails
+// before the disk transfer is started.
+func (r *Migration) validateUUID(vm *plan.VMStatus, object interface{}) {
+	uuid := ""
+	switch resource := object.(type) {
+	case *vsphere.VM:
+		uuid = resource.UUID
+	}
+	if uuid == "" {
+		return
+	}
+	if vm.UUID == "" {
+		vm.UUID = uuid
+		return
+	}
+	if vm.UUID == uuid {
+		return
+	}
+	msg := fmt.Sprintf(
+		"VM UUID changed: expected: %s, actual: %s.",
+		vm.UUID,
+		uuid)
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     VMUUIDChanged,
+			Status:   True,
+			Category: Critical,
+			Reason:   NotValid,
+			Message:  msg,
+		})
+	vm.AddError(msg)
+
+	return
+}
+
+//
+// Cleanup the import after the VM migration has succeeded.
+// Failures are logged; the migration has succeeded.
+func (r *Migration) cleanup(vm *plan.VMStatus) {
+	err := r.kubevirt.CleanupImport(vm)
+	if err != nil {
+		r.Log.Error(
+			err,
+			"Cleanup failed.",
+			"vm",
+			vm.String())
+	}
+}
+
+//
+// Validate the source VM firmware.
+// UEFI is configured on the target VM by the import. Secure
+// boot cannot be configured by the import and the (migrated)
+// VM would not boot so the VM fails before the disk transfer
+// is started.
+func (r *Migration) validateFirmware(vm *plan.VMStatus, object interface{}) {
+	vm.Firmware = plan.FirmwareBIOS
+	switch model := object.(type) {
+	case *vsphere.VM:
+		if model.Firmware == "efi" {
+			vm.Firmware = plan.FirmwareUEFI
+			if model.SecureBoot {
+				vm.Firmware = plan.FirmwareSecureBoot
+			}
+		}
+	case *ovirt.VM:
+		switch model.BIOS {
+		case "q35_ovmf":
+			vm.Firmware = plan.FirmwareUEFI
+		case "q35_secure_boot":
+			vm.Firmware = plan.FirmwareSecureBoot
+		}
+	default:
+		vm.Firmware = ""
+	}
+	switch vm.Firmware {
+	case plan.FirmwareUEFI:
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     VMFirmwareUEFI,
+				Status:   True,
+				Category: Advisory,
+				Message:  "The VM firmware is UEFI; configured on the target VM.",
+			})
+	case plan.FirmwareSecureBoot:
+		msg := "The VM firmware is UEFI with secure boot; secure boot cannot be configured on the target VM."
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     VMSecureBoot,
+				Status:   True,
+				Category: Critical,
+				Reason:   NotSupported,
+				Message:  msg,
+			})
+		vm.AddError(msg)
+	}
+
+	return
+}
+
+//
+// Apply the power-on policy to the target VM.
+// Failures are reported as a (durable) warning on the VM.
+// The VM has been migrated so it is not failed; a failed VM
+// is cleaned up which would delete the target VM.
+func (r *Migration) powerOn(vm *plan.VMStatus) {
+	if vm.Template {
+		// Kept as a golden image.
+		return
+	}
+	running := false
+	switch r.Plan.Spec.PowerOnAfterMigration {
+	case api.PowerOnAlways:
+		running = true
+	case api.PowerOnMatchSource:
+		running = vm.SourcePowerState == plan.PowerOn
+	case api.PowerOnNever:
+	default:
+		return
+	}
+	err := r.kubevirt.SetRunning(vm, running)
+	if err != nil {
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     PowerOnFailed,
+				Status:   True,
+				Category: Warn,
+				Reason:   NotValid,
+				Message: fmt.Sprintf(
+					"Power-on policy not applied: %s",
+					err.Error()),
+				Durable: true,
+			})
+		r.Log.Info(
+			"Power-on policy not applied.",
+			"vm",
+			vm.String(),
+			"error",
+			err.Error())
+	}
+}
+
+//
+// Apply the hook failure policy.
+// A failed hook step with the Ignore policy is completed and
+// the failure reported as a warning on the VM. The (ignored)
+// error is kept on the step.
+func (r *Migration) hookFailurePolicy(vm *plan.VMStatus, step *plan.Step) {
+	if step.Error == nil || step.Ignored {
+		return
+	}
+	hook, found := vm.FindHook(step.Name)
+	if !found || hook.FailurePolicy != plan.HookIgnore {
+		return
+	}
+	step.Ignored = true
+	step.MarkCompleted()
+	items := []string{}
+	if cnd := vm.FindCondition(HookFailed); cnd != nil {
+		items = cnd.Items
+	}
+	items = append(
+		items,
+		fmt.Sprintf(
+			"%s: %s",
+			hook.String(),
+			strings.Join(step.Error.Reasons, "; ")))
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     HookFailed,
+			Status:   True,
+			Category: Warn,
+			Reason:   Ignored,
+			Message:  "Hook failures ignored (failure policy).",
+			Items:    items,
+			Durable:  true,
+		})
+	r.Log.Info(
+		"Hook failure ignored.",
+		"vm",
+		vm.String(),
+		"step",
+		step.Name)
+}
+
+//
+// Record the source VM template and power state
+// using the (resolved) VM inventory.
+// Templates are recorded as powered off.
+func (r *Migration) sourceState(vm *plan.VMStatus, object interface{}) {
+	vm.Template = false
+	vm.SourcePowerState = plan.PowerOff
+	switch model := object.(type) {
+	case *vsphere.VM:
+		vm.Template = model.IsTemplate
+		if !vm.Template && model.PowerState == string(types.VirtualMachinePowerStatePoweredOn) {
+			vm.SourcePowerState = plan.PowerOn
+		}
+	case *ovirt.VM:
+		if model.Status == "up" {
+			vm.SourcePowerState = plan.PowerOn
+		}
+	}
+}
+
+//
+// Block the migration of VM templates.
+// Templates cannot be migrated as running VMs.
+// Overridden by the plan `migrateTemplates`.
+func (r *Migration) template(vm *plan.VMStatus) (blocked bool) {
+	vm.DeleteCondition(VMTemplate)
+	if !vm.Template || r.Plan.Spec.MigrateTemplates {
+		return
+	}
+	blocked = true
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     VMTemplate,
+			Status:   True,
+			Category: Critical,
+			Reason:   NotSupported,
+			Message:  "The source VM is a template; set `migrateTemplates` to migrate it.",
+		})
+
+	return
+}
+
+//
+// Block the VM (disk transfer) when source networks or
+// storage used by the VM are not mapped. Otherwise, the
+// migration fails when the import is built.
+// Overridden by the plan `skipMappingValidation`.
+func (r *Migration) unmapped(vm *plan.VMStatus) (blocked bool, err error) {
+	vm.DeleteCondition(VMNotMapped)
+	if r.Plan.Spec.SkipMappingValidation {
+		return
+	}
+	list, err := r.builder.Unmapped(vm.Ref)
+	if err != nil {
+		return
+	}
+	if len(list) > 0 {
+		blocked = true
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     VMNotMapped,
+				Status:   True,
+				Category: Critical,
+				Reason:   NotSet,
+				Message:  "Source networks or storage used by the VM are not mapped.",
+				Items:    list,
+			})
+	}
+
+	return
+}
+
+//
+// Block the VM (disk transfer) while the source host
+// or datastores are in maintenance mode.
+// Reads may fail or be degraded during maintenance.
+// Overridden by the plan `allowMaintenanceMode`.
+func (r *Migration) maintenanceMode(vm *plan.VMStatus) (blocked bool, err error) {
+	vm.DeleteCondition(SourceInMaintenance)
+	if r.Plan.Spec.AllowMaintenanceMode {
+		return
 	}
-}
-
-//
-func (r *Migration) runningVMs() (vms []*plan.VMStatus) {
-	vms = make([]*plan.VMStatus, 0)
-	for i := range r.Plan.Status.Migration.VMs {
-		vm := r.Plan.Status.Migration.VMs[i]
-		if vm.Running() {
-			vms = append(vms, vm)
-		}
+	list, err := r.builder.MaintenanceMode(vm.Ref)
+	if err != nil {
+		return
+	}
+	if len(list) > 0 {
+		blocked = true
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     SourceInMaintenance,
+				Status:   True,
+				Category: Critical,
+				Reason:   InMaintenanceMode,
+				Message:  "The source host or datastores are in maintenance mode.",
+				Items:    list,
+			})
 	}
+
 	return
 }
 
 //
-// Get/Build resources.
-func (r *Migration) init() (err error) {
-	adapter, err := adapter.New(r.Context.Source.Provider)
+// Determine whether the VM inventory has not been validated.
+// The (policy) validation must have been run against the current
+// revision of the VM so that the concerns are current. The import
+// is blocked until validated unless unvalidated VMs are allowed.
+func (r *Migration) notValidated(vm *plan.VMStatus) (blocked bool, err error) {
+	vm.DeleteCondition(VMNotValidated)
+	if r.Plan.Spec.AllowUnvalidated {
+		return
+	}
+	object, err := r.Source.Inventory.VM(&vm.Ref)
 	if err != nil {
 		return
 	}
+	blocked = r.revisionNotValidated(vm, object)
 
-	r.builder, err = adapter.Builder(r.Context)
-	if err != nil {
-		err = liberr.Wrap(err)
+	return
+}
+
+//
+// Determine whether the (resolved) VM inventory has not
+// been validated.
+func (r *Migration) revisionNotValidated(vm *plan.VMStatus, object interface{}) (blocked bool) {
+	vm.DeleteCondition(VMNotValidated)
+	if r.Plan.Spec.AllowUnvalidated {
 		return
 	}
-	r.kubevirt = KubeVirt{
-		Context: r.Context,
-		Builder: r.builder,
+	var revision, validated int64
+	switch model := object.(type) {
+	case *vsphere.VM:
+		revision = model.Revision
+		validated = model.RevisionValidated
+	case *ovirt.VM:
+		revision = model.Revision
+		validated = model.RevisionValidated
+	default:
+		return
 	}
-	r.scheduler, err = scheduler.New(r.Context)
-	if err != nil {
+	if revision == validated {
 		return
 	}
+	blocked = true
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     VMNotValidated,
+			Status:   True,
+			Category: Critical,
+			Reason:   NotReady,
+			Message: fmt.Sprintf(
+				"The VM has not been validated: revision %d, validated revision %d.",
+				revision,
+				validated),
+		})
 
 	return
 }
 
 //
-// Next step in the itinerary.
-func (r *Migration) next(phase string) (next string) {
-	step, done, err := itinerary.Next(phase)
-	if done || err != nil {
-		next = Completed
-		if err != nil {
-			r.Log.Error(err, "Next phase failed.")
+// Determine whether the source VM has been deleted.
+// The VM is reported as deleted when the ref can no longer
+// be resolved in the inventory. Once deleted, the VM is not
+// looked up again.
+func (r *Migration) sourceDeleted(vm *plan.VMStatus) (deleted bool, err error) {
+	if vm.HasCondition(SourceDeleted) {
+		deleted = true
+		return
+	}
+	_, err = r.Source.Inventory.VM(&vm.Ref)
+	if err != nil {
+		if errors.As(err, &web.NotFoundError{}) {
+			r.markSourceDeleted(vm)
+			deleted = true
+			err = nil
 		}
-	} else {
-		next = step.Name
+		return
 	}
 
 	return
 }
 
 //
-// Begin the migration.
-func (r *Migration) begin() (err error) {
-	snapshot := r.Plan.Status.Migration.ActiveSnapshot()
-	if snapshot.HasAnyCondition(Executing, Succeeded, Failed, Canceled) {
-		return
-	}
-	r.Plan.Status.Migration.MarkReset()
-	r.Plan.Status.Migration.MarkStarted()
-	snapshot.SetCondition(
+// Report the source VM deleted.
+// The pipeline (progress) is preserved.
+func (r *Migration) markSourceDeleted(vm *plan.VMStatus) {
+	vm.SetCondition(
 		libcnd.Condition{
-			Type:     Executing,
+			Type:     SourceDeleted,
 			Status:   True,
-			Category: Advisory,
-			Message:  "The plan is EXECUTING.",
+			Category: Warn,
+			Reason:   NotFound,
+			Message:  "The source VM has been deleted.",
 			Durable:  true,
 		})
-	err = r.kubevirt.EnsureNamespace()
-	if err != nil {
-		err = liberr.Wrap(err)
-		return
-	}
-	//
-	// Delete
-	kept := []*plan.VMStatus{}
-	for _, status := range r.Plan.Status.Migration.VMs {
-
-		// resolve the VM ref
-		_, err = r.Source.Inventory.VM(&status.Ref)
-		if err != nil {
-			err = liberr.Wrap(err)
-			return
-		}
+	r.Log.Info(
+		"Source VM deleted.",
+		"vm",
+		vm.String())
+}
 
-		if _, found := r.Plan.Spec.FindVM(status.Ref); found {
-			kept = append(kept, status)
-		}
-	}
-	r.Plan.Status.Migration.VMs = kept
-	//
-	// Add/Update.
-	list := []*plan.VMStatus{}
-	for _, vm := range r.Plan.Spec.VMs {
-		var status *plan.VMStatus
-		itinerary.Predicate = &Predicate{vm: &vm}
-		step, _ := itinerary.First()
-		if current, found := r.Plan.Status.Migration.FindVM(vm.Ref); !found {
-			status = &plan.VMStatus{VM: vm}
-		} else {
-			status = current
+//
+// Storage classes assigned to the disks keyed by the
+// disk identifier. Reported on the DiskTransfer tasks.
+func (r *Migration) storageClasses(pipeline []*plan.Step) (classes map[string]string) {
+	for _, step := range pipeline {
+		if step.Name != DiskTransfer {
+			continue
 		}
-		if status.Phase != Completed || status.HasAnyCondition(Canceled, Failed) {
-			pipeline, pErr := r.buildPipeline(&vm)
-			if pErr != nil {
-				err = liberr.Wrap(pErr)
-				return
+		for _, task := range step.Tasks {
+			if class, found := task.Annotations[plan.AnnStorageClass]; found {
+				if classes == nil {
+					classes = map[string]string{}
+				}
+				classes[task.Name] = class
 			}
-			status.DeleteCondition(Canceled, Failed)
-			status.MarkReset()
-			status.Pipeline = pipeline
-			status.Phase = step.Name
-			status.Error = nil
-			status.Warm = nil
-			log.Info(
-				"Pipeline reset.",
-				"vm",
-				vm.String())
-		} else {
-			log.Info(
-				"Pipeline preserved.",
-				"vm",
-				vm.String())
 		}
-		list = append(list, status)
 	}
 
-	r.Plan.Status.Migration.VMs = list
-
-	r.Log.Info("Migration [STARTED]")
-
 	return
 }
 
 //
 // Build the pipeline for a VM status.
 func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error) {
-	itinerary.Predicate = &Predicate{vm: vm}
-	step, _ := itinerary.First()
+	if runner := r.forVM(vm); runner != r {
+		pipeline, err = runner.buildPipeline(vm)
+		return
+	}
+	itr := r.vmItinerary(vm)
+	step, _ := itr.First()
 	for {
 		switch step.Name {
 		case PreHook:
@@ -461,6 +1826,26 @@ func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error
 						Progress:    libitr.Progress{Total: 1},
 					},
 				})
+		case QuiesceGuest:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        QuiesceGuest,
+						Description: "Quiesce the guest file systems.",
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
+		case PreImportHook:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        PreImportHook,
+						Description: "Run hook before the import is created.",
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
 		case CreateImport:
 			tasks, pErr := r.builder.Tasks(vm.Ref)
 			if pErr != nil {
@@ -471,6 +1856,25 @@ func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error
 			for _, task := range tasks {
 				total += task.Progress.Total
 			}
+			if r.builder.TransferBackend(vm.Ref) == plan.BackendInPlace {
+				// converted in place.
+				pipeline = append(
+					pipeline,
+					&plan.Step{
+						Task: plan.Task{
+							Name:        DiskTransfer,
+							Description: "Transfer and convert disks (in place).",
+							Progress: libitr.Progress{
+								Total: total,
+							},
+							Annotations: map[string]string{
+								"unit": "MB",
+							},
+						},
+						Tasks: tasks,
+					})
+				break
+			}
 			pipeline = append(
 				pipeline,
 				&plan.Step{
@@ -486,8 +1890,9 @@ func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error
 					},
 					Tasks: tasks,
 				})
-			// only vSphere VMs require image conversion.
-			if r.Source.Provider.Type() == api.VSphere {
+			// only vSphere VMs require image conversion
+			// unless skipped (already compatible).
+			if r.Source.Provider.Type() == api.VSphere && !vm.SkipConversion {
 				pipeline = append(
 					pipeline,
 					&plan.Step{
@@ -498,6 +1903,16 @@ func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error
 						},
 					})
 			}
+		case PostImportHook:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        PostImportHook,
+						Description: "Run hook after the disk transfer and image conversion.",
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
 		case PostHook:
 			pipeline = append(
 				pipeline,
@@ -509,7 +1924,7 @@ func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error
 					},
 				})
 		}
-		next, done, _ := itinerary.Next(step.Name)
+		next, done, _ := itr.Next(step.Name)
 		if !done {
 			step = next
 		} else {
@@ -528,26 +1943,61 @@ func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error
 //
 // End the migration.
 func (r *Migration) end() (completed bool, err error) {
-	failed := 0
+	failed := []string{}
 	succeeded := 0
 	for _, vm := range r.Plan.Status.Migration.VMs {
 		if !vm.MarkedCompleted() {
 			return
 		}
 		if vm.HasCondition(Failed) {
-			failed++
+			failed = append(failed, vm.String())
 		}
 		if vm.HasCondition(Succeeded) {
 			succeeded++
 		}
 	}
+	transition := !r.Plan.Status.Migration.MarkedCompleted()
 	r.Plan.Status.Migration.MarkCompleted()
 	snapshot := r.Plan.Status.Migration.ActiveSnapshot()
 	snapshot.DeleteCondition(Executing)
 
-	if failed > 0 {
+	bestEffort := r.Plan.Spec.CompletionPolicy == api.CompletionBestEffort
+	if len(failed) > 0 && bestEffort && succeeded > 0 {
+		// best effort: the migration succeeded with
+		// failures. The successful VMs are kept and
+		// the failed VMs are cleaned up.
+		r.Log.Info(
+			"Migration [SUCCEEDED] with failures.",
+			"failed",
+			len(failed))
+		if transition {
+			r.recordPlanOutcome(Succeeded)
+			r.notify(Succeeded)
+		}
+		snapshot.SetCondition(
+			libcnd.Condition{
+				Type:     Succeeded,
+				Status:   True,
+				Category: Advisory,
+				Reason:   PartialSuccess,
+				Message: fmt.Sprintf(
+					"The plan execution has SUCCEEDED: %d of %d VMs FAILED.",
+					len(failed),
+					len(r.Plan.Status.Migration.VMs)),
+				Items:   failed,
+				Durable: true,
+			})
+		_, err = r.cancel()
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+	} else if len(failed) > 0 {
 		// if any VMs failed, the migration failed.
 		r.Log.Info("Migration [FAILED]")
+		if transition {
+			r.recordPlanOutcome(Failed)
+			r.notify(Failed)
+		}
 		snapshot.SetCondition(
 			libcnd.Condition{
 				Type:     Failed,
@@ -556,7 +2006,7 @@ func (r *Migration) end() (completed bool, err error) {
 				Message:  "The plan execution has FAILED.",
 				Durable:  true,
 			})
-		err = r.Cancel()
+		_, err = r.cancel()
 		if err != nil {
 			err = liberr.Wrap(err)
 		}
@@ -565,6 +2015,10 @@ func (r *Migration) end() (completed bool, err error) {
 		// if the migration didn't fail and at least one VM succeeded,
 		// then the migration succeeded.
 		r.Log.Info("Migration [SUCCEEDED]")
+		if transition {
+			r.recordPlanOutcome(Succeeded)
+			r.notify(Succeeded)
+		}
 		snapshot.SetCondition(
 			libcnd.Condition{
 				Type:     Succeeded,
@@ -578,6 +2032,10 @@ func (r *Migration) end() (completed bool, err error) {
 		// all the VMs are complete, then the migration must
 		// have been canceled.
 		r.Log.Info("Migration [CANCELED]")
+		if transition {
+			r.recordPlanOutcome(Canceled)
+			r.notify(Canceled)
+		}
 		snapshot.SetCondition(
 			libcnd.Condition{
 				Type:     Canceled,
@@ -593,15 +2051,51 @@ func (r *Migration) end() (completed bool, err error) {
 }
 
 //
-// Update VM migration status.
-func (r *Migration) updateVM(vm *plan.VMStatus) (err error) {
-	if r.importMap == nil {
-		r.importMap, err = r.kubevirt.ImportMap()
-		if err != nil {
-			err = liberr.Wrap(err)
-			return
+// Build the message for a blocked DataVolume.
+// The condition message is followed by the most recent
+// warning events for the DataVolume and importer pod.
+// Events are best effort.
+func (r *Migration) blockedMessage(dv DataVolume, cnd *libcnd.Condition) string {
+	messages := []string{}
+	if cnd.Message != "" {
+		messages = append(messages, cnd.Message)
+	}
+	events, err := r.kubevirt.DataVolumeEvents(dv.DataVolume)
+	if err != nil {
+		r.Log.Error(
+			err,
+			"List DataVolume events failed.",
+			"dv",
+			path.Join(
+				dv.Namespace,
+				dv.Name))
+	}
+	n := 0
+	for _, event := range events {
+		if n == MaxBlockedEvents {
+			break
 		}
+		if event.Type != core.EventTypeWarning {
+			continue
+		}
+		messages = append(
+			messages,
+			fmt.Sprintf(
+				"%s/%s: %s: %s",
+				event.InvolvedObject.Kind,
+				event.InvolvedObject.Name,
+				event.Reason,
+				event.Message))
+		n++
 	}
+
+	return strings.Join(messages, "; ")
+}
+
+//
+// Update VM migration status.
+// The import map is built by stepAll().
+func (r *Migration) updateVM(vm *plan.VMStatus) (err error) {
 	var imp VmImport
 	found := false
 	if imp, found = r.importMap[vm.ID]; !found {
@@ -610,7 +2104,7 @@ func (r *Migration) updateVM(vm *plan.VMStatus) (err error) {
 		return
 	}
 	r.updatePipeline(vm, &imp)
-	if imp.Spec.Warm {
+	if imp.Spec.Warm && r.Plan.Spec.VMWarm(&vm.VM) {
 		updateWarmStatus(vm, imp)
 	}
 
@@ -638,12 +2132,169 @@ func updateWarmStatus(vm *plan.VMStatus, imp VmImport) {
 			if len(vm.Warm.Precopies) == 0 || vm.Warm.Precopies[len(vm.Warm.Precopies)-1].End != nil {
 				vm.Warm.Precopies = append(vm.Warm.Precopies, plan.Precopy{Start: &cnd.LastTransitionTime})
 			}
+			updatePrecopyBytes(vm.Warm, imp.BytesTransferred())
 		case string(vmio.CopyingPaused):
 			if len(vm.Warm.Precopies) != 0 && vm.Warm.Precopies[len(vm.Warm.Precopies)-1].End == nil {
 				vm.Warm.Precopies[len(vm.Warm.Precopies)-1].End = &cnd.LastTransitionTime
 			}
 		}
 	}
+	for i := range vm.Warm.Precopies {
+		vm.Warm.Precopies[i].SetDuration()
+	}
+}
+
+//
+// Update the bytes transferred by the last precopy.
+// The importer reports the (estimated) bytes transferred by
+// all precopies. The bytes transferred by the precopy is the
+// difference from the previous precopy.
+func updatePrecopyBytes(warm *plan.Warm, transferred int64) {
+	n := len(warm.Precopies)
+	last := &warm.Precopies[n-1]
+	last.Transferred = transferred
+	last.Bytes = transferred
+	if n > 1 {
+		last.Bytes -= warm.Precopies[n-2].Transferred
+	}
+	if last.Bytes < 0 {
+		last.Bytes = 0
+	}
+}
+
+//
+// Cutover is triggered when the source VM, powered on when
+// the migration started, has been powered off (out-of-band).
+func (r *Migration) cutoverOnShutdown(vm *plan.VMStatus) (err error) {
+	if !r.Plan.Spec.CutoverOnSourceShutdown || vm.Warm == nil || vm.Warm.Cutover != nil {
+		return
+	}
+	if vm.SourcePowerState != plan.PowerOn {
+		return
+	}
+	powerState, err := r.builder.PowerState(vm.Ref)
+	if err != nil {
+		if errors.As(err, &web.ProviderNotReadyError{}) {
+			err = nil
+		}
+		return
+	}
+	if powerState != plan.PowerOff {
+		return
+	}
+	vm.Warm.Cutover = &meta.Time{Time: time.Now()}
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     CutoverForced,
+			Status:   True,
+			Category: Advisory,
+			Reason:   Modified,
+			Message:  "Cutover triggered by the source VM power off.",
+			Durable:  true,
+		})
+	r.Log.Info(
+		"Cutover triggered by source power off.",
+		"vm",
+		vm.String())
+
+	return
+}
+
+//
+// Determine whether the warm migration is ready for cutover.
+// Ready when the last (completed) precopy transferred no more
+// than the threshold and after the required number of successful
+// precopies. Only the bytes changed since the previous precopy are
+// compared so that large disks become ready once the churn is low.
+// The condition is cleared when the churn increases.
+// The estimated downtime is the duration of the last precopy.
+func (r *Migration) cutoverReady(vm *plan.VMStatus) {
+	threshold := int64(r.Plan.Spec.CutoverReadyThreshold)
+	precopies := r.Plan.Spec.CutoverReadyPrecopies
+	if vm.Warm == nil {
+		return
+	}
+	last, found := vm.Warm.LastPrecopy()
+	if !found {
+		vm.DeleteCondition(CutoverReady)
+		return
+	}
+	if last.Duration != nil {
+		downtime := *last.Duration
+		vm.Warm.EstimatedDowntime = &downtime
+	}
+	if threshold == 0 && precopies == 0 {
+		return
+	}
+	ready := vm.Warm.Successes >= precopies && last.Duration != nil
+	transferred := last.Bytes / 0x100000
+	if threshold > 0 && transferred > threshold {
+		ready = false
+	}
+	if !ready {
+		vm.DeleteCondition(CutoverReady)
+		return
+	}
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     CutoverReady,
+			Status:   True,
+			Category: Advisory,
+			Message: fmt.Sprintf(
+				"Ready for cutover. The last precopy transferred %d MB in %s.",
+				transferred,
+				last.Duration.Duration.Round(time.Second)),
+		})
+}
+
+//
+// Apply the warm migration precopy limits.
+// Cutover is forced after the max (successful) precopies and
+// the VM fails after the max consecutive precopy failures.
+func (r *Migration) precopyLimits(vm *plan.VMStatus) {
+	if vm.Warm == nil {
+		return
+	}
+	maxFailures := r.Plan.Spec.MaxConsecutiveFailures
+	if maxFailures > 0 && vm.Warm.ConsecutiveFailures >= maxFailures {
+		msg := fmt.Sprintf(
+			"Precopy failed %d consecutive times (limit: %d).",
+			vm.Warm.ConsecutiveFailures,
+			maxFailures)
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     PrecopyFailed,
+				Status:   True,
+				Category: Critical,
+				Reason:   NotValid,
+				Message:  msg,
+				Durable:  true,
+			})
+		vm.AddError(msg)
+		return
+	}
+	maxPrecopies := r.Plan.Spec.MaxPrecopies
+	if maxPrecopies > 0 && vm.Warm.Successes >= maxPrecopies && vm.Warm.Cutover == nil {
+		vm.Warm.Cutover = &meta.Time{Time: time.Now()}
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     CutoverForced,
+				Status:   True,
+				Category: Advisory,
+				Reason:   Modified,
+				Message: fmt.Sprintf(
+					"Cutover forced after %d successful precopies (limit: %d).",
+					vm.Warm.Successes,
+					maxPrecopies),
+				Durable: true,
+			})
+		r.Log.Info(
+			"Cutover forced.",
+			"vm",
+			vm.String(),
+			"precopies",
+			vm.Warm.Successes)
+	}
 }
 
 //
@@ -673,6 +2324,7 @@ func (r *Migration) updatePipeline(vm *plan.VMStatus, imp *VmImport) {
 				if cnd != nil && cnd.Status == False {
 					task.Phase = Blocked
 					task.Reason = cnd.Reason
+					task.Message = r.blockedMessage(dv, cnd)
 					tasksBlocked++
 					continue nextDv
 				}
@@ -683,7 +2335,9 @@ func (r *Migration) updatePipeline(vm *plan.VMStatus, imp *VmImport) {
 				task.MarkStarted()
 				task.Phase = Running
 				task.Reason = cnd.Reason
+				task.Message = ""
 				tasksRunning++
+				transferred := task.Progress.Completed
 				pct := dv.PercentComplete()
 				completed := pct * float64(task.Progress.Total)
 				task.Progress.Completed = int64(completed)
@@ -692,6 +2346,8 @@ func (r *Migration) updatePipeline(vm *plan.VMStatus, imp *VmImport) {
 					task.MarkCompleted()
 					tasksCompleted++
 				}
+				// Progress is reported in MB.
+				r.recordBytes((task.Progress.Completed - transferred) * 0x100000)
 			}
 			if tasksCompleted == len(step.Tasks) {
 				step.Phase = Completed
@@ -757,19 +2413,24 @@ func (r *Migration) updatePipeline(vm *plan.VMStatus, imp *VmImport) {
 type Predicate struct {
 	// VM listed on the plan.
 	vm *plan.VM
+	// Quiesce the guest.
+	quiesce bool
 }
 
 //
 // Evaluate predicate flags.
 func (r *Predicate) Evaluate(flag libitr.Flag) (allowed bool, err error) {
-	if len(r.vm.Hooks) == 0 {
-		return
-	}
 	switch flag {
 	case HasPreHook:
 		_, allowed = r.vm.FindHook(PreHook)
 	case HasPostHook:
 		_, allowed = r.vm.FindHook(PostHook)
+	case HasPreImportHook:
+		_, allowed = r.vm.FindHook(PreImportHook)
+	case HasPostImportHook:
+		_, allowed = r.vm.FindHook(PostImportHook)
+	case Quiesce:
+		allowed = r.quiesce
 	}
 
 	return
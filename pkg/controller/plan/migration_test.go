@@ -0,0 +1,625 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	libcnd "github.com/konveyor/controller/pkg/condition"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+	"time"
+)
+
+func TestBeginRetry(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "plan",
+		},
+	}
+	p.Spec.TargetNamespace = "test"
+	p.Spec.SkipMappingValidation = true
+	p.Spec.AllowMaintenanceMode = true
+	p.Spec.VMs = []plan.VM{
+		{Ref: ref.Ref{ID: "vm-1"}},
+		{Ref: ref.Ref{ID: "vm-2"}},
+	}
+	p.Status.Migration.NewSnapshot(plan.Snapshot{})
+	// Failed by a previous migration.
+	failed := &plan.VMStatus{
+		VM:    p.Spec.VMs[0],
+		Phase: Completed,
+	}
+	failed.MarkStarted()
+	failed.MarkCompleted()
+	failed.SetCondition(libcnd.Condition{Type: Failed, Status: True})
+	p.Status.Migration.VMs = []*plan.VMStatus{failed}
+	expected := p.Status.Migration.DeepCopy()
+	builder := &beginBuilder{}
+	inventory := &beginInventory{missing: "vm-2"}
+	ctx := &plancontext.Context{
+		Plan: p,
+		Log:  log,
+	}
+	ctx.Source.Provider = &api.Provider{
+		Spec: api.ProviderSpec{Type: api.OVirt},
+	}
+	ctx.Source.Inventory = inventory
+	ctx.Destination.Client = fake.NewFakeClientWithScheme(scheme.Scheme)
+	migration := Migration{
+		Context:  ctx,
+		builder:  builder,
+		kubevirt: KubeVirt{Context: ctx, Builder: builder},
+	}
+
+	// Failed partway: vm-2 not found.
+	// The migration is unchanged.
+	err := migration.begin()
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(p.Status.Migration).To(gomega.Equal(*expected))
+	g.Expect(migration.begun()).To(gomega.BeFalse())
+
+	// Retried.
+	inventory.missing = ""
+	err = migration.begin()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(migration.begun()).To(gomega.BeTrue())
+	g.Expect(p.Status.Migration.VMs).To(gomega.HaveLen(2))
+	vm := p.Status.Migration.VMs[0]
+	g.Expect(vm.MarkedStarted()).To(gomega.BeFalse())
+	g.Expect(vm.HasCondition(Failed)).To(gomega.BeFalse())
+	g.Expect(vm.Phase).To(gomega.Equal(Started))
+	g.Expect(vm.History).To(gomega.HaveLen(1))
+	g.Expect(vm.History[0].Outcome).To(gomega.Equal(Failed))
+	// Checked using the VMs found in batch.
+	g.Expect(inventory.found).To(gomega.Equal(0))
+	for _, vm := range p.Status.Migration.VMs {
+		g.Expect(vm.SourcePowerState).To(gomega.Equal(plan.PowerOn))
+		g.Expect(vm.Firmware).To(gomega.Equal(plan.FirmwareUEFI))
+	}
+
+	// Begun: a no-op.
+	begun := p.Status.Migration.DeepCopy()
+	err = migration.begin()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(p.Status.Migration).To(gomega.Equal(*begun))
+}
+
+func TestCutoverReady(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := &api.Plan{}
+	p.Spec.CutoverReadyThreshold = 100
+	p.Spec.CutoverReadyPrecopies = 2
+	migration := Migration{
+		Context: &plancontext.Context{
+			Plan: p,
+			Log:  log,
+		},
+	}
+	precopy := func(mb int64) plan.Precopy {
+		start := meta.NewTime(time.Now().Add(-time.Minute))
+		end := meta.Now()
+		precopy := plan.Precopy{Start: &start, End: &end, Bytes: mb * 0x100000}
+		precopy.SetDuration()
+		return precopy
+	}
+	vm := &plan.VMStatus{
+		Warm: &plan.Warm{
+			Successes: 1,
+			Precopies: []plan.Precopy{precopy(50)},
+		},
+	}
+	// Not enough precopies.
+	migration.cutoverReady(vm)
+	g.Expect(vm.HasCondition(CutoverReady)).To(gomega.BeFalse())
+	g.Expect(vm.Warm.EstimatedDowntime).ToNot(gomega.BeNil())
+	// Ready.
+	vm.Warm.Successes = 2
+	vm.Warm.Precopies = append(vm.Warm.Precopies, precopy(80))
+	migration.cutoverReady(vm)
+	g.Expect(vm.HasCondition(CutoverReady)).To(gomega.BeTrue())
+	// Churn increased.
+	vm.Warm.Successes = 3
+	vm.Warm.Precopies = append(vm.Warm.Precopies, precopy(500))
+	migration.cutoverReady(vm)
+	g.Expect(vm.HasCondition(CutoverReady)).To(gomega.BeFalse())
+}
+
+func TestEndBestEffort(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	err := vmio.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+	err = cdi.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "plan",
+			UID:       "p1234567-0000",
+		},
+	}
+	p.Spec.TargetNamespace = "test"
+	p.Spec.CompletionPolicy = api.CompletionBestEffort
+	p.Status.Migration.NewSnapshot(plan.Snapshot{})
+	// Not a transition: no metrics or notification.
+	p.Status.Migration.MarkCompleted()
+	ctx := &plancontext.Context{
+		Plan: p,
+		Migration: &api.Migration{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: "test",
+				Name:      "migration",
+				UID:       "m1234567-0000",
+			},
+		},
+		Log: log,
+	}
+	ctx.Source.Provider = &api.Provider{
+		Spec: api.ProviderSpec{Type: api.OVirt},
+	}
+	kubevirt := KubeVirt{Context: ctx, Builder: &fakeBuilder{}}
+	objects := []runtime.Object{}
+	outcomes := []string{Succeeded, Failed, Succeeded}
+	for i, outcome := range outcomes {
+		vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: fmt.Sprintf("vm-%d", i)}}}
+		vm.MarkStarted()
+		vm.MarkCompleted()
+		vm.SetCondition(libcnd.Condition{Type: outcome, Status: True})
+		p.Status.Migration.VMs = append(p.Status.Migration.VMs, vm)
+		objects = append(
+			objects,
+			&vmio.VirtualMachineImport{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      vm.ID,
+					Labels:    kubevirt.vmLabels(vm.Ref),
+				},
+				Status: vmio.VirtualMachineImportStatus{
+					DataVolumes: []vmio.DataVolumeItem{
+						{Name: vm.ID + "-dv"},
+					},
+				},
+			},
+			&cdi.DataVolume{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      vm.ID + "-dv",
+				},
+			})
+	}
+	ctx.Destination.Client = fake.NewFakeClientWithScheme(scheme.Scheme, objects...)
+	migration := Migration{
+		Context:  ctx,
+		kubevirt: kubevirt,
+	}
+	completed, err := migration.end()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(completed).To(gomega.BeTrue())
+	snapshot := p.Status.Migration.ActiveSnapshot()
+	g.Expect(snapshot.HasCondition(Failed)).To(gomega.BeFalse())
+	succeeded := snapshot.FindCondition(Succeeded)
+	g.Expect(succeeded).ToNot(gomega.BeNil())
+	g.Expect(succeeded.Reason).To(gomega.Equal(PartialSuccess))
+	g.Expect(succeeded.Items).To(gomega.HaveLen(1))
+	// The failed VM is cleaned up.
+	// The successful VMs are kept.
+	for i, vm := range p.Status.Migration.VMs {
+		failed := outcomes[i] == Failed
+		g.Expect(vm.Cleaned).To(gomega.Equal(failed))
+		_, found, err := kubevirt.findImport(vm)
+		g.Expect(err).To(gomega.BeNil())
+		g.Expect(found).To(gomega.Equal(!failed))
+		err = ctx.Destination.Client.Get(
+			context.TODO(),
+			client.ObjectKey{Namespace: "test", Name: vm.ID + "-dv"},
+			&cdi.DataVolume{})
+		g.Expect(k8serr.IsNotFound(err)).To(gomega.Equal(failed))
+	}
+}
+
+func TestStepAdditionalSource(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	err := vmio.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+	err = cdi.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+
+	other := core.ObjectReference{Namespace: "test", Name: "other"}
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "plan",
+			UID:       "p1234567-0000",
+		},
+	}
+	p.Spec.TargetNamespace = "test"
+	p.Spec.Provider.Source = core.ObjectReference{Namespace: "test", Name: "source"}
+	p.Spec.SkipMappingValidation = true
+	p.Spec.AllowMaintenanceMode = true
+	p.Spec.AllowUnvalidated = true
+	vm := &plan.VMStatus{
+		VM: plan.VM{
+			Ref:      ref.Ref{ID: "vm-1", Name: "vm1"},
+			Provider: &other,
+		},
+		Phase: CreateImport,
+	}
+	vm.MarkStarted()
+	p.Spec.VMs = []plan.VM{vm.VM}
+	p.Status.Migration.VMs = []*plan.VMStatus{vm}
+	destination := fake.NewFakeClientWithScheme(scheme.Scheme)
+	newContext := func(provider core.ObjectReference, pType string, inventory web.Client) *plancontext.Context {
+		ctx := &plancontext.Context{
+			Plan: p,
+			Migration: &api.Migration{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      "migration",
+					UID:       "m1234567-0000",
+				},
+			},
+			Log: log,
+		}
+		ctx.Source.Provider = &api.Provider{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: provider.Namespace,
+				Name:      provider.Name,
+			},
+			Spec: api.ProviderSpec{Type: pType},
+		}
+		ctx.Source.Inventory = inventory
+		ctx.Destination.Client = destination
+		return ctx
+	}
+	// The VM is not found by the plan source.
+	ctx := newContext(p.Spec.Provider.Source, api.VSphere, &notFoundInventory{})
+	migration := Migration{
+		Context:  ctx,
+		builder:  &fakeBuilder{},
+		kubevirt: KubeVirt{Context: ctx, Builder: &fakeBuilder{}},
+		sources: map[string]KubeVirt{
+			"test/other": {
+				Context: newContext(other, api.OVirt, &fakeInventory{}),
+				Builder: &fakeBuilder{},
+			},
+		},
+	}
+	runner := migration.forVM(&vm.VM)
+	g.Expect(runner).ToNot(gomega.BeIdenticalTo(&migration))
+	g.Expect(runner.forVM(&vm.VM)).To(gomega.BeIdenticalTo(runner))
+	g.Expect(runner.Source.Provider.Type()).To(gomega.Equal(api.OVirt))
+
+	// Stepped using the additional source.
+	err = migration.step(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(vm.HasCondition(SourceDeleted)).To(gomega.BeFalse())
+	g.Expect(vm.Error).To(gomega.BeNil())
+	g.Expect(vm.Phase).To(gomega.Equal(ImportCreated))
+	imports := &vmio.VirtualMachineImportList{}
+	err = destination.List(context.TODO(), imports)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(imports.Items).To(gomega.HaveLen(1))
+}
+
+//
+// Fake inventory: VMs not found.
+type notFoundInventory struct {
+	web.Client
+}
+
+func (r *notFoundInventory) VM(ref *ref.Ref) (object interface{}, err error) {
+	err = web.NotFoundError{Ref: *ref}
+	return
+}
+
+func TestPowerOnFailed(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	err := vmio.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+
+	p := &api.Plan{}
+	p.Spec.PowerOnAfterMigration = api.PowerOnAlways
+	vm := &plan.VMStatus{
+		VM:    plan.VM{Ref: ref.Ref{ID: "vm-1"}},
+		Phase: Completed,
+	}
+	vm.MarkStarted()
+	ctx := &plancontext.Context{
+		Plan:      p,
+		Migration: &api.Migration{},
+		Log:       log,
+	}
+	ctx.Source.Provider = &api.Provider{
+		Spec: api.ProviderSpec{Type: api.VSphere},
+	}
+	// The target VM cannot be found.
+	ctx.Destination.Client = fake.NewFakeClientWithScheme(scheme.Scheme)
+	migration := Migration{
+		Context:  ctx,
+		kubevirt: KubeVirt{Context: ctx},
+	}
+	err = migration.step(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(vm.MarkedCompleted()).To(gomega.BeTrue())
+	g.Expect(vm.Error).To(gomega.BeNil())
+	cnd := vm.FindCondition(PowerOnFailed)
+	g.Expect(cnd).ToNot(gomega.BeNil())
+	g.Expect(cnd.Category).To(gomega.Equal(Warn))
+	g.Expect(cnd.Durable).To(gomega.BeTrue())
+	g.Expect(vm.HasCondition(Failed)).To(gomega.BeFalse())
+}
+
+func TestQuiesceGuest(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := &plan.VMStatus{
+		VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}},
+		Pipeline: []*plan.Step{
+			{Task: plan.Task{Name: QuiesceGuest}},
+		},
+	}
+	builder := &quiesceBuilder{polled: 2}
+	migration := Migration{
+		Context: &plancontext.Context{
+			Plan: &api.Plan{},
+			Log:  log,
+		},
+		builder: builder,
+	}
+	// Started.
+	g.Expect(migration.quiesceGuest(vm)).To(gomega.BeFalse())
+	step, _ := vm.FindStep(QuiesceGuest)
+	g.Expect(step.Annotations[plan.AnnQuiesceTask]).To(gomega.Equal("task-0"))
+	// Polled (snapshot created; removal started).
+	g.Expect(migration.quiesceGuest(vm)).To(gomega.BeFalse())
+	g.Expect(step.Annotations[plan.AnnQuiesceTask]).To(gomega.Equal("task-1"))
+	// Completed.
+	g.Expect(migration.quiesceGuest(vm)).To(gomega.BeTrue())
+	g.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+	g.Expect(step.Error).To(gomega.BeNil())
+	g.Expect(step.Progress.Completed).To(gomega.Equal(int64(1)))
+	g.Expect(step.Annotations).ToNot(gomega.HaveKey(plan.AnnQuiesceTask))
+	g.Expect(vm.HasCondition(GuestNotQuiesced)).To(gomega.BeFalse())
+	// Timed out.
+	vm.Pipeline[0] = &plan.Step{Task: plan.Task{Name: QuiesceGuest}}
+	builder.polled = 100
+	g.Expect(migration.quiesceGuest(vm)).To(gomega.BeFalse())
+	step, _ = vm.FindStep(QuiesceGuest)
+	step.Started.Time = step.Started.Add(-QuiesceTimeout - time.Second)
+	g.Expect(migration.quiesceGuest(vm)).To(gomega.BeTrue())
+	g.Expect(step.Error).ToNot(gomega.BeNil())
+}
+
+func TestStepAllConcurrent(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	err := vmio.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+	err = cdi.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+	saved := Settings.Migration.StepWorkers
+	Settings.Migration.StepWorkers = 4
+	defer func() {
+		Settings.Migration.StepWorkers = saved
+	}()
+
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "plan",
+			UID:       "p1234567-0000",
+		},
+	}
+	p.Spec.TargetNamespace = "test"
+	ctx := &plancontext.Context{
+		Plan: p,
+		Migration: &api.Migration{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: "test",
+				Name:      "migration",
+				UID:       "m1234567-0000",
+			},
+		},
+		Log: log,
+	}
+	ctx.Source.Provider = &api.Provider{
+		Spec: api.ProviderSpec{Type: api.OVirt},
+	}
+	ctx.Source.Inventory = &fakeInventory{}
+	ctx.Destination.Client = fake.NewFakeClientWithScheme(scheme.Scheme)
+	migration := Migration{
+		Context:  ctx,
+		builder:  &fakeBuilder{},
+		kubevirt: KubeVirt{Context: ctx, Builder: &fakeBuilder{}},
+	}
+	vms := []*plan.VMStatus{}
+	for i := 0; i < 8; i++ {
+		vm := &plan.VMStatus{
+			VM: plan.VM{
+				Ref: ref.Ref{
+					ID:   fmt.Sprintf("vm-%d", i),
+					Name: fmt.Sprintf("vm%d", i),
+				},
+			},
+			Phase: ImportCreated,
+		}
+		vm.MarkStarted()
+		err = migration.kubevirt.EnsureImport(vm)
+		g.Expect(err).To(gomega.BeNil())
+		p.Spec.VMs = append(p.Spec.VMs, vm.VM)
+		vms = append(vms, vm)
+	}
+	p.Status.Migration.VMs = vms
+
+	// The import map is shared (read-only) by the workers.
+	err = migration.stepAll(vms)
+	g.Expect(err).To(gomega.BeNil())
+	for _, vm := range vms {
+		g.Expect(vm.Error).To(gomega.BeNil())
+		g.Expect(vm.Phase).To(gomega.Equal(ImportCreated))
+	}
+	g.Expect(migration.importMap).To(gomega.HaveLen(len(vms)))
+}
+
+func TestUpdatePrecopyBytes(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	gb := int64(0x40000000)
+	warm := &plan.Warm{
+		Precopies: []plan.Precopy{{}},
+	}
+	// First precopy: the full disk.
+	updatePrecopyBytes(warm, 10*gb)
+	g.Expect(warm.Precopies[0].Bytes).To(gomega.Equal(10 * gb))
+	g.Expect(warm.Precopies[0].Transferred).To(gomega.Equal(10 * gb))
+	// Later precopies: the difference.
+	warm.Precopies = append(warm.Precopies, plan.Precopy{})
+	updatePrecopyBytes(warm, 10*gb+0x100000)
+	g.Expect(warm.Precopies[1].Bytes).To(gomega.Equal(int64(0x100000)))
+	g.Expect(warm.Precopies[1].Transferred).To(gomega.Equal(10*gb + 0x100000))
+	// Updated while in progress.
+	updatePrecopyBytes(warm, 10*gb+0x200000)
+	g.Expect(warm.Precopies[1].Bytes).To(gomega.Equal(int64(0x200000)))
+	g.Expect(warm.Precopies[0].Bytes).To(gomega.Equal(10 * gb))
+}
+
+func TestCutoverReadyLargeDisk(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := &api.Plan{}
+	p.Spec.CutoverReadyThreshold = 100
+	p.Spec.CutoverReadyPrecopies = 2
+	migration := Migration{
+		Context: &plancontext.Context{
+			Plan: p,
+			Log:  log,
+		},
+	}
+	gb := int64(0x40000000)
+	vm := &plan.VMStatus{
+		Warm: &plan.Warm{},
+	}
+	precopy := func(transferred int64) {
+		start := meta.NewTime(time.Now().Add(-time.Minute))
+		end := meta.Now()
+		vm.Warm.Precopies = append(vm.Warm.Precopies, plan.Precopy{Start: &start, End: &end})
+		updatePrecopyBytes(vm.Warm, transferred)
+		vm.Warm.Precopies[len(vm.Warm.Precopies)-1].SetDuration()
+		vm.Warm.Successes++
+	}
+	// Full copy of a 40 GB disk.
+	precopy(40 * gb)
+	migration.cutoverReady(vm)
+	g.Expect(vm.HasCondition(CutoverReady)).To(gomega.BeFalse())
+	// 2 GB changed.
+	precopy(42 * gb)
+	migration.cutoverReady(vm)
+	g.Expect(vm.HasCondition(CutoverReady)).To(gomega.BeFalse())
+	// 50 MB changed.
+	precopy(42*gb + 50*0x100000)
+	migration.cutoverReady(vm)
+	g.Expect(vm.HasCondition(CutoverReady)).To(gomega.BeTrue())
+	cnd := vm.FindCondition(CutoverReady)
+	g.Expect(cnd.Message).To(gomega.ContainSubstring("transferred 50 MB"))
+}
+
+//
+// Fake inventory: oVirt VMs found (in batch).
+// The VMs are listed as summaries unless the detail
+// is requested. The VMs are counted when found individually.
+type beginInventory struct {
+	web.Client
+	// VM not found.
+	missing string
+	// Number of VMs found individually.
+	found int
+}
+
+func (r *beginInventory) VM(ref *ref.Ref) (object interface{}, err error) {
+	r.found++
+	object = &ovirt.VM{}
+	return
+}
+
+func (r *beginInventory) VMs(refs []*ref.Ref) (found map[ref.Ref]interface{}, err error) {
+	finder := &ovirt.Finder{Client: r}
+	found, err = finder.VMs(refs)
+	return
+}
+
+func (r *beginInventory) List(list interface{}, param ...web.Param) (err error) {
+	detail := false
+	for _, p := range param {
+		if p.Key == ovirt.DetailParam && p.Value == "1" {
+			detail = true
+		}
+	}
+	vms := list.(*[]ovirt.VM)
+	for _, id := range []string{"vm-1", "vm-2"} {
+		if id == r.missing {
+			continue
+		}
+		vm := ovirt.VM{}
+		vm.ID = id
+		vm.Name = id
+		if detail {
+			vm.Status = "up"
+			vm.BIOS = "q35_ovmf"
+		}
+		*vms = append(*vms, vm)
+	}
+	return
+}
+
+//
+// Fake builder.
+type beginBuilder struct {
+	fakeBuilder
+}
+
+func (r *beginBuilder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
+	return
+}
+
+func (r *beginBuilder) TransferBackend(vmRef ref.Ref) string {
+	return plan.BackendCDI
+}
+
+type quiesceBuilder struct {
+	fakeBuilder
+	polled int
+	next   int
+}
+
+func (r *quiesceBuilder) Quiesce(vmRef ref.Ref) (task string, quiesced bool, err error) {
+	task = fmt.Sprintf("task-%d", r.next)
+	quiesced = true
+	return
+}
+
+func (r *quiesceBuilder) QuiesceTask(vmRef ref.Ref, task string) (next string, done bool, err error) {
+	r.next++
+	if r.next >= r.polled {
+		done = true
+		return
+	}
+	next = fmt.Sprintf("task-%d", r.next)
+	return
+}
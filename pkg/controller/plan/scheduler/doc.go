@@ -20,6 +20,33 @@ type Scheduler interface {
 //
 // Scheduler factory.
 func New(ctx *plancontext.Context) (scheduler Scheduler, err error) {
+	if len(ctx.Plan.Referenced.Sources) > 0 {
+		sources := &Sources{}
+		err = sources.build(ctx)
+		if err != nil {
+			return
+		}
+		scheduler = sources
+	} else {
+		scheduler, err = forProvider(ctx)
+		if err != nil {
+			return
+		}
+	}
+	if scheduler != nil && settings.Settings.MaxInFlightGlobal > 0 {
+		scheduler = &Global{
+			Scheduler:   scheduler,
+			Context:     ctx,
+			MaxInFlight: settings.Settings.MaxInFlightGlobal,
+		}
+	}
+
+	return
+}
+
+//
+// Provider scheduler factory.
+func forProvider(ctx *plancontext.Context) (scheduler Scheduler, err error) {
 	switch ctx.Source.Provider.Type() {
 	case api.VSphere:
 		scheduler = &vsphere.Scheduler{
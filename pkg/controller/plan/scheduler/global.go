@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"context"
+	liberr "github.com/konveyor/controller/pkg/error"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"sync"
+)
+
+//
+// Package level mutex to ensure that
+// multiple concurrent reconciles don't
+// exceed the (global) limit.
+var mutex sync.Mutex
+
+//
+// Global (controller-wide) scheduler.
+// Limits the number of VMs in-flight across all plans
+// (and providers) before delegating to the provider
+// scheduler. The VMs in-flight are counted using the
+// status of the executing plans so the count drops as
+// VMs complete (succeeded, failed or canceled).
+type Global struct {
+	// Provider scheduler.
+	Scheduler
+	*plancontext.Context
+	// Maximum number of VMs in-flight across all plans.
+	MaxInFlight int
+}
+
+//
+// Return the next VM to migrate.
+// No VM is returned while the limit is reached.
+func (r *Global) Next() (vm *plan.VMStatus, hasNext bool, err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	inFlight, err := r.inFlight()
+	if err != nil {
+		return
+	}
+	if inFlight >= r.MaxInFlight {
+		r.Log.V(1).Info(
+			"Global in-flight limit reached.",
+			"inflight",
+			inFlight,
+			"limit",
+			r.MaxInFlight)
+		return
+	}
+
+	vm, hasNext, err = r.Scheduler.Next()
+
+	return
+}
+
+//
+// Count the VMs in-flight across all executing plans.
+func (r *Global) inFlight() (count int, err error) {
+	// Since we modify the plan VMStatuses in memory,
+	// we need to use the plan from the context rather
+	// than from the list of plans that are retrieved below.
+	count = running(r.Plan)
+	planList := &api.PlanList{}
+	err = r.List(context.TODO(), planList)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range planList.Items {
+		p := &planList.Items[i]
+		if p.Namespace == r.Plan.Namespace && p.Name == r.Plan.Name {
+			continue
+		}
+		snapshot := p.Status.Migration.ActiveSnapshot()
+		if !snapshot.HasCondition("Executing") {
+			continue
+		}
+		count += running(p)
+	}
+
+	return
+}
+
+//
+// The number of VMs running (started and not completed).
+func running(p *api.Plan) (count int) {
+	for _, vmStatus := range p.Status.Migration.VMs {
+		if vmStatus.Running() {
+			count++
+		}
+	}
+
+	return
+}
@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	libcnd "github.com/konveyor/controller/pkg/condition"
+	"github.com/konveyor/controller/pkg/logging"
+	"github.com/konveyor/forklift-controller/pkg/apis"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+type fakeScheduler struct {
+	called bool
+}
+
+func (r *fakeScheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
+	r.called = true
+	vm = &plan.VMStatus{}
+	hasNext = true
+	return
+}
+
+func TestGlobalScheduler(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	err := apis.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+
+	newPlan := func(name string, running int, executing bool) *api.Plan {
+		p := &api.Plan{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: "test",
+				Name:      name,
+			},
+		}
+		for i := 0; i < running; i++ {
+			vm := &plan.VMStatus{
+				VM: plan.VM{
+					Ref: ref.Ref{ID: name + "-vm"},
+				},
+			}
+			vm.MarkStarted()
+			p.Status.Migration.VMs = append(p.Status.Migration.VMs, vm)
+		}
+		// Completed VMs are not counted.
+		completed := &plan.VMStatus{}
+		completed.MarkStarted()
+		completed.MarkCompleted()
+		p.Status.Migration.VMs = append(p.Status.Migration.VMs, completed)
+		snapshot := plan.Snapshot{}
+		if executing {
+			snapshot.SetCondition(
+				libcnd.Condition{
+					Type:   "Executing",
+					Status: libcnd.True,
+				})
+		}
+		p.Status.Migration.NewSnapshot(snapshot)
+		return p
+	}
+	newGlobal := func(max int) (global *Global, provider *fakeScheduler) {
+		provider = &fakeScheduler{}
+		global = &Global{
+			Scheduler: provider,
+			Context: &plancontext.Context{
+				Client: fake.NewFakeClientWithScheme(
+					scheme.Scheme,
+					newPlan("a", 2, true),
+					newPlan("b", 3, false)),
+				Plan: newPlan("c", 1, true),
+				Log:  logging.WithName("test"),
+			},
+			MaxInFlight: max,
+		}
+		return
+	}
+
+	// Below the limit.
+	global, provider := newGlobal(4)
+	_, hasNext, err := global.Next()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(hasNext).To(gomega.BeTrue())
+	g.Expect(provider.called).To(gomega.BeTrue())
+
+	// Limit reached.
+	global, provider = newGlobal(3)
+	_, hasNext, err = global.Next()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(hasNext).To(gomega.BeFalse())
+	g.Expect(provider.called).To(gomega.BeFalse())
+}
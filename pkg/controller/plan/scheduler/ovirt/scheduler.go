@@ -59,7 +59,7 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 		return
 	}
 
-	for _, vmStatus := range r.Plan.Status.Migration.VMs {
+	for _, vmStatus := range plan.Prioritized(r.Plan.Status.Migration.VMs) {
 		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
 			vm = vmStatus
 			hasNext = true
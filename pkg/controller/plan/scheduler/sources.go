@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	liberr "github.com/konveyor/controller/pkg/error"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	core "k8s.io/api/core/v1"
+)
+
+//
+// Scheduler for plans with additional source providers.
+// The VMs of each provider are scheduled by the provider
+// scheduler using a (shallow) copy of the plan listing
+// only the VMs of the provider. The in-flight limit is
+// applied per provider.
+type Sources struct {
+	// Provider schedulers.
+	// The plan source provider is first.
+	Schedulers []Scheduler
+}
+
+//
+// Return the next VM to migrate.
+// The providers are considered in order.
+func (r *Sources) Next() (vm *plan.VMStatus, hasNext bool, err error) {
+	for _, scheduler := range r.Schedulers {
+		vm, hasNext, err = scheduler.Next()
+		if err != nil || hasNext {
+			return
+		}
+	}
+
+	return
+}
+
+//
+// Build the provider schedulers.
+func (r *Sources) build(ctx *plancontext.Context) (err error) {
+	contexts := []*plancontext.Context{ctx}
+	for _, source := range ctx.Plan.Referenced.Sources {
+		sourceCtx, wErr := ctx.WithSource(source)
+		if wErr != nil {
+			err = liberr.Wrap(wErr)
+			return
+		}
+		contexts = append(contexts, sourceCtx)
+	}
+	for _, sourceCtx := range contexts {
+		copied := *sourceCtx
+		copied.Plan = r.plan(sourceCtx)
+		scheduler, pErr := forProvider(&copied)
+		if pErr != nil {
+			err = pErr
+			return
+		}
+		if scheduler != nil {
+			r.Schedulers = append(r.Schedulers, scheduler)
+		}
+	}
+
+	return
+}
+
+//
+// A (shallow) copy of the plan with the source
+// provider and only the VMs of the provider.
+func (r *Sources) plan(ctx *plancontext.Context) (copied *api.Plan) {
+	provider := ctx.Source.Provider
+	p := *ctx.Plan
+	p.Spec.Provider.Source = core.ObjectReference{
+		Namespace: provider.Namespace,
+		Name:      provider.Name,
+	}
+	p.Status.Migration.VMs = nil
+	for _, vm := range ctx.Plan.Status.Migration.VMs {
+		ref := ctx.Plan.Spec.VMProvider(&vm.VM)
+		if ref.Namespace == provider.Namespace && ref.Name == provider.Name {
+			p.Status.Migration.VMs = append(p.Status.Migration.VMs, vm)
+		}
+	}
+	copied = &p
+
+	return
+}
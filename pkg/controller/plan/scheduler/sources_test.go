@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+type idleScheduler struct {
+	called bool
+}
+
+func (r *idleScheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
+	r.called = true
+	return
+}
+
+func TestSourcesScheduler(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// The providers are considered in order.
+	idle := &idleScheduler{}
+	next := &fakeScheduler{}
+	last := &fakeScheduler{}
+	scheduler := Sources{
+		Schedulers: []Scheduler{idle, next, last},
+	}
+	_, hasNext, err := scheduler.Next()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(hasNext).To(gomega.BeTrue())
+	g.Expect(idle.called).To(gomega.BeTrue())
+	g.Expect(next.called).To(gomega.BeTrue())
+	g.Expect(last.called).To(gomega.BeFalse())
+
+	// The plan (copy) lists only the VMs of the provider.
+	other := core.ObjectReference{Namespace: "test", Name: "other"}
+	p := &api.Plan{}
+	p.Spec.Provider.Source = core.ObjectReference{Namespace: "test", Name: "source"}
+	p.Status.Migration.VMs = []*plan.VMStatus{
+		{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}},
+		{VM: plan.VM{Ref: ref.Ref{ID: "vm-2"}, Provider: &other}},
+	}
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: other.Namespace,
+			Name:      other.Name,
+		},
+	}
+	ctx := &plancontext.Context{Plan: p}
+	ctx.Source.Provider = provider
+	copied := scheduler.plan(ctx)
+	g.Expect(copied.Spec.Provider.Source).To(gomega.Equal(other))
+	g.Expect(copied.Status.Migration.VMs).To(gomega.HaveLen(1))
+	g.Expect(copied.Status.Migration.VMs[0]).To(gomega.BeIdenticalTo(p.Status.Migration.VMs[1]))
+	g.Expect(p.Status.Migration.VMs).To(gomega.HaveLen(2))
+}
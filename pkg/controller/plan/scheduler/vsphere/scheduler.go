@@ -42,6 +42,8 @@ type Scheduler struct {
 type pendingVM struct {
 	status *plan.VMStatus
 	cost   int
+	// Position in the (prioritized) schedule.
+	index int
 }
 
 //
@@ -53,12 +55,7 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 	if err != nil {
 		return
 	}
-	for _, vms := range r.schedulable() {
-		if len(vms) > 0 {
-			vm = vms[0].status
-			hasNext = true
-		}
-	}
+	vm, hasNext = r.next(r.schedulable())
 
 	if hasNext {
 		r.Log.Info(
@@ -162,10 +159,11 @@ func (r *Scheduler) buildInFlight() (err error) {
 
 //
 // Build the map of pending VMs belonging to each host.
+// The VMs are ordered by scheduling priority.
 func (r *Scheduler) buildPending() (err error) {
 	r.pending = make(map[string][]*pendingVM)
 
-	for _, vmStatus := range r.Plan.Status.Migration.VMs {
+	for i, vmStatus := range plan.Prioritized(r.Plan.Status.Migration.VMs) {
 		vm := &model.VM{}
 		err = r.Source.Inventory.Find(vm, vmStatus.Ref)
 		if err != nil {
@@ -176,6 +174,7 @@ func (r *Scheduler) buildPending() (err error) {
 			pending := &pendingVM{
 				status: vmStatus,
 				cost:   len(vm.Disks),
+				index:  i,
 			}
 			r.pending[vm.Host] = append(r.pending[vm.Host], pending)
 		}
@@ -183,6 +182,27 @@ func (r *Scheduler) buildPending() (err error) {
 	return
 }
 
+//
+// Select the next VM from the schedulable VMs.
+// The first (by priority) schedulable VM is selected.
+func (r *Scheduler) next(schedulable map[string][]*pendingVM) (vm *plan.VMStatus, hasNext bool) {
+	var selected *pendingVM
+	for _, vms := range schedulable {
+		if len(vms) == 0 {
+			continue
+		}
+		if selected == nil || vms[0].index < selected.index {
+			selected = vms[0]
+		}
+	}
+	if selected != nil {
+		vm = selected.status
+		hasNext = true
+	}
+
+	return
+}
+
 //
 // Return a map of all the VMs that could be scheduled
 // based on the available host capacities.
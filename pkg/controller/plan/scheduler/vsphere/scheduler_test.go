@@ -1,6 +1,8 @@
 package vsphere
 
 import (
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	"github.com/onsi/gomega"
 	"testing"
 )
@@ -98,3 +100,35 @@ func TestScheduler(t *testing.T) {
 	}
 	g.Expect(scheduler.schedulable()).To(gomega.Equal(expectedSchedule))
 }
+
+func TestSchedulerNext(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	high := 90
+	vmA := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vmA"}}}
+	vmB := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vmB"}, Priority: &high}}
+	vmC := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vmC"}}}
+
+	// Ordered by priority (highest first) and
+	// then by list order.
+	prioritized := plan.Prioritized([]*plan.VMStatus{vmA, vmB, vmC})
+	g.Expect(prioritized).To(gomega.Equal([]*plan.VMStatus{vmB, vmA, vmC}))
+
+	// The first (by priority) schedulable VM
+	// is selected across hosts.
+	scheduler := Scheduler{MaxInFlight: 10}
+	vm, hasNext := scheduler.next(
+		map[string][]*pendingVM{
+			"hostA": {
+				{status: vmA, index: 1},
+			},
+			"hostB": {
+				{status: vmC, index: 2},
+			},
+		})
+	g.Expect(hasNext).To(gomega.BeTrue())
+	g.Expect(vm).To(gomega.Equal(vmA))
+
+	_, hasNext = scheduler.next(map[string][]*pendingVM{})
+	g.Expect(hasNext).To(gomega.BeFalse())
+}
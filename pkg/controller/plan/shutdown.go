@@ -0,0 +1,74 @@
+package plan
+
+import (
+	"sync"
+	"time"
+)
+
+//
+// Graceful shutdown (singleton).
+var Shutdown = &GracefulShutdown{}
+
+//
+// Graceful shutdown.
+// When the manager is stopped, reconciles in progress are given
+// time to finish and persist the plan status. While stopping, new
+// reconciles are not started and a running migration stops
+// stepping (and scheduling) VMs so the status of the VMs already
+// stepped is persisted when the reconcile returns.
+type GracefulShutdown struct {
+	mutex sync.Mutex
+	// Reconciles in progress.
+	inProgress sync.WaitGroup
+	// Stopping.
+	stopping bool
+}
+
+//
+// Begin a reconcile.
+// Returns false when stopping.
+func (r *GracefulShutdown) Begin() (ok bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.stopping {
+		return
+	}
+	r.inProgress.Add(1)
+	ok = true
+	return
+}
+
+//
+// End a reconcile.
+func (r *GracefulShutdown) End() {
+	r.inProgress.Done()
+}
+
+//
+// Stopping.
+func (r *GracefulShutdown) Stopping() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.stopping
+}
+
+//
+// Stop and wait for the reconciles in progress to end.
+// Returns false when the timeout expired.
+func (r *GracefulShutdown) Wait(timeout time.Duration) (ended bool) {
+	r.mutex.Lock()
+	r.stopping = true
+	r.mutex.Unlock()
+	done := make(chan struct{})
+	go func() {
+		r.inProgress.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		ended = true
+	case <-time.After(timeout):
+	}
+
+	return
+}
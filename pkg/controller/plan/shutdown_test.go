@@ -0,0 +1,88 @@
+package plan
+
+import (
+	"context"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+	"time"
+)
+
+func TestShutdownPersistsStatus(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	err := api.SchemeBuilder.AddToScheme(scheme.Scheme)
+	g.Expect(err).To(gomega.BeNil())
+	saved := Shutdown
+	Shutdown = &GracefulShutdown{}
+	defer func() {
+		Shutdown = saved
+	}()
+
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "plan",
+		},
+	}
+	vm := &plan.VMStatus{
+		VM: plan.VM{
+			Ref: ref.Ref{ID: "vm-1"},
+		},
+		Phase: DiskTransfer,
+	}
+	p.Status.Migration.VMs = []*plan.VMStatus{vm}
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, p.DeepCopy())
+	runner := Migration{
+		Context: &plancontext.Context{
+			Client: c,
+			Plan:   p,
+			Log:    log,
+		},
+	}
+
+	// Reconcile in progress.
+	g.Expect(Shutdown.Begin()).To(gomega.BeTrue())
+	ended := make(chan bool)
+	go func() {
+		ended <- Shutdown.Wait(time.Minute)
+	}()
+	g.Eventually(Shutdown.Stopping).Should(gomega.BeTrue())
+	g.Expect(Shutdown.Begin()).To(gomega.BeFalse())
+
+	// Stopping: the VMs are not stepped.
+	err = runner.stepAll(p.Status.Migration.VMs)
+	g.Expect(err).To(gomega.BeNil())
+
+	// Progress of the (in-progress) step is persisted
+	// before the reconcile ends.
+	vm.Phase = ImageConversion
+	err = c.Status().Update(context.TODO(), p)
+	g.Expect(err).To(gomega.BeNil())
+	Shutdown.End()
+	g.Expect(<-ended).To(gomega.BeTrue())
+
+	persisted := &api.Plan{}
+	err = c.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: "test", Name: "plan"},
+		persisted)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(persisted.Status.Migration.VMs).To(gomega.HaveLen(1))
+	g.Expect(persisted.Status.Migration.VMs[0].Phase).To(gomega.Equal(ImageConversion))
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	shutdown := &GracefulShutdown{}
+	g.Expect(shutdown.Begin()).To(gomega.BeTrue())
+	g.Expect(shutdown.Wait(time.Millisecond * 10)).To(gomega.BeFalse())
+	shutdown.End()
+	g.Expect(shutdown.Wait(time.Millisecond * 10)).To(gomega.BeTrue())
+}
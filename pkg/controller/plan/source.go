@@ -0,0 +1,87 @@
+package plan
+
+import (
+	libcnd "github.com/konveyor/controller/pkg/condition"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"time"
+)
+
+//
+// Quiesce timeout.
+const (
+	QuiesceTimeout = time.Minute * 20
+)
+
+//
+// Quiesce the guest file systems.
+// The (provider) quiesce task is started and then polled
+// on each reconcile until completed. When the guest cannot
+// be quiesced (tools not running), the migration continues
+// and a warning is reported.
+// Returns completed=true when the step has been completed.
+func (r *Migration) quiesceGuest(vm *plan.VMStatus) (completed bool) {
+	step, found := vm.FindStep(QuiesceGuest)
+	if !found {
+		vm.AddError("Step not found: " + QuiesceGuest)
+		completed = true
+		return
+	}
+	if !step.MarkedStarted() {
+		step.MarkStarted()
+		task, quiesced, err := r.builder.Quiesce(vm.Ref)
+		if err != nil {
+			step.AddError(err.Error())
+			step.MarkCompleted()
+			completed = true
+			return
+		}
+		if !quiesced {
+			vm.SetCondition(
+				libcnd.Condition{
+					Type:     GuestNotQuiesced,
+					Status:   True,
+					Category: Warn,
+					Reason:   NotSupported,
+					Message:  "The guest tools are not running; the guest file systems have not been quiesced.",
+					Durable:  true,
+				})
+		}
+		r.Log.Info(
+			"Guest quiesce.",
+			"vm",
+			vm.String(),
+			"quiesced",
+			quiesced,
+			"task",
+			task)
+		if task == "" {
+			step.Progress.Completed = 1
+			step.MarkCompleted()
+			completed = true
+			return
+		}
+		if step.Annotations == nil {
+			step.Annotations = map[string]string{}
+		}
+		step.Annotations[plan.AnnQuiesceTask] = task
+		return
+	}
+	task := step.Annotations[plan.AnnQuiesceTask]
+	next, done, err := r.builder.QuiesceTask(vm.Ref, task)
+	switch {
+	case err != nil:
+		step.AddError(err.Error())
+	case done:
+		step.Progress.Completed = 1
+	case time.Since(step.Started.Time) > QuiesceTimeout:
+		step.AddError("The guest quiesce has timed out.")
+	default:
+		step.Annotations[plan.AnnQuiesceTask] = next
+		return
+	}
+	delete(step.Annotations, plan.AnnQuiesceTask)
+	step.MarkCompleted()
+	completed = true
+
+	return
+}
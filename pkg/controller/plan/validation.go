@@ -2,6 +2,7 @@ package plan
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	net "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
@@ -12,39 +13,84 @@ import (
 	refapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/validation"
+	core "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"path"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strings"
 )
 
 //
 // Types
 const (
+	SpecNotValid        = "Invalid"
 	NamespaceNotValid   = "NamespaceNotValid"
 	TransferNetNotValid = "TransferNetworkNotValid"
+	TransferNetMTU      = "TransferNetworkMTUNotValid"
+	TransferCANotValid  = "TransferCANotValid"
+	BandwidthNotValid   = "BandwidthLimitNotSupported"
+	DisksPerVMNotValid  = "DisksPerVMNotSupported"
+	DiskFormatNotValid  = "TargetDiskFormatNotValid"
+	DiskFormatIgnored   = "TargetDiskFormatNotSupported"
+	ResourcesNotValid   = "ImporterResourcesNotValid"
+	ResourcesIgnored    = "ImporterResourcesNotSupported"
 	NetRefNotValid      = "NetworkMapRefNotValid"
 	NetMapNotReady      = "NetworkMapNotReady"
 	DsMapNotReady       = "StorageMapNotReady"
 	DsRefNotValid       = "StorageRefNotValid"
+	SourceNotValid      = "AdditionalSourceNotValid"
+	SourceNotReady      = "AdditionalSourceNotReady"
+	VMProviderNotValid  = "VMSourceProviderNotValid"
 	VMRefNotValid       = "VMRefNotValid"
 	VMNotFound          = "VMNotFound"
 	VMAlreadyExists     = "VMAlreadyExists"
 	VMNetworksNotMapped = "VMNetworksNotMapped"
 	VMStorageNotMapped  = "VMStorageNotMapped"
+	VMNotMapped         = "VMResourcesNotMapped"
+	VMSharedDisks       = "VMSharedDisksNotSupported"
+	VMDiskNotValid      = "VMDiskOverrideNotValid"
+	VMExcludedDisk      = "VMExcludedDiskNotValid"
+	VMUUIDChanged       = "VMUUIDChanged"
+	VMDeviceNotValid    = "VMUnsupportedDevices"
+	VMAntiAffinity      = "VMAntiAffinityGroup"
+	ProviderNotReady    = "ProviderNotReady"
+	VMChangeTracking    = "VMChangeTrackingDisabled"
+	CutoverForced       = "CutoverForced"
+	PrecopyFailed       = "PrecopyFailureLimitReached"
+	CutoverReady        = "CutoverReady"
+	StorageCapacity     = "InsufficientStorageCapacity"
+	VMNotValidated      = "VMNotValidated"
+	SourceDeleted       = "SourceDeleted"
+	VMFirmwareUEFI      = "VMFirmwareUEFI"
+	VMSecureBoot        = "VMSecureBootNotSupported"
+	VMTemplate          = "VMIsTemplate"
+	GuestNotQuiesced    = "GuestNotQuiesced"
+	PowerOnFailed       = "PowerOnPolicyNotApplied"
+	StorageModeNotValid = "StorageModeNotSupported"
+	VMNamespaceNotValid = "VMTargetNamespaceNotValid"
 	HostNotReady        = "HostNotReady"
+	SourceInMaintenance = "SourceInMaintenanceMode"
 	DuplicateVM         = "DuplicateVM"
 	NameNotValid        = "TargetNameNotValid"
 	HookNotValid        = "HookNotValid"
 	HookNotReady        = "HookNotReady"
 	HookStepNotValid    = "HookStepNotValid"
+	HookFailed          = "HookFailed"
 	Executing           = "Executing"
 	Succeeded           = "Succeeded"
 	Failed              = "Failed"
 	Canceled            = "Canceled"
 	Deleted             = "Deleted"
 	Paused              = "Paused"
+	WindowNotValid      = "AllowedWindowNotValid"
+	PVCMetadataNotValid = "TargetPVCMetadataNotValid"
+	NodeSelNotValid     = "TargetNodeSelectorNotValid"
+	WaitingForWindow    = "WaitingForWindow"
 	Pending             = "Pending"
 	Running             = "Running"
 	Blocked             = "Blocked"
@@ -71,6 +117,11 @@ const (
 	Modified          = "Modified"
 	UserRequested     = "UserRequested"
 	InMaintenanceMode = "InMaintenanceMode"
+	NotSupported      = "NotSupported"
+	NotReady          = "NotReady"
+	OutsideWindow     = "OutsideWindow"
+	PartialSuccess    = "PartialSuccess"
+	Ignored           = "Ignored"
 )
 
 //
@@ -83,6 +134,11 @@ const (
 //
 // Validate the plan resource.
 func (r *Reconciler) validate(plan *api.Plan) error {
+	// Spec.
+	err := r.validateSpec(plan)
+	if err != nil {
+		return err
+	}
 	// Provider.
 	pv := validation.ProviderPair{Client: r}
 	conditions, err := pv.Validate(plan.Spec.Provider)
@@ -111,6 +167,30 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 	if err != nil {
 		return err
 	}
+	err = r.validateStorageModes(plan)
+	if err != nil {
+		return err
+	}
+	err = r.validateSources(plan)
+	if err != nil {
+		return err
+	}
+	err = r.validateBandwidthLimit(plan)
+	if err != nil {
+		return err
+	}
+	err = r.validateDisksPerVM(plan)
+	if err != nil {
+		return err
+	}
+	err = r.validateDiskFormat(plan)
+	if err != nil {
+		return err
+	}
+	r.validateImporterResources(plan)
+	r.validateWindows(plan)
+	r.validatePVCMetadata(plan)
+	r.validateNodeSelector(plan)
 	//
 	// VM list.
 	err = r.validateVM(plan)
@@ -123,6 +203,12 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 	if err != nil {
 		return err
 	}
+	//
+	// Transfer CA
+	err = r.validateTransferCA(plan)
+	if err != nil {
+		return err
+	}
 	// VM Hooks.
 	err = r.validateHooks(plan)
 	if err != nil {
@@ -132,6 +218,309 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 	return nil
 }
 
+//
+// Validate the plan spec.
+// The (centralized) spec errors are reported on
+// a single blocking condition.
+func (r *Reconciler) validateSpec(plan *api.Plan) (err error) {
+	errs, err := ValidateSpec(r, plan)
+	if err != nil {
+		return
+	}
+	if len(errs) == 0 {
+		return
+	}
+	invalid := libcnd.Condition{
+		Type:     SpecNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "The plan spec is not valid.",
+		Items:    []string{},
+	}
+	for _, e := range errs {
+		invalid.Items = append(invalid.Items, e.Error())
+	}
+	plan.Status.SetCondition(invalid)
+
+	return
+}
+
+//
+// Validate the disk transfer bandwidth limit.
+// The limit is ignored when not supported by the importer.
+func (r *Reconciler) validateBandwidthLimit(plan *api.Plan) (err error) {
+	provider := plan.Referenced.Provider.Source
+	if plan.Spec.BandwidthLimit == 0 || provider == nil {
+		return
+	}
+	pAdapter, err := adapter.New(provider)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(plan)
+	if err != nil {
+		return
+	}
+	if !validator.BandwidthLimit() {
+		plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     BandwidthNotValid,
+				Status:   True,
+				Reason:   NotSupported,
+				Category: Warn,
+				Message: fmt.Sprintf(
+					"Disk transfer bandwidth throttling is not supported for provider type: %s. The limit is ignored.",
+					provider.Type()),
+			})
+	}
+
+	return
+}
+
+//
+// Validate the (per VM) disk transfer parallelism.
+// The limit is ignored when not supported by the importer.
+func (r *Reconciler) validateDisksPerVM(plan *api.Plan) (err error) {
+	provider := plan.Referenced.Provider.Source
+	if plan.Spec.DisksPerVM == 0 || provider == nil {
+		return
+	}
+	pAdapter, err := adapter.New(provider)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(plan)
+	if err != nil {
+		return
+	}
+	if !validator.DisksPerVM() {
+		plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     DisksPerVMNotValid,
+				Status:   True,
+				Reason:   NotSupported,
+				Category: Warn,
+				Message: fmt.Sprintf(
+					"Limiting the disks transferred concurrently is not supported for provider type: %s. The limit is ignored.",
+					provider.Type()),
+			})
+	}
+
+	return
+}
+
+//
+// Validate the target disk formats.
+// The qcow2 format cannot be used with Block volumes. Formats not
+// supported by the importer are ignored (the importer default).
+func (r *Reconciler) validateDiskFormat(plan *api.Plan) (err error) {
+	provider := plan.Referenced.Provider.Source
+	if provider == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     DiskFormatNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "The qcow2 disk format cannot be used with Block volumes.",
+		Items:    []string{},
+	}
+	requested := map[string]bool{}
+	qcow2OnBlock := func(format string, mode core.PersistentVolumeMode) bool {
+		if mode == "" {
+			mode = plan.Spec.VolumeMode
+		}
+		return format == api.DiskFormatQcow2 && mode == core.PersistentVolumeBlock
+	}
+	format := plan.Spec.TargetDiskFormat
+	if format != "" {
+		requested[format] = true
+		if mp := plan.Referenced.Map.Storage; mp != nil {
+			for _, pair := range mp.Spec.Map {
+				if qcow2OnBlock(format, pair.Destination.VolumeMode) {
+					notValid.Items = append(notValid.Items, pair.Destination.StorageClass)
+				}
+			}
+		}
+	}
+	for i := range plan.Spec.VMs {
+		vm := &plan.Spec.VMs[i]
+		for j := range vm.Disks {
+			disk := &vm.Disks[j]
+			format := plan.Spec.DiskFormat(disk)
+			if format == "" {
+				continue
+			}
+			requested[format] = true
+			if qcow2OnBlock(format, disk.VolumeMode) {
+				notValid.Items = append(notValid.Items, disk.Disk)
+			}
+		}
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+	if len(requested) == 0 {
+		return
+	}
+	pAdapter, err := adapter.New(provider)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(plan)
+	if err != nil {
+		return
+	}
+	ignored := []string{}
+	for format := range requested {
+		if !validator.DiskFormat(format) {
+			ignored = append(ignored, format)
+		}
+	}
+	if len(ignored) > 0 {
+		sort.Strings(ignored)
+		plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     DiskFormatIgnored,
+				Status:   True,
+				Reason:   NotSupported,
+				Category: Warn,
+				Message: fmt.Sprintf(
+					"Disk format not supported by the importer for provider type: %s. The disks are converted to raw.",
+					provider.Type()),
+				Items: ignored,
+			})
+	}
+
+	return
+}
+
+//
+// Validate the importer resource requirements.
+// Limits must not be less than requests. The requirements
+// are ignored when not supported by the importer.
+func (r *Reconciler) validateImporterResources(plan *api.Plan) {
+	resources := plan.Spec.ImporterResources
+	if resources == nil {
+		return
+	}
+	notValid := []string{}
+	for name, request := range resources.Requests {
+		if limit, found := resources.Limits[name]; found && limit.Cmp(request) < 0 {
+			notValid = append(notValid, name.String())
+		}
+	}
+	if len(notValid) > 0 {
+		sort.Strings(notValid)
+		plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     ResourcesNotValid,
+				Status:   True,
+				Reason:   NotValid,
+				Category: Critical,
+				Message:  "Importer resource limits are less than the requests.",
+				Items:    notValid,
+			})
+	}
+	if len(resources.Requests)+len(resources.Limits) > 0 {
+		plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     ResourcesIgnored,
+				Status:   True,
+				Reason:   NotSupported,
+				Category: Warn,
+				Message:  "Importer resource requirements are not supported by the importer. The requirements are ignored.",
+			})
+	}
+}
+
+//
+// Validate the allowed (transfer) windows.
+func (r *Reconciler) validateWindows(plan *api.Plan) {
+	notValid := []string{}
+	for i := range plan.Spec.AllowedWindows {
+		err := plan.Spec.AllowedWindows[i].Validate()
+		if err != nil {
+			notValid = append(notValid, err.Error())
+		}
+	}
+	if len(notValid) > 0 {
+		plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     WindowNotValid,
+				Status:   True,
+				Reason:   NotValid,
+				Category: Critical,
+				Message:  "Allowed window is not valid.",
+				Items:    notValid,
+			})
+	}
+}
+
+//
+// Validate the target PVC labels and annotations.
+func (r *Reconciler) validatePVCMetadata(plan *api.Plan) {
+	notValid := []string{}
+	for k, v := range plan.Spec.TargetPVCLabels {
+		if len(k8svalidation.IsQualifiedName(k)) > 0 ||
+			len(k8svalidation.IsValidLabelValue(v)) > 0 {
+			notValid = append(notValid, "label: "+k)
+		}
+	}
+	for k := range plan.Spec.TargetPVCAnnotations {
+		if len(k8svalidation.IsQualifiedName(strings.ToLower(k))) > 0 {
+			notValid = append(notValid, "annotation: "+k)
+		}
+	}
+	if len(notValid) > 0 {
+		sort.Strings(notValid)
+		plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     PVCMetadataNotValid,
+				Status:   True,
+				Reason:   NotValid,
+				Category: Critical,
+				Message:  "Target PVC labels or annotations not valid.",
+				Items:    notValid,
+			})
+	}
+}
+
+//
+// Validate the target node selectors.
+// The plan and VM selector keys must be qualified
+// names and the values valid label values.
+func (r *Reconciler) validateNodeSelector(plan *api.Plan) {
+	notValid := []string{}
+	validate := func(owner string, selector map[string]string) {
+		for k, v := range selector {
+			if len(k8svalidation.IsQualifiedName(k)) > 0 ||
+				len(k8svalidation.IsValidLabelValue(v)) > 0 {
+				notValid = append(notValid, owner+": "+k)
+			}
+		}
+	}
+	validate("plan", plan.Spec.TargetNodeSelector)
+	for i := range plan.Spec.VMs {
+		vm := &plan.Spec.VMs[i]
+		validate(vm.Ref.String(), vm.TargetNodeSelector)
+	}
+	if len(notValid) > 0 {
+		sort.Strings(notValid)
+		plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     NodeSelNotValid,
+				Status:   True,
+				Reason:   NotValid,
+				Category: Critical,
+				Message:  "Target node selector not valid.",
+				Items:    notValid,
+			})
+	}
+}
+
 //
 // Validate the target namespace.
 func (r *Reconciler) validateTargetNamespace(plan *api.Plan) (err error) {
@@ -243,6 +632,272 @@ func (r *Reconciler) validateStorageMap(plan *api.Plan) (err error) {
 	return
 }
 
+//
+// Validate the additional source providers.
+// Each provider and its maps must exist and be ready. The
+// providers (and maps) are referenced for the VM validation
+// and the migration. The VMs must reference either the plan
+// source provider or an additional source provider.
+func (r *Reconciler) validateSources(plan *api.Plan) (err error) {
+	notValid := libcnd.Condition{
+		Type:     SourceNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "Additional source providers (or maps) not valid.",
+		Items:    []string{},
+	}
+	notReady := libcnd.Condition{
+		Type:     SourceNotReady,
+		Status:   True,
+		Reason:   NotReady,
+		Category: Critical,
+		Message:  "Additional source providers (or maps) not ready.",
+		Items:    []string{},
+	}
+	vmNotValid := libcnd.Condition{
+		Type:     VMProviderNotValid,
+		Status:   True,
+		Reason:   NotFound,
+		Category: Critical,
+		Message:  "VM source provider is neither the plan source provider nor an additional source provider.",
+		Items:    []string{},
+	}
+	get := func(ref core.ObjectReference, object runtime.Object) (found bool, err error) {
+		if !libref.RefSet(&ref) {
+			return
+		}
+		err = r.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: ref.Namespace,
+				Name:      ref.Name,
+			},
+			object)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+			} else {
+				err = liberr.Wrap(err)
+			}
+			return
+		}
+		found = true
+		return
+	}
+	plan.Referenced.Sources = []*api.ReferencedSource{}
+	setOf := map[string]bool{
+		path.Join(
+			plan.Spec.Provider.Source.Namespace,
+			plan.Spec.Provider.Source.Name): true,
+	}
+	for _, source := range plan.Spec.Sources {
+		name := path.Join(source.Provider.Namespace, source.Provider.Name)
+		if setOf[name] {
+			notValid.Items = append(notValid.Items, name+": duplicate provider.")
+			continue
+		}
+		setOf[name] = true
+		referenced := &api.ReferencedSource{Provider: &api.Provider{}}
+		found, gErr := get(source.Provider, referenced.Provider)
+		if gErr != nil {
+			err = gErr
+			return
+		}
+		if !found {
+			notValid.Items = append(notValid.Items, name+": provider not found.")
+			continue
+		}
+		switch referenced.Provider.Type() {
+		case api.VSphere, api.OVirt:
+		default:
+			notValid.Items = append(notValid.Items, name+": provider type not supported.")
+			continue
+		}
+		referenced.Map.Network = &api.NetworkMap{}
+		found, gErr = get(source.Map.Network, referenced.Map.Network)
+		if gErr != nil {
+			err = gErr
+			return
+		}
+		if !found {
+			notValid.Items = append(notValid.Items, name+": network map not found.")
+			continue
+		}
+		referenced.Map.Storage = &api.StorageMap{}
+		found, gErr = get(source.Map.Storage, referenced.Map.Storage)
+		if gErr != nil {
+			err = gErr
+			return
+		}
+		if !found {
+			notValid.Items = append(notValid.Items, name+": storage map not found.")
+			continue
+		}
+		if !referenced.Provider.Status.HasCondition(libcnd.Ready) ||
+			!referenced.Map.Network.Status.HasCondition(libcnd.Ready) ||
+			!referenced.Map.Storage.Status.HasCondition(libcnd.Ready) {
+			notReady.Items = append(notReady.Items, name)
+			continue
+		}
+		plan.Referenced.Sources = append(plan.Referenced.Sources, referenced)
+	}
+	for i := range plan.Spec.VMs {
+		vm := &plan.Spec.VMs[i]
+		ref := plan.Spec.VMProvider(vm)
+		if !setOf[path.Join(ref.Namespace, ref.Name)] {
+			vmNotValid.Items = append(vmNotValid.Items, vm.String())
+		}
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+	if len(notReady.Items) > 0 {
+		plan.Status.SetCondition(notReady)
+	}
+	if len(vmNotValid.Items) > 0 {
+		plan.Status.SetCondition(vmNotValid)
+	}
+
+	return
+}
+
+//
+// The plan as seen by the VM source provider.
+// For VMs of an additional source, a (shallow) copy of the
+// plan referencing the source provider and maps.
+// Returns nil when the source has not been referenced.
+func (r *Reconciler) sourcePlan(plan *api.Plan, ref core.ObjectReference) (view *api.Plan) {
+	source := plan.Spec.Provider.Source
+	if ref.Namespace == source.Namespace && ref.Name == source.Name {
+		view = plan
+		return
+	}
+	found, referenced := plan.Referenced.FindSource(ref)
+	if !found {
+		return
+	}
+	copied := *plan
+	copied.Referenced.Provider.Source = referenced.Provider
+	copied.Referenced.Map.Network = referenced.Map.Network
+	copied.Referenced.Map.Storage = referenced.Map.Storage
+	view = &copied
+
+	return
+}
+
+//
+// Validate the storage volume and access modes.
+// Warn when the modes (or the plan defaults) are not
+// supported by the provisioner of the storage class.
+func (r *Reconciler) validateStorageModes(plan *api.Plan) (err error) {
+	mp := plan.Referenced.Map.Storage
+	source := plan.Referenced.Provider.Source
+	destination := plan.Referenced.Provider.Destination
+	if mp == nil || source == nil || destination == nil {
+		return
+	}
+	notSupported := libcnd.Condition{
+		Type:     StorageModeNotValid,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Warn,
+		Message:  "Storage volume (or access) mode not supported by the storage class provisioner.",
+		Items:    []string{},
+	}
+	list := &api.ProvisionerList{}
+	err = r.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			Namespace: source.Namespace,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	provisioners := map[string]*api.Provisioner{}
+	for i := range list.Items {
+		p := &list.Items[i]
+		provisioners[p.Spec.Name] = p
+	}
+	inventory, err := web.NewClient(destination)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	mapped := []api.DestinationStorage{}
+	for _, pair := range mp.Spec.Map {
+		mapped = append(mapped, pair.Destination)
+	}
+	for _, vm := range plan.Spec.VMs {
+		for _, disk := range vm.Disks {
+			mapped = append(
+				mapped,
+				api.DestinationStorage{
+					StorageClass: disk.StorageClass,
+					VolumeMode:   disk.VolumeMode,
+					AccessMode:   disk.AccessMode,
+				})
+		}
+	}
+	reported := map[string]bool{}
+	for _, dm := range mapped {
+		if dm.VolumeMode == "" {
+			dm.VolumeMode = plan.Spec.VolumeMode
+		}
+		if dm.AccessMode == "" {
+			dm.AccessMode = plan.Spec.AccessMode
+		}
+		if dm.VolumeMode == "" && dm.AccessMode == "" {
+			continue
+		}
+		sc := &ocp.StorageClass{}
+		fErr := inventory.Find(sc, refapi.Ref{Name: dm.StorageClass})
+		if fErr != nil {
+			continue
+		}
+		provisioner, found := provisioners[sc.Object.Provisioner]
+		if !found {
+			continue
+		}
+		if !r.modesSupported(provisioner, &dm) && !reported[dm.StorageClass] {
+			reported[dm.StorageClass] = true
+			notSupported.Items = append(notSupported.Items, dm.StorageClass)
+		}
+	}
+	if len(notSupported.Items) > 0 {
+		plan.Status.SetCondition(notSupported)
+	}
+
+	return
+}
+
+//
+// The volume and access modes are supported by the provisioner.
+// Unspecified modes match any mode. Provisioners that do
+// not list volume modes support any mode.
+func (r *Reconciler) modesSupported(provisioner *api.Provisioner, dm *api.DestinationStorage) bool {
+	if len(provisioner.Spec.VolumeModes) == 0 {
+		return true
+	}
+	for _, volumeMode := range provisioner.Spec.VolumeModes {
+		if dm.VolumeMode != "" && volumeMode.Name != dm.VolumeMode {
+			continue
+		}
+		if dm.AccessMode == "" {
+			return true
+		}
+		for _, accessMode := range volumeMode.AccessModes {
+			if accessMode.Name == dm.AccessMode {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 //
 // Validate listed VMs.
 func (r *Reconciler) validateVM(plan *api.Plan) error {
@@ -281,6 +936,14 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "Target VM name not valid.",
 		Items:    []string{},
 	}
+	namespaceNotValid := libcnd.Condition{
+		Type:     VMNamespaceNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "Target VM namespace not valid.",
+		Items:    []string{},
+	}
 	alreadyExists := libcnd.Condition{
 		Type:     VMAlreadyExists,
 		Status:   True,
@@ -305,6 +968,46 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "VM has unmapped storage.",
 		Items:    []string{},
 	}
+	sharedDisks := libcnd.Condition{
+		Type:     VMSharedDisks,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Critical,
+		Message:  "VM has shared (or RDM) disks which cannot be migrated. Set `skipSharedDisks` to migrate without them or `migrateSharedDisks` to migrate shared (not RDM) disks once.",
+		Items:    []string{},
+	}
+	diskNotValid := libcnd.Condition{
+		Type:     VMDiskNotValid,
+		Status:   True,
+		Reason:   NotFound,
+		Category: Warn,
+		Message:  "VM disk storage overrides reference disks not found on the source VM.",
+		Items:    []string{},
+	}
+	excludedNotValid := libcnd.Condition{
+		Type:     VMExcludedDisk,
+		Status:   True,
+		Reason:   NotFound,
+		Category: Warn,
+		Message:  "VM excluded disks reference disks not found on the source VM.",
+		Items:    []string{},
+	}
+	deviceNotValid := libcnd.Condition{
+		Type:     VMDeviceNotValid,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Warn,
+		Message:  "VM devices (GPU, passthrough, USB, serial) cannot be migrated.",
+		Items:    []string{},
+	}
+	changeTracking := libcnd.Condition{
+		Type:     VMChangeTracking,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Advisory,
+		Message:  "Changed block tracking (CBT) is disabled for previously migrated VMs.",
+		Items:    []string{},
+	}
 	maintenanceMode := libcnd.Condition{
 		Type:     HostNotReady,
 		Status:   True,
@@ -313,8 +1016,17 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "VM host is in maintenance mode.",
 		Items:    []string{},
 	}
+	antiAffinity := libcnd.Condition{
+		Type:     VMAntiAffinity,
+		Status:   True,
+		Reason:   NotUnique,
+		Category: Warn,
+		Message:  "Multiple VMs in the same anti-affinity group may be migrated at the same time.",
+		Items:    []string{},
+	}
 
 	setOf := map[string]bool{}
+	groups := map[string][]string{}
 	//
 	// Referenced VMs.
 	for i := range plan.Spec.VMs {
@@ -330,10 +1042,14 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 			continue
 		}
 		// Source.
-		provider := plan.Referenced.Provider.Source
-		if provider == nil {
+		if plan.Referenced.Provider.Source == nil {
 			return nil
 		}
+		view := r.sourcePlan(plan, plan.Spec.VMProvider(&plan.Spec.VMs[i]))
+		if view == nil {
+			continue
+		}
+		provider := view.Referenced.Provider.Source
 		inventory, pErr := web.NewClient(provider)
 		if pErr != nil {
 			return liberr.Wrap(pErr)
@@ -350,23 +1066,29 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 			}
 			return liberr.Wrap(pErr)
 		}
-		if len(k8svalidation.IsDNS1123Label(ref.Name)) > 0 {
+		targetName := plan.Spec.VMs[i].TargetVMName()
+		if len(k8svalidation.IsDNS1123Label(targetName)) > 0 {
 			nameNotValid.Items = append(nameNotValid.Items, ref.String())
 		}
-		if _, found := setOf[ref.ID]; found {
+		namespace := plan.Spec.VMNamespace(&plan.Spec.VMs[i])
+		if len(k8svalidation.IsDNS1123Label(namespace)) > 0 {
+			namespaceNotValid.Items = append(namespaceNotValid.Items, ref.String())
+		}
+		key := path.Join(provider.Namespace, provider.Name, ref.ID)
+		if _, found := setOf[key]; found {
 			notUnique.Items = append(notUnique.Items, ref.String())
 		} else {
-			setOf[ref.ID] = true
+			setOf[key] = true
 		}
 		pAdapter, err := adapter.New(provider)
 		if err != nil {
 			return err
 		}
-		validator, err := pAdapter.Validator(plan)
+		validator, err := pAdapter.Validator(view)
 		if err != nil {
 			return err
 		}
-		if plan.Referenced.Map.Network != nil {
+		if view.Referenced.Map.Network != nil {
 			ok, err := validator.NetworksMapped(*ref)
 			if err != nil {
 				return err
@@ -375,7 +1097,7 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 				unmappedNetwork.Items = append(unmappedNetwork.Items, ref.String())
 			}
 		}
-		if plan.Referenced.Map.Storage != nil {
+		if view.Referenced.Map.Storage != nil {
 			ok, err := validator.StorageMapped(*ref)
 			if err != nil {
 				return err
@@ -391,6 +1113,55 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		if !ok {
 			maintenanceMode.Items = append(maintenanceMode.Items, ref.String())
 		}
+		ok, err = validator.SharedDisks(*ref)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			sharedDisks.Items = append(sharedDisks.Items, ref.String())
+		}
+		ok, err = validator.DiskOverrides(*ref)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			diskNotValid.Items = append(diskNotValid.Items, ref.String())
+		}
+		ok, err = validator.ExcludedDisks(*ref)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			excludedNotValid.Items = append(excludedNotValid.Items, ref.String())
+		}
+		if _, found := plan.Status.Migration.FindVM(*ref); found {
+			ok, err = validator.ChangeTracking(*ref)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				changeTracking.Items = append(changeTracking.Items, ref.String())
+			}
+		}
+		names, err := validator.AntiAffinity(*ref)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			groups[name] = append(groups[name], ref.String())
+		}
+		kinds, err := validator.UnsupportedDevices(*ref)
+		if err != nil {
+			return err
+		}
+		if len(kinds) > 0 {
+			deviceNotValid.Items = append(
+				deviceNotValid.Items,
+				fmt.Sprintf(
+					"%s devices: %s",
+					ref.String(),
+					strings.Join(kinds, ",")))
+		}
 		// Destination.
 		provider = plan.Referenced.Provider.Destination
 		if provider == nil {
@@ -401,8 +1172,8 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 			return liberr.Wrap(pErr)
 		}
 		id := path.Join(
-			plan.Spec.TargetNamespace,
-			ref.Name)
+			namespace,
+			targetName)
 		_, pErr = inventory.VM(&refapi.Ref{Name: id})
 		if pErr == nil {
 			if vm, found := plan.Status.Migration.FindVM(*ref); found {
@@ -431,6 +1202,9 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 	if len(nameNotValid.Items) > 0 {
 		plan.Status.SetCondition(nameNotValid)
 	}
+	if len(namespaceNotValid.Items) > 0 {
+		plan.Status.SetCondition(namespaceNotValid)
+	}
 	if len(ambiguous.Items) > 0 {
 		plan.Status.SetCondition(ambiguous)
 	}
@@ -440,6 +1214,35 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 	if len(unmappedStorage.Items) > 0 {
 		plan.Status.SetCondition(unmappedStorage)
 	}
+	if len(sharedDisks.Items) > 0 {
+		plan.Status.SetCondition(sharedDisks)
+	}
+	if len(diskNotValid.Items) > 0 {
+		plan.Status.SetCondition(diskNotValid)
+	}
+	if len(excludedNotValid.Items) > 0 {
+		plan.Status.SetCondition(excludedNotValid)
+	}
+	if len(deviceNotValid.Items) > 0 {
+		plan.Status.SetCondition(deviceNotValid)
+	}
+	if len(changeTracking.Items) > 0 {
+		plan.Status.SetCondition(changeTracking)
+	}
+	for name, vms := range groups {
+		if len(vms) > 1 {
+			antiAffinity.Items = append(
+				antiAffinity.Items,
+				fmt.Sprintf(
+					"%s VMs: %s",
+					name,
+					strings.Join(vms, ",")))
+		}
+	}
+	if len(antiAffinity.Items) > 0 {
+		sort.Strings(antiAffinity.Items)
+		plan.Status.SetCondition(antiAffinity)
+	}
 
 	return nil
 }
@@ -470,6 +1273,136 @@ func (r *Reconciler) validateTransferNetwork(plan *api.Plan) (err error) {
 	}
 	if err != nil {
 		err = liberr.Wrap(err)
+		return
+	}
+	err = r.validateTransferMTU(plan, netAttachDef)
+
+	return
+}
+
+//
+// Validate the transfer CA bundle secret.
+// The secret must contain the `ca.crt` key.
+func (r *Reconciler) validateTransferCA(plan *api.Plan) (err error) {
+	if plan.Spec.TransferCA == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     TransferCANotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotFound,
+		Message:  "Transfer CA secret is not valid.",
+	}
+	key := client.ObjectKey{
+		Namespace: plan.Spec.TransferCA.Namespace,
+		Name:      plan.Spec.TransferCA.Name,
+	}
+	secret := &core.Secret{}
+	err = r.Get(context.TODO(), key, secret)
+	if k8serr.IsNotFound(err) {
+		err = nil
+		plan.Status.SetCondition(notValid)
+		return
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if _, found := secret.Data[api.TransferCAKey]; !found {
+		plan.Status.SetCondition(notValid)
+	}
+
+	return
+}
+
+//
+// Validate the transfer network MTU.
+// Source networks used for disk transfer with an MTU larger
+// than the transfer network MTU are fragmented. Not validated
+// when the MTU is not specified by the network configuration.
+func (r *Reconciler) validateTransferMTU(plan *api.Plan, netAttachDef *net.NetworkAttachmentDefinition) (err error) {
+	config := struct {
+		MTU int32 `json:"mtu"`
+	}{}
+	if json.Unmarshal([]byte(netAttachDef.Spec.Config), &config) != nil || config.MTU == 0 {
+		return
+	}
+	provider := plan.Referenced.Provider.Source
+	if provider == nil {
+		return
+	}
+	err = r.referenceHosts(plan)
+	if err != nil {
+		return
+	}
+	pAdapter, err := adapter.New(provider)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(plan)
+	if err != nil {
+		return
+	}
+	mismatch := libcnd.Condition{
+		Type:     TransferNetMTU,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Warn,
+		Items:    []string{},
+	}
+	suggested := config.MTU
+	for i := range plan.Spec.VMs {
+		ref := &plan.Spec.VMs[i].Ref
+		ok, sourceMTU, vErr := validator.TransferMTU(*ref, config.MTU)
+		if vErr != nil {
+			err = vErr
+			return
+		}
+		if !ok {
+			mismatch.Items = append(mismatch.Items, ref.String())
+			if sourceMTU > suggested {
+				suggested = sourceMTU
+			}
+		}
+	}
+	if len(mismatch.Items) > 0 {
+		mismatch.Message = fmt.Sprintf(
+			"The source network used for disk transfer has an MTU larger than the transfer network MTU (%d). Set the transfer network MTU to %d.",
+			config.MTU,
+			suggested)
+		plan.Status.SetCondition(mismatch)
+	}
+
+	return
+}
+
+//
+// Populate the referenced (ready) hosts
+// of the source provider.
+func (r *Reconciler) referenceHosts(plan *api.Plan) (err error) {
+	provider := plan.Referenced.Provider.Source
+	list := &api.HostList{}
+	err = r.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			Namespace: provider.Namespace,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	plan.Referenced.Hosts = []*api.Host{}
+	for i := range list.Items {
+		host := &list.Items[i]
+		if host.Spec.Provider.Name != provider.Name {
+			continue
+		}
+		if !host.Status.HasCondition(libcnd.Ready) {
+			continue
+		}
+		plan.Referenced.Hosts = append(plan.Referenced.Hosts, host)
 	}
 
 	return
@@ -510,9 +1443,9 @@ func (r *Reconciler) validateHooks(plan *api.Plan) (err error) {
 		Items:    []string{},
 	}
 	for _, vm := range plan.Spec.VMs {
-		for _, ref := range vm.Hooks {
+		for _, ref := range plan.Spec.VMHooks(&vm) {
 			// Step not valid.
-			if _, found := map[string]int{PreHook: 1, PostHook: 1}[ref.Step]; !found {
+			if _, found := map[string]int{PreHook: 1, PreImportHook: 1, PostImportHook: 1, PostHook: 1}[ref.Step]; !found {
 				description := fmt.Sprintf(
 					"VM: %s step: %s",
 					vm.String(),
@@ -0,0 +1,165 @@
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libitr "github.com/konveyor/controller/pkg/itinerary"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//
+// Webhook delivery.
+const (
+	// Number of delivery attempts.
+	WebhookRetries = 3
+	// Delay between attempts.
+	WebhookRetryDelay = time.Second * 10
+	// HTTP client timeout.
+	WebhookTimeout = time.Second * 30
+)
+
+//
+// Plan completion (webhook) payload.
+type Completion struct {
+	// Plan namespace.
+	Namespace string `json:"namespace"`
+	// Plan name.
+	Name string `json:"name"`
+	// Outcome: Succeeded|Failed|Canceled.
+	Outcome string `json:"outcome"`
+	// Started timestamp.
+	Started *meta.Time `json:"started,omitempty"`
+	// Completed timestamp.
+	Completed *meta.Time `json:"completed,omitempty"`
+	// Duration (seconds).
+	Duration float64 `json:"duration"`
+	// VM progress.
+	Progress libitr.Progress `json:"progress"`
+	// VM results.
+	VMs []CompletedVM `json:"vms"`
+}
+
+//
+// VM result.
+type CompletedVM struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+}
+
+//
+// Build the payload.
+func (r *Completion) With(plan *api.Plan, outcome string) {
+	migration := &plan.Status.Migration
+	r.Namespace = plan.Namespace
+	r.Name = plan.Name
+	r.Outcome = outcome
+	r.Started = migration.Started
+	r.Completed = migration.Completed
+	if r.Started != nil && r.Completed != nil {
+		r.Duration = r.Completed.Sub(r.Started.Time).Seconds()
+	}
+	r.VMs = []CompletedVM{}
+	for _, vm := range migration.VMs {
+		result := CompletedVM{
+			ID:   vm.ID,
+			Name: vm.Name,
+		}
+		for _, cndType := range []string{Succeeded, Failed, Canceled} {
+			if vm.HasCondition(cndType) {
+				result.Outcome = cndType
+				break
+			}
+		}
+		if vm.Error != nil {
+			result.Error = strings.Join(vm.Error.Reasons, "; ")
+		}
+		if result.Outcome == Succeeded {
+			r.Progress.Completed++
+		}
+		r.VMs = append(r.VMs, result)
+	}
+	r.Progress.Total = int64(len(migration.VMs))
+}
+
+//
+// Notify the plan completion webhook (when specified).
+// Delivered asynchronously and retried a bounded number
+// of times. Failures are logged and do not affect the
+// migration.
+func (r *Migration) notify(outcome string) {
+	webhook := r.Plan.Spec.CompletionWebhook
+	if webhook == nil || webhook.URL == "" {
+		return
+	}
+	payload := Completion{}
+	payload.With(r.Plan, outcome)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		r.Log.Error(err, "Webhook payload failed.")
+		return
+	}
+	webhook = webhook.DeepCopy()
+	logger := r.Log
+	go func() {
+		for attempt := 1; attempt <= WebhookRetries; attempt++ {
+			err := post(webhook, body)
+			if err == nil {
+				logger.Info(
+					"Webhook delivered.",
+					"url",
+					webhook.URL)
+				return
+			}
+			logger.Error(
+				err,
+				"Webhook delivery failed.",
+				"url",
+				webhook.URL,
+				"attempt",
+				attempt)
+			if attempt < WebhookRetries {
+				time.Sleep(WebhookRetryDelay)
+			}
+		}
+	}()
+}
+
+//
+// POST the body to the webhook.
+func post(webhook *api.Webhook, body []byte) (err error) {
+	request, err := http.NewRequest(
+		http.MethodPost,
+		webhook.URL,
+		bytes.NewReader(body))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for k, v := range webhook.Headers {
+		request.Header.Set(k, v)
+	}
+	client := http.Client{Timeout: WebhookTimeout}
+	response, err := client.Do(request)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		err = liberr.New(
+			fmt.Sprintf(
+				"Webhook returned: %s",
+				response.Status))
+	}
+
+	return
+}
@@ -16,6 +16,7 @@ import (
 	libpath "path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -59,6 +60,18 @@ type Collector struct {
 	phase string
 	// List of watches.
 	watches []*libmodel.Watch
+	// Refresh requests.
+	refreshes struct {
+		sync.Mutex
+		// Wake the collector.
+		wake chan struct{}
+		// Requested revision.
+		requested int64
+		// Revision being refreshed.
+		taken int64
+		// Refreshed revision.
+		refreshed int64
+	}
 }
 
 //
@@ -78,6 +91,7 @@ func New(db libmodel.DB, provider *api.Provider, secret *core.Secret) (r *Collec
 		db:       db,
 		log:      log,
 	}
+	r.refreshes.wake = make(chan struct{}, 1)
 
 	return
 }
@@ -182,10 +196,12 @@ func (r *Collector) run(ctx *Context) (err error) {
 			r.parity = true
 		}
 	case Refresh:
+		revision := r.takeRefresh()
 		err = r.refresh(ctx)
 		if err == nil {
 			r.parity = true
-			time.Sleep(RefreshInterval)
+			r.markRefreshed(revision)
+			r.wait(ctx)
 		} else {
 			r.parity = false
 		}
@@ -204,6 +220,63 @@ func (r *Collector) run(ctx *Context) (err error) {
 	return
 }
 
+//
+// Request an immediate refresh.
+// Requests made before the refresh has started are
+// coalesced.
+// Returns the revision of the requested refresh.
+func (r *Collector) Refresh() (revision int64) {
+	r.refreshes.Lock()
+	defer r.refreshes.Unlock()
+	if r.refreshes.requested == r.refreshes.taken {
+		r.refreshes.requested++
+		select {
+		case r.refreshes.wake <- struct{}{}:
+		default:
+		}
+	}
+	revision = r.refreshes.requested
+	return
+}
+
+//
+// The revision of the last completed refresh.
+func (r *Collector) Refreshed() (revision int64) {
+	r.refreshes.Lock()
+	defer r.refreshes.Unlock()
+	revision = r.refreshes.refreshed
+	return
+}
+
+//
+// Take the requested refresh revision.
+func (r *Collector) takeRefresh() (revision int64) {
+	r.refreshes.Lock()
+	defer r.refreshes.Unlock()
+	r.refreshes.taken = r.refreshes.requested
+	revision = r.refreshes.taken
+	return
+}
+
+//
+// Mark the refresh revision completed.
+func (r *Collector) markRefreshed(revision int64) {
+	r.refreshes.Lock()
+	defer r.refreshes.Unlock()
+	r.refreshes.refreshed = revision
+}
+
+//
+// Wait for the refresh interval or
+// until a refresh is requested.
+func (r *Collector) wait(ctx *Context) {
+	select {
+	case <-r.refreshes.wake:
+	case <-ctx.ctx.Done():
+	case <-time.After(RefreshInterval):
+	}
+}
+
 //
 // Shutdown the collector.
 func (r *Collector) Shutdown() {
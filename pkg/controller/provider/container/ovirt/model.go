@@ -567,7 +567,7 @@ func (r *ClusterAdapter) Event() []int {
 // List the collection.
 func (r *ClusterAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
 	clusterList := ClusterList{}
-	err = ctx.client.list("clusters", &clusterList)
+	err = ctx.client.list("clusters", &clusterList, r.follow())
 	if err != nil {
 		return
 	}
@@ -603,7 +603,7 @@ func (r *ClusterAdapter) Apply(ctx *Context, event *Event) (updater Updater, err
 	switch event.code() {
 	case USER_ADD_CLUSTER:
 		object := &Cluster{}
-		err = ctx.client.get(event.Cluster.Ref, object)
+		err = ctx.client.get(event.Cluster.Ref, object, r.follow())
 		if err != nil {
 			break
 		}
@@ -617,7 +617,7 @@ func (r *ClusterAdapter) Apply(ctx *Context, event *Event) (updater Updater, err
 		}
 	case USER_UPDATE_CLUSTER:
 		object := &Cluster{}
-		err = ctx.client.get(event.Cluster.Ref, object)
+		err = ctx.client.get(event.Cluster.Ref, object, r.follow())
 		if err != nil {
 			break
 		}
@@ -648,6 +648,12 @@ func (r *ClusterAdapter) Apply(ctx *Context, event *Event) (updater Updater, err
 	return
 }
 
+func (r *ClusterAdapter) follow() libweb.Param {
+	return r.BaseAdapter.follow(
+		"affinity_groups",
+	)
+}
+
 //
 // Host adapter.
 type HostAdapter struct {
@@ -84,6 +84,17 @@ type Cluster struct {
 	KSM           struct {
 		Enabled string `json:"enabled"`
 	} `json:"ksm"`
+	AffinityGroups struct {
+		List []struct {
+			ID        string `json:"id"`
+			Name      string `json:"name"`
+			Enforcing string `json:"enforcing"`
+			Positive  string `json:"positive"`
+			VMs       struct {
+				List []Ref `json:"vm"`
+			} `json:"vms"`
+		} `json:"affinity_group"`
+	} `json:"affinity_groups"`
 }
 
 //
@@ -94,6 +105,26 @@ func (r *Cluster) ApplyTo(m *model.Cluster) {
 	m.DataCenter = r.DataCenter.ID
 	m.HaReservation = r.bool(r.HaReservation)
 	m.KsmEnabled = r.bool(r.KSM.Enabled)
+	r.addAffinityGroups(m)
+}
+
+func (r *Cluster) addAffinityGroups(m *model.Cluster) {
+	m.AffinityGroups = []model.AffinityGroup{}
+	for _, g := range r.AffinityGroups.List {
+		vms := []string{}
+		for _, vm := range g.VMs.List {
+			vms = append(vms, vm.ID)
+		}
+		m.AffinityGroups = append(
+			m.AffinityGroups,
+			model.AffinityGroup{
+				ID:        g.ID,
+				Name:      g.Name,
+				Enforcing: r.bool(g.Enforcing),
+				Positive:  r.bool(g.Positive),
+				VMs:       vms,
+			})
+	}
 }
 
 //
@@ -194,8 +225,9 @@ type HostList struct {
 // VM.
 type VM struct {
 	Base
-	Cluster Ref `json:"cluster"`
-	Host    Ref `json:"host"`
+	Cluster Ref    `json:"cluster"`
+	Host    Ref    `json:"host"`
+	FQDN    string `json:"fqdn"`
 	Guest   struct {
 		Distribution string `json:"distribution"`
 		Version      struct {
@@ -278,7 +310,10 @@ type VM struct {
 			Interface string `json:"interface"`
 			Plugged   string `json:"plugged"`
 			Profile   Ref    `json:"vnic_profile"`
-			Devices   struct {
+			MAC       struct {
+				Address string `json:"address"`
+			} `json:"mac"`
+			Devices struct {
 				List []struct {
 					IPS struct {
 						IP []struct {
@@ -329,6 +364,7 @@ func (r *VM) ApplyTo(m *model.VM) {
 	m.Description = r.Description
 	m.Cluster = r.Cluster.ID
 	m.Host = r.Host.ID
+	m.HostName = r.FQDN
 	m.GuestName = r.Guest.Distribution + " " + r.Guest.Version.Full
 	m.CpuSockets = r.int16(r.CPU.Topology.Sockets)
 	m.CpuCores = r.int16(r.CPU.Topology.Cores)
@@ -391,6 +427,7 @@ func (r *VM) addNICs(m *model.VM) {
 				Profile:   n.Profile.ID,
 				Interface: n.Interface,
 				Plugged:   r.bool(n.Plugged),
+				MAC:       n.MAC.Address,
 				IpAddress: ips,
 			})
 	}
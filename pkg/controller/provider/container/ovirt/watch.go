@@ -43,9 +43,9 @@ const (
 //
 // Endpoints.
 const (
-	BaseEndpoint       = "/v1/data/io/konveyor/forklift/ovirt/"
-	VersionEndpoint    = BaseEndpoint + "rules_version"
-	ValidationEndpoint = BaseEndpoint + "validate"
+	BaseEndpoint       = web.PolicyBaseEndpoint
+	VersionEndpoint    = web.PolicyVersionEndpoint
+	ValidationEndpoint = web.PolicyValidationEndpoint
 )
 
 //
@@ -19,6 +19,7 @@ import (
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	liburl "net/url"
 	"path"
+	"sync/atomic"
 	"time"
 )
 
@@ -66,6 +67,7 @@ const (
 	fDrsEnabled    = "configuration.drsConfig.enabled"
 	fDrsVmBehavior = "configuration.drsConfig.defaultVmBehavior"
 	fDrsVmCfg      = "configuration.drsVmConfig"
+	fClusterRule   = "configuration.rule"
 	// Host
 	fVm             = "vm"
 	fProductName    = "config.product.name"
@@ -93,6 +95,7 @@ const (
 	// VM
 	fUUID                = "config.uuid"
 	fFirmware            = "config.firmware"
+	fSecureBoot          = "config.bootOptions.efiSecureBootEnabled"
 	fFtInfo              = "config.ftInfo"
 	fCpuAffinity         = "config.cpuAffinity"
 	fCpuHotAddEnabled    = "config.cpuHotAddEnabled"
@@ -107,6 +110,9 @@ const (
 	fGuestName           = "summary.config.guestFullName"
 	fBalloonedMemory     = "summary.quickStats.balloonedMemory"
 	fVmIpAddress         = "summary.guest.ipAddress"
+	fGuestHostName       = "guest.hostName"
+	fGuestNet            = "guest.net"
+	fToolsRunningStatus  = "guest.toolsRunningStatus"
 	fStorageUsed         = "summary.storage.committed"
 	fRuntimeHost         = "runtime.host"
 	fPowerState          = "runtime.powerState"
@@ -239,6 +245,10 @@ type Collector struct {
 	cancel func()
 	// has parity.
 	parity bool
+	// Requested refresh revision.
+	requested int64
+	// Refreshed revision.
+	refreshed int64
 }
 
 //
@@ -339,6 +349,26 @@ func (r *Collector) Start() error {
 	return nil
 }
 
+//
+// Request an immediate refresh.
+// Changes are pushed by the property collector so the
+// inventory is current once parity has been achieved.
+// Returns the revision of the requested refresh.
+func (r *Collector) Refresh() (revision int64) {
+	revision = atomic.AddInt64(&r.requested, 1)
+	return
+}
+
+//
+// The revision of the last completed refresh.
+func (r *Collector) Refreshed() (revision int64) {
+	if r.parity {
+		atomic.StoreInt64(&r.refreshed, atomic.LoadInt64(&r.requested))
+	}
+	revision = atomic.LoadInt64(&r.refreshed)
+	return
+}
+
 //
 // Shutdown the collector.
 func (r *Collector) Shutdown() {
@@ -638,6 +668,7 @@ func (r *Collector) propertySpec() []types.PropertySpec {
 				fDrsEnabled,
 				fDrsVmBehavior,
 				fDrsVmCfg,
+				fClusterRule,
 				fHost,
 				fNetwork,
 				fDatastore,
@@ -707,6 +738,7 @@ func (r *Collector) propertySpec() []types.PropertySpec {
 				fParent,
 				fUUID,
 				fFirmware,
+				fSecureBoot,
 				fFtInfo,
 				fCpuAffinity,
 				fCpuHotAddEnabled,
@@ -720,6 +752,9 @@ func (r *Collector) propertySpec() []types.PropertySpec {
 				fGuestName,
 				fBalloonedMemory,
 				fVmIpAddress,
+				fGuestHostName,
+				fGuestNet,
+				fToolsRunningStatus,
 				fStorageUsed,
 				fDatastore,
 				fNetwork,
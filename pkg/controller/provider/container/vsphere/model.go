@@ -204,8 +204,8 @@ func (v *ClusterAdapter) Apply(u types.ObjectUpdate) {
 				}
 			case fDasVmCfg:
 				refList := []model.Ref{}
-				if list, cast := p.Val.([]types.ClusterDasVmConfigInfo); cast {
-					for _, val := range list {
+				if array, cast := p.Val.(types.ArrayOfClusterDasVmConfigInfo); cast {
+					for _, val := range array.ClusterDasVmConfigInfo {
 						refList = append(refList, v.Ref(val.Key))
 					}
 				}
@@ -216,8 +216,8 @@ func (v *ClusterAdapter) Apply(u types.ObjectUpdate) {
 				}
 			case fDrsVmCfg:
 				refList := []model.Ref{}
-				if list, cast := p.Val.([]types.ClusterDrsVmConfigInfo); cast {
-					for _, val := range list {
+				if array, cast := p.Val.(types.ArrayOfClusterDrsVmConfigInfo); cast {
+					for _, val := range array.ClusterDrsVmConfigInfo {
 						refList = append(refList, v.Ref(val.Key))
 					}
 				}
@@ -226,11 +226,35 @@ func (v *ClusterAdapter) Apply(u types.ObjectUpdate) {
 				if b, cast := p.Val.(types.DrsBehavior); cast {
 					v.model.DrsBehavior = string(b)
 				}
+			case fClusterRule:
+				v.model.AntiAffinity = []model.AntiAffinityRule{}
+				if array, cast := p.Val.(types.ArrayOfClusterRuleInfo); cast {
+					v.updateAntiAffinity(&array)
+				}
 			}
 		}
 	}
 }
 
+//
+// Update the VM anti-affinity rules.
+// Other (affinity, host group) rules are ignored.
+func (v *ClusterAdapter) updateAntiAffinity(array *types.ArrayOfClusterRuleInfo) {
+	for _, info := range array.ClusterRuleInfo {
+		if rule, cast := info.(*types.ClusterAntiAffinityRuleSpec); cast {
+			antiAffinity := model.AntiAffinityRule{
+				Name:    rule.Name,
+				Enabled: rule.Enabled != nil && *rule.Enabled,
+				VMs:     []model.Ref{},
+			}
+			for _, ref := range rule.Vm {
+				antiAffinity.VMs = append(antiAffinity.VMs, v.Ref(ref))
+			}
+			v.model.AntiAffinity = append(v.model.AntiAffinity, antiAffinity)
+		}
+	}
+}
+
 //
 // Host model adapter.
 type HostAdapter struct {
@@ -529,6 +553,10 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				if s, cast := p.Val.(string); cast {
 					v.model.Firmware = s
 				}
+			case fSecureBoot:
+				if b, cast := p.Val.(bool); cast {
+					v.model.SecureBoot = b
+				}
 			case fPowerState:
 				if s, cast := p.Val.(types.VirtualMachinePowerState); cast {
 					v.model.PowerState = string(s)
@@ -598,6 +626,18 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				if s, cast := p.Val.(string); cast {
 					v.model.IpAddress = s
 				}
+			case fGuestHostName:
+				if s, cast := p.Val.(string); cast {
+					v.model.HostName = s
+				}
+			case fToolsRunningStatus:
+				if s, cast := p.Val.(string); cast {
+					v.model.ToolsRunningStatus = s
+				}
+			case fGuestNet:
+				if nicArray, cast := p.Val.(types.ArrayOfGuestNicInfo); cast {
+					v.updateGuestNetworks(&nicArray)
+				}
 			case fFtInfo:
 				if _, cast := p.Val.(types.FaultToleranceConfigInfo); cast {
 					v.model.FaultToleranceEnabled = true
@@ -624,7 +664,8 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 						case *types.VirtualSriovEthernetCard,
 							*types.VirtualPCIPassthrough,
 							*types.VirtualSCSIPassthrough,
-							*types.VirtualUSBController:
+							*types.VirtualUSBController,
+							*types.VirtualSerialPort:
 							list = append(
 								list,
 								model.Device{
@@ -652,6 +693,7 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 			case *types.VirtualDiskFlatVer1BackingInfo:
 				backing := disk.Backing.(*types.VirtualDiskFlatVer1BackingInfo)
 				md := model.Disk{
+					ID:       v.diskID(disk),
 					File:     backing.FileName,
 					Capacity: disk.CapacityInBytes,
 					Datastore: model.Ref{
@@ -663,6 +705,7 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 			case *types.VirtualDiskFlatVer2BackingInfo:
 				backing := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
 				md := model.Disk{
+					ID:       v.diskID(disk),
 					File:     backing.FileName,
 					Capacity: disk.CapacityInBytes,
 					Shared:   backing.Sharing != "sharingNone",
@@ -675,6 +718,7 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 			case *types.VirtualDiskRawDiskMappingVer1BackingInfo:
 				backing := disk.Backing.(*types.VirtualDiskRawDiskMappingVer1BackingInfo)
 				md := model.Disk{
+					ID:       v.diskID(disk),
 					File:     backing.FileName,
 					Capacity: disk.CapacityInBytes,
 					Shared:   backing.Sharing != "sharingNone",
@@ -688,6 +732,7 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 			case *types.VirtualDiskRawDiskVer2BackingInfo:
 				backing := disk.Backing.(*types.VirtualDiskRawDiskVer2BackingInfo)
 				md := model.Disk{
+					ID:       v.diskID(disk),
 					Capacity: disk.CapacityInBytes,
 					Shared:   backing.Sharing != "sharingNone",
 					RDM:      true,
@@ -699,3 +744,33 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 
 	v.model.Disks = disks
 }
+
+//
+// Update guest (reported) networks.
+func (v *VmAdapter) updateGuestNetworks(nicArray *types.ArrayOfGuestNicInfo) {
+	list := []model.GuestNetwork{}
+	for _, nic := range nicArray.GuestNicInfo {
+		ips := []string{}
+		ips = append(ips, nic.IpAddress...)
+		list = append(
+			list,
+			model.GuestNetwork{
+				Device:  nic.DeviceConfigId,
+				Network: nic.Network,
+				MAC:     nic.MacAddress,
+				IPs:     ips,
+			})
+	}
+
+	v.model.GuestNetworks = list
+}
+
+//
+// The (virtual) disk ID.
+func (v *VmAdapter) diskID(disk *types.VirtualDisk) string {
+	if disk.VDiskId != nil {
+		return disk.VDiskId.Id
+	}
+
+	return disk.DiskObjectId
+}
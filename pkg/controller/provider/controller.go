@@ -35,6 +35,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/settings"
 	core "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apiserver/pkg/storage/names"
 	"os"
 	"path/filepath"
@@ -65,7 +66,7 @@ var Settings = &settings.Settings
 func Add(mgr manager.Manager) error {
 	libfb.WorkingDir = Settings.WorkingDir
 	container := libcontainer.New()
-	web := libweb.New(container, web.All(container)...)
+	web := libweb.New(container, web.All(container, mgr.GetClient())...)
 	web.Port = Settings.Inventory.Port
 	web.TLS.Enabled = Settings.Inventory.TLS.Enabled
 	web.TLS.Certificate = Settings.Inventory.TLS.Certificate
@@ -250,19 +251,27 @@ func (r *Reconciler) updateProvider(provider *api.Provider) (err error) {
 //
 // Update the container.
 func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
-	if _, found := r.container.Get(provider); found {
-		if provider.HasReconciled() {
-			r.Log.V(1).Info(
-				"Provider not reconciled, postponing.")
-			return
-		}
-	}
 	if provider.Status.HasBlockerCondition() ||
 		!provider.Status.HasCondition(ConnectionTestSucceeded) {
 		r.Log.V(1).Info(
 			"Provider not ready, postponing.")
 		return
 	}
+	secret, err := r.getSecret(provider)
+	if err != nil {
+		return
+	}
+	if _, found := r.container.Get(provider); found {
+		if provider.HasReconciled() {
+			if !r.catalog.secretChanged(provider, secret) {
+				r.Log.V(1).Info(
+					"Provider not reconciled, postponing.")
+				return
+			}
+			r.Log.Info(
+				"Provider secret changed, reconnecting.")
+		}
+	}
 	log.Info("Update container.")
 	if current, found := r.container.Get(provider); found {
 		current.Shutdown()
@@ -271,10 +280,6 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 			"Shutdown found collector.")
 	}
 	db := r.getDB(provider)
-	secret, err := r.getSecret(provider)
-	if err != nil {
-		return
-	}
 	err = db.Open(true)
 	if err != nil {
 		return
@@ -284,6 +289,7 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 	if err != nil {
 		return
 	}
+	r.catalog.setSecret(provider, secret)
 
 	r.Log.V(2).Info(
 		"Data collector added/started.")
@@ -336,6 +342,9 @@ func (r *Reconciler) getSecret(provider *api.Provider) (*core.Secret, error) {
 type Catalog struct {
 	mutex   sync.Mutex
 	content map[reconcile.Request]*api.Provider
+	// Secret (resource) version used by the
+	// collector keyed by provider UID.
+	secrets map[types.UID]string
 }
 
 //
@@ -360,3 +369,25 @@ func (r *Catalog) get(request reconcile.Request) (p *api.Provider, found bool) {
 	p, found = r.content[request]
 	return
 }
+
+//
+// Record the secret used to build the collector.
+func (r *Catalog) setSecret(p *api.Provider, secret *core.Secret) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.secrets == nil {
+		r.secrets = make(map[types.UID]string)
+	}
+	r.secrets[p.UID] = secret.ResourceVersion
+}
+
+//
+// Determine whether the secret has changed (credentials
+// rotated) since the collector was built.
+func (r *Catalog) secretChanged(p *api.Provider, secret *core.Secret) (changed bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	version, found := r.secrets[p.UID]
+	changed = found && version != secret.ResourceVersion
+	return
+}
@@ -53,9 +53,10 @@ type DataCenter struct {
 
 type Cluster struct {
 	Base
-	DataCenter    string `sql:"d0,index(dataCenter)"`
-	HaReservation bool   `sql:""`
-	KsmEnabled    bool   `sql:""`
+	DataCenter     string          `sql:"d0,index(dataCenter)"`
+	HaReservation  bool            `sql:""`
+	KsmEnabled     bool            `sql:""`
+	AffinityGroups []AffinityGroup `sql:""`
 }
 
 type Network struct {
@@ -98,8 +99,8 @@ type Host struct {
 	ProductName        string              `sql:""`
 	ProductVersion     string              `sql:""`
 	InMaintenance      bool                `sql:""`
-	CpuSockets         int16               `sql:""`
-	CpuCores           int16               `sql:""`
+	CpuSockets         int16               `sql:"d0"`
+	CpuCores           int16               `sql:"d0"`
 	NetworkAttachments []NetworkAttachment `sql:""`
 	NICs               []HostNIC           `sql:""`
 }
@@ -124,13 +125,14 @@ type VM struct {
 	RevisionValidated           int64            `sql:"d0,index(revisionValidated)" eq:"-"`
 	PolicyVersion               int              `sql:"d0,index(policyVersion)" eq:"-"`
 	GuestName                   string           `sql:""`
+	HostName                    string           `sql:""`
 	CpuSockets                  int16            `sql:""`
 	CpuCores                    int16            `sql:""`
 	CpuAffinity                 []CpuPinning     `sql:""`
 	CpuShares                   int16            `sql:""`
-	Memory                      int64            `sql:""`
+	Memory                      int64            `sql:"d0"`
 	BalloonedMemory             bool             `sql:""`
-	BIOS                        string           `sql:""`
+	BIOS                        string           `sql:"d0"`
 	Display                     string           `sql:""`
 	IOThreads                   int16            `sql:""`
 	StorageErrorResumeBehaviour string           `sql:""`
@@ -179,6 +181,7 @@ type NIC struct {
 	Name      string      `json:"name"`
 	Interface string      `json:"interface"`
 	Plugged   bool        `json:"plugged"`
+	MAC       string      `json:"mac"`
 	IpAddress []IpAddress `json:"ipAddress"`
 	Profile   string      `json:"profile"`
 }
@@ -210,6 +213,14 @@ type WatchDog struct {
 	Model  string `json:"model"`
 }
 
+type AffinityGroup struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Enforcing bool     `json:"enforcing"`
+	Positive  bool     `json:"positive"`
+	VMs       []string `json:"vms"`
+}
+
 type Property struct {
 	Name  string `json:"name"`
 	Value string `json:"value"`
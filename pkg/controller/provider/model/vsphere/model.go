@@ -3,9 +3,15 @@ package vsphere
 import (
 	libmodel "github.com/konveyor/controller/pkg/inventory/model"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/base"
+	"regexp"
 	"strings"
 )
 
+//
+// Regex which matches the snapshot identifier suffix of a
+// vSphere disk backing file.
+var backingFilePattern = regexp.MustCompile("-\\d+.vmdk")
+
 //
 // Networks (variant).
 const (
@@ -177,15 +183,25 @@ type Datacenter struct {
 
 type Cluster struct {
 	Base
-	Folder      string `sql:"d0,index(folder)"`
-	Hosts       []Ref  `sql:""`
-	Networks    []Ref  `sql:""`
-	Datastores  []Ref  `sql:""`
-	DasEnabled  bool   `sql:""`
-	DasVms      []Ref  `sql:""`
-	DrsEnabled  bool   `sql:""`
-	DrsBehavior string `sql:""`
-	DrsVms      []Ref  `sql:""`
+	Folder       string             `sql:"d0,index(folder)"`
+	Hosts        []Ref              `sql:""`
+	Networks     []Ref              `sql:""`
+	Datastores   []Ref              `sql:""`
+	DasEnabled   bool               `sql:""`
+	DasVms       []Ref              `sql:""`
+	DrsEnabled   bool               `sql:""`
+	DrsBehavior  string             `sql:""`
+	DrsVms       []Ref              `sql:""`
+	AntiAffinity []AntiAffinityRule `sql:""`
+}
+
+//
+// DRS VM anti-affinity rule (group).
+// The VMs are placed on separate hosts.
+type AntiAffinityRule struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	VMs     []Ref  `json:"vms"`
 }
 
 type Host struct {
@@ -296,34 +312,38 @@ type Datastore struct {
 
 type VM struct {
 	Base
-	Folder                string    `sql:"d0,index(folder)"`
-	Host                  string    `sql:"d0,index(host)"`
-	RevisionValidated     int64     `sql:"d0,index(revisionValidated)"`
-	PolicyVersion         int       `sql:"d0,index(policyVersion)"`
-	UUID                  string    `sql:""`
-	Firmware              string    `sql:""`
-	PowerState            string    `sql:""`
-	ConnectionState       string    `sql:""`
-	CpuAffinity           []int32   `sql:""`
-	CpuHotAddEnabled      bool      `sql:""`
-	CpuHotRemoveEnabled   bool      `sql:""`
-	MemoryHotAddEnabled   bool      `sql:""`
-	FaultToleranceEnabled bool      `sql:""`
-	CpuCount              int32     `sql:""`
-	CoresPerSocket        int32     `sql:""`
-	MemoryMB              int32     `sql:""`
-	GuestName             string    `sql:""`
-	BalloonedMemory       int32     `sql:""`
-	IpAddress             string    `sql:""`
-	NumaNodeAffinity      []string  `sql:""`
-	StorageUsed           int64     `sql:""`
-	Snapshot              Ref       `sql:""`
-	IsTemplate            bool      `sql:""`
-	ChangeTrackingEnabled bool      `sql:""`
-	Devices               []Device  `sql:""`
-	Disks                 []Disk    `sql:""`
-	Networks              []Ref     `sql:""`
-	Concerns              []Concern `sql:""`
+	Folder                string         `sql:"d0,index(folder)"`
+	Host                  string         `sql:"d0,index(host)"`
+	RevisionValidated     int64          `sql:"d0,index(revisionValidated)"`
+	PolicyVersion         int            `sql:"d0,index(policyVersion)"`
+	UUID                  string         `sql:""`
+	Firmware              string         `sql:"d0"`
+	SecureBoot            bool           `sql:"d0"`
+	PowerState            string         `sql:""`
+	ConnectionState       string         `sql:""`
+	CpuAffinity           []int32        `sql:""`
+	CpuHotAddEnabled      bool           `sql:""`
+	CpuHotRemoveEnabled   bool           `sql:""`
+	MemoryHotAddEnabled   bool           `sql:""`
+	FaultToleranceEnabled bool           `sql:""`
+	CpuCount              int32          `sql:"d0"`
+	CoresPerSocket        int32          `sql:"d0"`
+	MemoryMB              int32          `sql:"d0"`
+	GuestName             string         `sql:""`
+	BalloonedMemory       int32          `sql:""`
+	IpAddress             string         `sql:""`
+	HostName              string         `sql:""`
+	ToolsRunningStatus    string         `sql:""`
+	NumaNodeAffinity      []string       `sql:""`
+	StorageUsed           int64          `sql:""`
+	Snapshot              Ref            `sql:""`
+	IsTemplate            bool           `sql:""`
+	ChangeTrackingEnabled bool           `sql:"d0"`
+	Devices               []Device       `sql:""`
+	Disks                 []Disk         `sql:""`
+	Networks              []Ref          `sql:""`
+	GuestNetworks         []GuestNetwork `sql:""`
+	Concerns              []Concern      `sql:""`
 }
 
 //
@@ -335,6 +355,7 @@ func (m *VM) Validated() bool {
 //
 // Virtual Disk.
 type Disk struct {
+	ID        string `json:"id"`
 	File      string `json:"file"`
 	Datastore Ref    `json:"datastore"`
 	Capacity  int64  `json:"capacity"`
@@ -342,6 +363,24 @@ type Disk struct {
 	RDM       bool   `json:"rdm"`
 }
 
+//
+// Trims the snapshot suffix from a disk backing file name if there is one.
+//	Example:
+// 	Input: 	[datastore13] my-vm/disk-name-000015.vmdk
+//	Output: [datastore13] my-vm/disk-name.vmdk
+func TrimBackingFileName(fileName string) string {
+	return backingFilePattern.ReplaceAllString(fileName, ".vmdk")
+}
+
+//
+// Guest (reported) network.
+type GuestNetwork struct {
+	Device  int32    `json:"device"`
+	Network string   `json:"network"`
+	MAC     string   `json:"mac"`
+	IPs     []string `json:"ips"`
+}
+
 //
 // Virtual Device.
 type Device struct {
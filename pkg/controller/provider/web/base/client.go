@@ -118,6 +118,18 @@ type Finder interface {
 	Host(ref *Ref) (interface{}, error)
 }
 
+//
+// Resource Finder which resolves VMs in batch.
+type BatchFinder interface {
+	// Find VMs by ref.
+	// Refs are resolved in place. Refs not found are omitted.
+	// Returns the matching resources keyed by (resolved) ref and:
+	//   ProviderNotSupportedErr
+	//   ProviderNotReadyErr
+	//   RefNotUniqueErr
+	VMs(refs []*Ref) (map[Ref]interface{}, error)
+}
+
 //
 // REST Client.
 type Client interface {
@@ -157,6 +169,13 @@ type Client interface {
 	//   NotFoundErr
 	//   RefNotUniqueErr
 	VM(ref *Ref) (interface{}, error)
+	// Find VMs by ref.
+	// Refs are resolved in place. Refs not found are omitted.
+	// Returns the matching resources keyed by (resolved) ref and:
+	//   ProviderNotSupportedErr
+	//   ProviderNotReadyErr
+	//   RefNotUniqueErr
+	VMs(refs []*Ref) (map[Ref]interface{}, error)
 	// Find a Workload by ref.
 	// Returns the matching resource and:
 	//   ProviderNotSupportedErr
@@ -0,0 +1,150 @@
+package base
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+//
+// Sparse fieldset parameter.
+const (
+	FieldsParam = "fields"
+)
+
+//
+// Field not valid.
+type FieldNotValidError struct {
+	Field string
+}
+
+func (r FieldNotValidError) Error() string {
+	return fmt.Sprintf("Field `%s` not valid.", r.Field)
+}
+
+//
+// Set the sparse fieldset.
+// Selecting fields implies detail.
+func (h *Handler) setFields(ctx *gin.Context) int {
+	h.Fields = nil
+	q := ctx.Request.URL.Query()
+	pFields := q.Get(FieldsParam)
+	if len(pFields) == 0 {
+		return http.StatusOK
+	}
+	for _, name := range strings.Split(pFields, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) > 0 {
+			h.Fields = append(h.Fields, name)
+		}
+	}
+	if len(h.Fields) == 0 {
+		return http.StatusBadRequest
+	}
+
+	h.Detail = true
+
+	return http.StatusOK
+}
+
+//
+// Render the content (JSON or YAML).
+// The sort order and sparse fieldset are applied as requested.
+func (h *Handler) Render(ctx *gin.Context, content interface{}) {
+	if list, cast := content.([]interface{}); cast {
+		content = h.Sort.Apply(list)
+	}
+	content, err := h.Select(content)
+	if err != nil {
+		log.V(3).Info(
+			err.Error(),
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	h.write(ctx, content)
+}
+
+//
+// Select the requested fields.
+// The content may be a resource or a list of resources.
+// Returns: FieldNotValidError when a requested field is
+// not defined by the resource.
+func (h *Handler) Select(content interface{}) (selected interface{}, err error) {
+	if len(h.Fields) == 0 {
+		selected = content
+		return
+	}
+	if list, cast := content.([]interface{}); cast {
+		kept := []interface{}{}
+		for _, r := range list {
+			object, sErr := h.selectFields(r)
+			if sErr != nil {
+				err = sErr
+				return
+			}
+			kept = append(kept, object)
+		}
+		selected = kept
+	} else {
+		selected, err = h.selectFields(content)
+	}
+
+	return
+}
+
+//
+// Select the requested fields of a resource.
+func (h *Handler) selectFields(r interface{}) (selected map[string]interface{}, err error) {
+	all := map[string]reflect.Value{}
+	jsonFields(reflect.ValueOf(r), all)
+	selected = map[string]interface{}{}
+	for _, name := range h.Fields {
+		v, found := all[name]
+		if !found {
+			err = FieldNotValidError{Field: name}
+			return
+		}
+		selected[name] = v.Interface()
+	}
+
+	return
+}
+
+//
+// Collect the (JSON) fields of a struct keyed by name.
+// Anonymous (embedded) structs are inlined.
+func jsonFields(v reflect.Value, fields map[string]reflect.Value) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+		if ft.PkgPath != "" && !ft.Anonymous {
+			continue
+		}
+		name := strings.Split(ft.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if ft.Anonymous && name == "" {
+			jsonFields(v.Field(i), fields)
+			continue
+		}
+		if name == "" {
+			name = ft.Name
+		}
+		fields[name] = v.Field(i)
+	}
+}
@@ -0,0 +1,54 @@
+package base
+
+import (
+	"errors"
+	"github.com/onsi/gomega"
+	"testing"
+)
+
+type testResource struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type testVM struct {
+	testResource
+	PowerState string   `json:"powerState"`
+	Disks      []string `json:"disks"`
+	internal   string
+}
+
+func TestSelect(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	vm := &testVM{
+		testResource: testResource{
+			ID:   "vm-1",
+			Name: "test",
+		},
+		PowerState: "poweredOn",
+		Disks:      []string{"disk-1"},
+		internal:   "x",
+	}
+	// Not requested.
+	h := Handler{}
+	content, err := h.Select(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(content).To(gomega.Equal(vm))
+	// Resource.
+	h.Fields = []string{"id", "powerState"}
+	content, err = h.Select(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(content).To(gomega.Equal(
+		map[string]interface{}{
+			"id":         "vm-1",
+			"powerState": "poweredOn",
+		}))
+	// List.
+	content, err = h.Select([]interface{}{vm, vm})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(content.([]interface{}))).To(gomega.Equal(2))
+	// Unknown.
+	h.Fields = []string{"id", "internal"}
+	_, err = h.Select(vm)
+	g.Expect(errors.As(err, &FieldNotValidError{})).To(gomega.BeTrue())
+}
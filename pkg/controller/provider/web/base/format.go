@@ -0,0 +1,87 @@
+package base
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+//
+// Format parameter.
+const (
+	FormatParam = "format"
+)
+
+//
+// Formats.
+const (
+	JSON   = "json"
+	YAML   = "yaml"
+	NDJSON = "ndjson"
+)
+
+//
+// YAML media types.
+var yamlMediaTypes = []string{
+	"application/yaml",
+	"application/x-yaml",
+	"text/yaml",
+}
+
+//
+// Set the (response) format.
+// The `format` parameter takes precedence over
+// the Accept header. Defaults to JSON.
+func (h *Handler) setFormat(ctx *gin.Context) int {
+	h.Format = JSON
+	q := ctx.Request.URL.Query()
+	pFormat := q.Get(FormatParam)
+	if len(pFormat) > 0 {
+		switch strings.ToLower(pFormat) {
+		case JSON:
+		case YAML:
+			h.Format = YAML
+		case NDJSON:
+			h.Format = NDJSON
+		default:
+			return http.StatusNotAcceptable
+		}
+		return http.StatusOK
+	}
+	accept := strings.ToLower(ctx.GetHeader("Accept"))
+	if strings.Contains(accept, NDJSONMediaType) {
+		h.Format = NDJSON
+		return http.StatusOK
+	}
+	for _, mediaType := range yamlMediaTypes {
+		if strings.Contains(accept, mediaType) {
+			h.Format = YAML
+			break
+		}
+	}
+
+	return http.StatusOK
+}
+
+//
+// Write the content in the requested format.
+// YAML is marshaled using the JSON tags. Content not
+// streamed is written as JSON when NDJSON is requested.
+func (h *Handler) write(ctx *gin.Context, content interface{}) {
+	if h.Format != YAML {
+		ctx.JSON(http.StatusOK, content)
+		return
+	}
+	b, err := yaml.Marshal(content)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Data(http.StatusOK, yamlMediaTypes[0], b)
+}
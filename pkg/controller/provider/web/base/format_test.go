@@ -0,0 +1,91 @@
+package base
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	fb "github.com/konveyor/controller/pkg/filebacked"
+	"github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	request := func(url, accept string) (h Handler, status int, w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest(http.MethodGet, url, nil)
+		if accept != "" {
+			ctx.Request.Header.Set("Accept", accept)
+		}
+		status = h.setFormat(ctx)
+		if status == http.StatusOK {
+			h.write(ctx, &testResource{ID: "vm-1", Name: "test"})
+		}
+		return
+	}
+	// Default.
+	h, status, w := request("/vms", "")
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(h.Format).To(gomega.Equal(JSON))
+	g.Expect(w.Body.String()).To(gomega.Equal(`{"id":"vm-1","name":"test"}`))
+	// Accept.
+	h, status, w = request("/vms", "application/yaml")
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(h.Format).To(gomega.Equal(YAML))
+	g.Expect(w.Body.String()).To(gomega.Equal("id: vm-1\nname: test\n"))
+	// Parameter.
+	h, status, _ = request("/vms?format=yaml", "application/json")
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(h.Format).To(gomega.Equal(YAML))
+	// NDJSON (not streamed).
+	h, status, w = request("/vms", NDJSONMediaType)
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(h.Streaming()).To(gomega.BeTrue())
+	g.Expect(w.Body.String()).To(gomega.Equal(`{"id":"vm-1","name":"test"}`))
+	// Not valid.
+	_, status, _ = request("/vms?format=xml", "")
+	g.Expect(status).To(gomega.Equal(http.StatusNotAcceptable))
+}
+
+func TestStream(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	list := fb.NewList()
+	defer list.Close()
+	list.Append(&testResource{ID: "vm-1", Name: "test"})
+	list.Append(&testResource{ID: "vm-2", Name: "other"})
+	failed := errors.New("failed")
+	stream := func(buildErr error, fields ...string) (w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/vms", nil)
+		h := Handler{Fields: fields}
+		h.Stream(
+			ctx,
+			list.Iter(),
+			func(itr fb.Iterator, index int) (content interface{}, kept bool, err error) {
+				r := &testResource{}
+				itr.AtWith(index, r)
+				content = r
+				kept = true
+				err = buildErr
+				return
+			})
+		ctx.Writer.WriteHeaderNow()
+		return
+	}
+	// Streamed.
+	w := stream(nil, "id")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(w.Header().Get("Content-Type")).To(gomega.Equal(NDJSONMediaType))
+	g.Expect(w.Body.String()).To(gomega.Equal("{\"id\":\"vm-1\"}\n{\"id\":\"vm-2\"}\n"))
+	// Field not valid.
+	w = stream(nil, "id", "unknown")
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+	g.Expect(w.Body.String()).To(gomega.BeEmpty())
+	// Build failed.
+	w = stream(failed)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusInternalServerError))
+	g.Expect(w.Body.String()).To(gomega.BeEmpty())
+}
@@ -26,9 +26,15 @@ const (
 //
 // Header.
 const (
-	ProviderHeader = "X-Provider"
+	ProviderHeader   = "X-Provider"
+	RetryAfterHeader = "Retry-After"
 )
 
+//
+// Seconds a client should wait before retrying a
+// request while the collector is still syncing.
+var RetryAfter = 10
+
 //
 // Params
 type Params = map[string]string
@@ -59,6 +65,18 @@ type Handler struct {
 	Collector libcontainer.Collector
 	// Resources include details.
 	Detail bool
+	// Watch revision cursor.
+	Cursor Cursor
+	// Watch (event) filter.
+	WatchFilter WatchFilter
+	// Revision (since) filter.
+	Since Since
+	// Sparse fieldset.
+	Fields []string
+	// Collection sort.
+	Sort Sort
+	// Response format.
+	Format string
 }
 
 //
@@ -72,10 +90,30 @@ func (h *Handler) Prepare(ctx *gin.Context) int {
 	if status != http.StatusOK {
 		return status
 	}
+	status = h.Cursor.Prepare(ctx)
+	if status != http.StatusOK {
+		return status
+	}
+	status = h.Since.Prepare(ctx)
+	if status != http.StatusOK {
+		return status
+	}
 	status = h.setDetail(ctx)
 	if status != http.StatusOK {
 		return status
 	}
+	status = h.setFields(ctx)
+	if status != http.StatusOK {
+		return status
+	}
+	status = h.Sort.Prepare(ctx)
+	if status != http.StatusOK {
+		return status
+	}
+	status = h.setFormat(ctx)
+	if status != http.StatusOK {
+		return status
+	}
 	status = h.setProvider(ctx)
 	if status != http.StatusOK {
 		return status
@@ -97,6 +135,8 @@ func (h *Handler) Link(path string, params Params) string {
 //
 // Set the provider.
 // Set the Provider field and the X-Provider header.
+// While the collector has not completed the initial sync
+// (parity), 503 is returned with the Retry-After header.
 func (h *Handler) setProvider(ctx *gin.Context) (status int) {
 	var found bool
 	uid := ctx.Param(ProviderParam)
@@ -113,6 +153,10 @@ func (h *Handler) setProvider(ctx *gin.Context) (status int) {
 		ctx.Header(ProviderHeader, uid)
 		h.Provider = h.Collector.Owner().(*api.Provider)
 		status = h.EnsureParity(h.Collector, time.Second*10)
+		if status == http.StatusPartialContent {
+			ctx.Header(RetryAfterHeader, strconv.Itoa(RetryAfter))
+			status = http.StatusServiceUnavailable
+		}
 	} else {
 		status = http.StatusOK
 	}
@@ -0,0 +1,364 @@
+package base
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr"
+	"github.com/gorilla/websocket"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	libweb "github.com/konveyor/controller/pkg/inventory/web"
+	"github.com/konveyor/controller/pkg/logging"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+//
+// Params.
+const (
+	// List of collections (multiplexed watch).
+	CollectionsParam = "collections"
+)
+
+//
+// Collection watched (multiplexed watch).
+type WatchedCollection struct {
+	// Collection name used to tag events.
+	Kind string
+	// Model (kind) watched.
+	Model libmodel.Model
+	// Resource builder.
+	Builder libweb.ResourceBuilder
+	// Event filter.
+	Filter WatchFilter
+}
+
+//
+// Multiplexed watch event.
+// The (libweb) event tagged with the collection.
+type WatchEvent struct {
+	// Collection.
+	Kind string
+	libweb.Event
+}
+
+//
+// The collections requested by the `collections` parameter.
+// The value is a (comma separated) list.
+func (h *Handler) Collections(ctx *gin.Context) (list []string) {
+	q := ctx.Request.URL.Query()
+	for _, value := range q[CollectionsParam] {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if len(name) > 0 {
+				list = append(list, name)
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Watch multiple collections.
+// The connection is upgraded once and the events for each collection
+// are forwarded on the same websocket tagged with the collection.
+// Each collection is watched using the same (model) watch as the
+// collection endpoint and the collection filter is applied. A revision
+// cursor is not supported because revisions are kept per collection.
+// The watches are ended when the socket is closed by the peer (or
+// broken) and the socket is closed when all the watches have ended.
+func (h *Handler) MultiWatch(
+	ctx *gin.Context,
+	db libmodel.DB,
+	collections []WatchedCollection) (err error) {
+	//
+	if h.Cursor.Set {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	upGrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+	}
+	socket, err := upGrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"websocket upgrade failed.",
+			"url",
+			ctx.Request.URL)
+		return
+	}
+	writer := &MultiWatchWriter{
+		webSocket: socket,
+		remaining: len(collections),
+		log: logging.WithName("web|watch|mux").WithValues(
+			"peer",
+			socket.RemoteAddr()),
+	}
+	options := libmodel.WatchOptions{
+		Snapshot: h.snapshot(ctx),
+	}
+	for _, collection := range collections {
+		watchDB := db
+		if collection.Filter != nil {
+			watchDB = &filterDB{
+				DB:     watchDB,
+				filter: collection.Filter,
+			}
+		}
+		watch, wErr := watchDB.Watch(
+			collection.Model,
+			&multiWatchHandler{
+				writer:  writer,
+				kind:    collection.Kind,
+				builder: collection.Builder,
+				options: options,
+			})
+		if wErr != nil {
+			err = liberr.Wrap(wErr)
+			writer.Abort()
+			return
+		}
+		writer.Add(watch)
+	}
+
+	writer.Start()
+
+	log.V(3).Info(
+		"handler: multiplexed watch created.",
+		"url",
+		ctx.Request.URL)
+
+	return
+}
+
+//
+// The snapshot option was passed in the X-Watch header.
+func (h *Handler) snapshot(ctx *gin.Context) (snapshot bool) {
+	header := ctx.Request.Header[libweb.WatchHeader]
+	for _, option := range header {
+		for _, part := range strings.Split(option, ",") {
+			if strings.TrimSpace(part) == libweb.WatchSnapshot {
+				snapshot = true
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Multiplexed watch (event) writer.
+// Shared by the watches. Sends the events on the
+// websocket and ends the watches when the socket
+// is closed by the peer.
+type MultiWatchWriter struct {
+	mutex sync.Mutex
+	// Negotiated web socket.
+	webSocket *websocket.Conn
+	// Watches.
+	watches []*libmodel.Watch
+	// Number of watches not ended.
+	remaining int
+	// Logger.
+	log logr.Logger
+	// Done.
+	done bool
+}
+
+//
+// Add a watch.
+func (r *MultiWatchWriter) Add(watch *libmodel.Watch) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.watches = append(r.watches, watch)
+}
+
+//
+// Start the writer.
+// Detect connection closed by peer or broken
+// and end the watches.
+func (r *MultiWatchWriter) Start() {
+	go func() {
+		defer func() {
+			r.log.V(3).Info("stopped.")
+		}()
+		for {
+			event := WatchEvent{}
+			err := r.webSocket.ReadJSON(&event)
+			if r.isDone() {
+				return
+			}
+			if err != nil {
+				r.log.V(4).Info(err.Error())
+				r.EndAll()
+				return
+			}
+			switch event.Action {
+			case libmodel.End:
+				r.log.V(4).Info("ended by peer.")
+				r.EndAll()
+				return
+			}
+		}
+	}()
+}
+
+//
+// End all of the watches.
+func (r *MultiWatchWriter) EndAll() {
+	r.mutex.Lock()
+	watches := r.watches
+	r.watches = nil
+	r.mutex.Unlock()
+	for _, watch := range watches {
+		watch.End()
+	}
+}
+
+//
+// Abort.
+// The socket is closed and the watches
+// (already created) are ended.
+func (r *MultiWatchWriter) Abort() {
+	r.mutex.Lock()
+	r.done = true
+	_ = r.webSocket.Close()
+	r.mutex.Unlock()
+	r.EndAll()
+}
+
+//
+// A watch has ended.
+// The socket is closed when all watches have ended.
+func (r *MultiWatchWriter) Ended(kind string) {
+	r.send(kind, libweb.Event{Action: libmodel.End})
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.remaining--
+	if r.remaining > 0 || r.done {
+		return
+	}
+	r.done = true
+	time.Sleep(50 * time.Millisecond)
+	_ = r.webSocket.Close()
+}
+
+//
+// Write event to the socket.
+// Serialized because the watches deliver
+// events on separate goroutines.
+func (r *MultiWatchWriter) send(kind string, event libweb.Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.done {
+		return
+	}
+	err := r.webSocket.WriteJSON(
+		WatchEvent{
+			Kind:  kind,
+			Event: event,
+		})
+	if err != nil {
+		r.log.V(4).Error(err, "websocket send failed.")
+	}
+}
+
+//
+// Writer is done.
+func (r *MultiWatchWriter) isDone() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.done
+}
+
+//
+// Event handler for a collection (multiplexed watch).
+type multiWatchHandler struct {
+	// Shared writer.
+	writer *MultiWatchWriter
+	// Collection.
+	kind string
+	// Resource.
+	builder libweb.ResourceBuilder
+	// Watch options.
+	options libmodel.WatchOptions
+}
+
+//
+// Watch options.
+func (r *multiWatchHandler) Options() libmodel.WatchOptions {
+	return r.options
+}
+
+//
+// Watch has started.
+func (r *multiWatchHandler) Started(watchID uint64) {
+	r.writer.send(
+		r.kind,
+		libweb.Event{
+			ID:     watchID,
+			Action: libmodel.Started,
+		})
+}
+
+//
+// Watch has parity.
+func (r *multiWatchHandler) Parity() {
+	r.writer.send(r.kind, libweb.Event{Action: libmodel.Parity})
+}
+
+//
+// A model has been created.
+func (r *multiWatchHandler) Created(event libmodel.Event) {
+	r.forward(event)
+}
+
+//
+// A model has been updated.
+func (r *multiWatchHandler) Updated(event libmodel.Event) {
+	r.forward(event)
+}
+
+//
+// A model has been deleted.
+func (r *multiWatchHandler) Deleted(event libmodel.Event) {
+	r.forward(event)
+}
+
+//
+// An error has occurred delivering an event.
+func (r *multiWatchHandler) Error(err error) {
+	r.writer.log.V(3).Info(
+		"event: error",
+		"kind",
+		r.kind,
+		"error",
+		err.Error())
+	r.writer.send(r.kind, libweb.Event{Action: libmodel.Error})
+}
+
+//
+// The watch has ended.
+func (r *multiWatchHandler) End() {
+	r.writer.Ended(r.kind)
+}
+
+//
+// Build the resource(s) and send the event.
+func (r *multiWatchHandler) forward(e libmodel.Event) {
+	event := libweb.Event{
+		ID:     e.ID,
+		Action: e.Action,
+	}
+	if e.Model != nil {
+		event.Resource = r.builder(e.Model)
+	}
+	if e.Updated != nil {
+		event.Updated = r.builder(e.Updated)
+	}
+	r.writer.send(r.kind, event)
+}
@@ -0,0 +1,75 @@
+package base
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	RefreshRoot = "/refresh"
+)
+
+//
+// Collector which supports an (on-demand) refresh.
+type Refresher interface {
+	// Request an immediate refresh.
+	// Returns the revision of the requested refresh.
+	Refresh() int64
+	// The revision of the last completed refresh.
+	Refreshed() int64
+}
+
+//
+// Refresh (REST) resource.
+type Refresh struct {
+	// Requested revision.
+	Revision int64 `json:"revision"`
+	// Refreshed (completed) revision.
+	Refreshed int64 `json:"refreshed"`
+}
+
+//
+// Request the provider collector to refresh the inventory.
+// Safe to be called repeatedly. Returns 202 and the revision
+// which has been requested. Clients may poll (GET) for the
+// refreshed revision.
+func (h Handler) Refresh(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	refresher, cast := h.Collector.(Refresher)
+	if !cast {
+		ctx.Status(http.StatusNotImplemented)
+		return
+	}
+	r := Refresh{
+		Revision:  refresher.Refresh(),
+		Refreshed: refresher.Refreshed(),
+	}
+
+	ctx.JSON(http.StatusAccepted, r)
+}
+
+//
+// Get the refreshed revision.
+func (h Handler) Refreshed(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	refresher, cast := h.Collector.(Refresher)
+	if !cast {
+		ctx.Status(http.StatusNotImplemented)
+		return
+	}
+	r := Refresh{
+		Refreshed: refresher.Refreshed(),
+	}
+
+	ctx.JSON(http.StatusOK, r)
+}
@@ -0,0 +1,103 @@
+package base
+
+import (
+	"github.com/gin-gonic/gin"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+//
+// Since parameters.
+const (
+	SinceParam = "since"
+)
+
+//
+// Header.
+const (
+	RevisionHeader = "X-Revision"
+)
+
+//
+// Revision (since) filter.
+// Lists only models updated after the revision.
+// The revision column is indexed.
+type Since struct {
+	// The revision observed by the client.
+	Revision int64
+	// The filter has been set.
+	Set bool
+}
+
+//
+// Prepare the filter using the passed parameters.
+func (r *Since) Prepare(ctx *gin.Context) int {
+	r.Revision = 0
+	r.Set = false
+	q := ctx.Request.URL.Query()
+	pSince := q.Get(SinceParam)
+	if len(pSince) == 0 {
+		return http.StatusOK
+	}
+	n, err := strconv.ParseInt(pSince, 10, 64)
+	if err != nil || n < 0 {
+		return http.StatusBadRequest
+	}
+	r.Revision = n
+	r.Set = true
+
+	return http.StatusOK
+}
+
+//
+// Build the list predicate.
+// The revision predicate is combined with the passed predicate.
+func (r *Since) Predicate(p libmodel.Predicate) libmodel.Predicate {
+	if !r.Set {
+		return p
+	}
+	since := libmodel.Gt("Revision", r.Revision)
+	if p == nil {
+		return since
+	}
+
+	return libmodel.And(p, since)
+}
+
+//
+// Set the X-Revision header to the current (max) revision
+// of the collection so the client may advance the cursor.
+// The `list` must be a pointer to a slice of models.
+// Only models updated after the revision are fetched; the
+// header is the passed revision when nothing has changed.
+func (r *Since) Header(ctx *gin.Context, db libmodel.DB, list interface{}) (err error) {
+	if !r.Set {
+		return
+	}
+	lt := reflect.TypeOf(list)
+	if lt.Kind() != reflect.Ptr || lt.Elem().Kind() != reflect.Slice {
+		return
+	}
+	changed := reflect.New(lt.Elem())
+	err = db.List(
+		changed.Interface(),
+		libmodel.ListOptions{
+			Predicate: libmodel.Gt("Revision", r.Revision),
+		})
+	if err != nil {
+		return
+	}
+	revision := r.Revision
+	items := changed.Elem()
+	for i := 0; i < items.Len(); i++ {
+		fv := reflect.Indirect(items.Index(i)).FieldByName("Revision")
+		if fv.IsValid() && fv.Kind() == reflect.Int64 && fv.Int() > revision {
+			revision = fv.Int()
+		}
+	}
+	ctx.Header(RevisionHeader, strconv.FormatInt(revision, 10))
+
+	return
+}
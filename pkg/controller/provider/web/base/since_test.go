@@ -0,0 +1,39 @@
+package base
+
+import (
+	"github.com/gin-gonic/gin"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	"github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSince(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	prepare := func(url string) (since Since, status int) {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, url, nil)
+		status = since.Prepare(ctx)
+		return
+	}
+	// Not requested.
+	since, status := prepare("/vms")
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(since.Set).To(gomega.BeFalse())
+	name := libmodel.Eq("name", "test")
+	g.Expect(since.Predicate(name)).To(gomega.Equal(name))
+	// Requested.
+	since, status = prepare("/vms?since=10")
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(since.Set).To(gomega.BeTrue())
+	g.Expect(since.Revision).To(gomega.Equal(int64(10)))
+	g.Expect(since.Predicate(nil)).To(gomega.Equal(libmodel.Gt("Revision", int64(10))))
+	_, combined := since.Predicate(name).(*libmodel.AndPredicate)
+	g.Expect(combined).To(gomega.BeTrue())
+	// Not valid.
+	_, status = prepare("/vms?since=-1")
+	g.Expect(status).To(gomega.Equal(http.StatusBadRequest))
+	_, status = prepare("/vms?since=x")
+	g.Expect(status).To(gomega.Equal(http.StatusBadRequest))
+}
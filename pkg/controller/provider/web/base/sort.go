@@ -0,0 +1,127 @@
+package base
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+//
+// Sort parameters.
+const (
+	SortParam  = "sort"
+	OrderParam = "order"
+)
+
+//
+// Sort order.
+const (
+	Asc  = "asc"
+	Desc = "desc"
+)
+
+//
+// Sort field not valid.
+type SortNotValidError struct {
+	Field string
+}
+
+func (r SortNotValidError) Error() string {
+	return fmt.Sprintf("Sort `%s` not valid.", r.Field)
+}
+
+//
+// Collection sort.
+// The sort field must be an indexed column.
+type Sort struct {
+	// Field (column) name.
+	Field string
+	// Descending order.
+	Desc bool
+	// Page applied after the (descending) list is reversed.
+	page *libmodel.Page
+}
+
+//
+// Prepare the sort using the passed parameters.
+func (r *Sort) Prepare(ctx *gin.Context) int {
+	r.Field = ""
+	r.Desc = false
+	r.page = nil
+	q := ctx.Request.URL.Query()
+	r.Field = strings.TrimSpace(q.Get(SortParam))
+	switch strings.ToLower(q.Get(OrderParam)) {
+	case "", Asc:
+	case Desc:
+		r.Desc = true
+	default:
+		return http.StatusBadRequest
+	}
+
+	return http.StatusOK
+}
+
+//
+// Build the list options.
+// The `list` must be a pointer to a slice of models.
+// The DB only sorts in ascending order so descending
+// lists are fetched without the page which is applied
+// by Apply() after the list is reversed.
+// Returns: SortNotValidError when the field is not an
+// indexed column of the model.
+func (r *Sort) Build(list interface{}, options *libmodel.ListOptions) (err error) {
+	if r.Field == "" {
+		return
+	}
+	lt := reflect.TypeOf(list)
+	if lt.Kind() != reflect.Ptr || lt.Elem().Kind() != reflect.Slice {
+		err = SortNotValidError{Field: r.Field}
+		return
+	}
+	model := reflect.New(lt.Elem().Elem()).Interface()
+	fields, err := libmodel.Table{}.Fields(model)
+	if err != nil {
+		return
+	}
+	position := 0
+	for _, f := range fields {
+		if !f.MatchDetail(options.Detail) {
+			continue
+		}
+		position++
+		if !strings.EqualFold(f.Name, r.Field) {
+			continue
+		}
+		if f.Pk() || len(f.Index()) > 0 {
+			options.Sort = []int{position}
+			if r.Desc {
+				r.page = options.Page
+				options.Page = nil
+			}
+			return
+		}
+		break
+	}
+
+	err = SortNotValidError{Field: r.Field}
+	return
+}
+
+//
+// Apply the descending order (and page) to the
+// rendered collection.
+func (r *Sort) Apply(content []interface{}) []interface{} {
+	if !r.Desc || r.page == nil {
+		return content
+	}
+	reversed := make([]interface{}, 0, len(content))
+	for i := len(content) - 1; i >= 0; i-- {
+		reversed = append(reversed, content[i])
+	}
+	r.page.Slice(&reversed)
+
+	return reversed
+}
@@ -0,0 +1,44 @@
+package base
+
+import (
+	"errors"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	"github.com/onsi/gomega"
+	"testing"
+)
+
+type testModel struct {
+	ID     string `sql:"pk"`
+	Name   string `sql:"d0,index(name)"`
+	Folder string `sql:"d0,index(folder)"`
+	Notes  string `sql:""`
+}
+
+func TestSort(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	list := []testModel{}
+	// Not requested.
+	sort := Sort{}
+	options := libmodel.ListOptions{}
+	err := sort.Build(&list, &options)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(options.Sort).To(gomega.BeNil())
+	// Indexed.
+	sort = Sort{Field: "folder"}
+	err = sort.Build(&list, &options)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(options.Sort).To(gomega.Equal([]int{3}))
+	// Not indexed.
+	sort = Sort{Field: "notes"}
+	err = sort.Build(&list, &options)
+	g.Expect(errors.As(err, &SortNotValidError{})).To(gomega.BeTrue())
+	// Descending.
+	page := libmodel.Page{Offset: 1, Limit: 2}
+	options = libmodel.ListOptions{Page: &page}
+	sort = Sort{Field: "name", Desc: true}
+	err = sort.Build(&list, &options)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(options.Page).To(gomega.BeNil())
+	content := sort.Apply([]interface{}{1, 2, 3, 4})
+	g.Expect(content).To(gomega.Equal([]interface{}{3, 2}))
+}
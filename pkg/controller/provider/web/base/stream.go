@@ -0,0 +1,107 @@
+package base
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	fb "github.com/konveyor/controller/pkg/filebacked"
+	"net/http"
+)
+
+//
+// NDJSON (streamed) media type.
+const (
+	NDJSONMediaType = "application/x-ndjson"
+)
+
+//
+// Build the resource for a model read from the iterator.
+// Returns kept=false when the model is filtered.
+type StreamBuilder func(itr fb.Iterator, index int) (content interface{}, kept bool, err error)
+
+//
+// The client requested a streamed (NDJSON) collection.
+func (h *Handler) Streaming() bool {
+	return h.Format == NDJSON
+}
+
+//
+// Stream the collection as NDJSON.
+// Each model is read from the (file-backed) iterator, built
+// and written with the requested fields as a separate line so
+// that memory use does not depend on the size of the collection.
+// The descending sort (and page) is applied by iterating in
+// reverse, consistent with Sort.Apply(). The response is started
+// when the first line is ready so that failures are reported with
+// the same status as the JSON and YAML formats. Failures after the
+// response has started are logged and end the stream.
+func (h *Handler) Stream(ctx *gin.Context, itr fb.Iterator, build StreamBuilder) {
+	defer itr.Close()
+	started := false
+	start := func() {
+		if !started {
+			ctx.Header("Content-Type", NDJSONMediaType)
+			ctx.Status(http.StatusOK)
+			started = true
+		}
+	}
+	defer start()
+	encoder := json.NewEncoder(ctx.Writer)
+	flusher, _ := ctx.Writer.(http.Flusher)
+	reversed := h.Sort.Desc && h.Sort.page != nil
+	n := itr.Len()
+	kept := 0
+	for i := 0; i < n; i++ {
+		index := i
+		if reversed {
+			index = n - 1 - i
+		}
+		content, matched, err := build(itr, index)
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			if !started {
+				ctx.Status(http.StatusInternalServerError)
+				started = true
+			}
+			return
+		}
+		if !matched {
+			continue
+		}
+		kept++
+		if reversed {
+			if kept <= h.Sort.page.Offset {
+				continue
+			}
+			if kept > h.Sort.page.Offset+h.Sort.page.Limit {
+				break
+			}
+		}
+		content, err = h.Select(content)
+		if err != nil {
+			log.V(3).Info(
+				err.Error(),
+				"url",
+				ctx.Request.URL)
+			if !started {
+				ctx.Status(http.StatusBadRequest)
+				started = true
+			}
+			return
+		}
+		start()
+		err = encoder.Encode(content)
+		if err != nil {
+			log.V(3).Info(
+				err.Error(),
+				"url",
+				ctx.Request.URL)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package base
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	libweb "github.com/konveyor/controller/pkg/inventory/web"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//
+// Watch options.
+const (
+	// Resume the watch at the specified revision.
+	// Passed in the X-Watch header as: revision=<n>.
+	WatchRevision = "revision"
+)
+
+//
+// Header.
+const (
+	// Returned with 410 (Gone) when the revision cursor
+	// cannot be satisfied and the client must re-list.
+	RelistHeader = "X-Watch-Relist"
+)
+
+//
+// Watch (event) filter.
+// Events for models not matched by the filter are not delivered.
+type WatchFilter func(m libmodel.Model) bool
+
+//
+// Build a filter which matches model fields.
+// The fields is a map of field name to value. Empty
+// values are ignored. Returns nil when there is nothing
+// to match.
+func FieldFilter(fields map[string]string) (filter WatchFilter) {
+	matched := map[string]string{}
+	for name, value := range fields {
+		if len(value) > 0 {
+			matched[name] = value
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+	filter = func(m libmodel.Model) bool {
+		for name, value := range matched {
+			if FieldValue(m, name) != value {
+				return false
+			}
+		}
+		return true
+	}
+
+	return
+}
+
+//
+// Build a filter which matches the (list) predicate.
+// The Eq, Neq, And and Or predicates are matched using the
+// model fields. Other predicates are not matched (the events
+// are delivered). Returns nil when the predicate is nil.
+func PredicateFilter(p libmodel.Predicate) (filter WatchFilter) {
+	if p == nil {
+		return
+	}
+	filter = func(m libmodel.Model) bool {
+		return predicateMatch(m, p)
+	}
+
+	return
+}
+
+//
+// Determine if the model is matched by the predicate.
+func predicateMatch(m libmodel.Model, p libmodel.Predicate) bool {
+	switch p.(type) {
+	case *libmodel.EqPredicate:
+		simple := p.(*libmodel.EqPredicate).SimplePredicate
+		return fieldEqual(m, simple.Field, simple.Value)
+	case *libmodel.NeqPredicate:
+		simple := p.(*libmodel.NeqPredicate).SimplePredicate
+		return !fieldEqual(m, simple.Field, simple.Value)
+	case *libmodel.AndPredicate:
+		for _, p := range p.(*libmodel.AndPredicate).Predicates {
+			if !predicateMatch(m, p) {
+				return false
+			}
+		}
+		return true
+	case *libmodel.OrPredicate:
+		for _, p := range p.(*libmodel.OrPredicate).Predicates {
+			if predicateMatch(m, p) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+//
+// Determine if the model field equals the value.
+// The field name is not case sensitive (consistent with
+// the list predicate).
+func fieldEqual(m libmodel.Model, name string, value interface{}) bool {
+	mv := reflect.Indirect(reflect.ValueOf(m))
+	if mv.Kind() != reflect.Struct {
+		return false
+	}
+	fv := mv.FieldByNameFunc(
+		func(field string) bool {
+			return strings.EqualFold(field, name)
+		})
+	if !fv.IsValid() {
+		return false
+	}
+
+	return fmt.Sprint(fv.Interface()) == fmt.Sprint(value)
+}
+
+//
+// The value of a (string) model field.
+// Fields of embedded structs are included.
+func FieldValue(m libmodel.Model, name string) (value string) {
+	mv := reflect.Indirect(reflect.ValueOf(m))
+	if mv.Kind() != reflect.Struct {
+		return
+	}
+	fv := mv.FieldByName(name)
+	if fv.IsValid() && fv.Kind() == reflect.String {
+		value = fv.String()
+	}
+
+	return
+}
+
+//
+// Revision cursor (watch).
+type Cursor struct {
+	// The revision observed by the client.
+	Revision int64
+	// The cursor has been set.
+	Set bool
+}
+
+//
+// Set the cursor using the X-Watch header options.
+func (c *Cursor) Prepare(ctx *gin.Context) int {
+	c.Revision = 0
+	c.Set = false
+	header := ctx.Request.Header[libweb.WatchHeader]
+	for _, option := range header {
+		for _, part := range strings.Split(option, ",") {
+			kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+			if len(kv) != 2 || kv[0] != WatchRevision {
+				continue
+			}
+			n, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil || n < 0 {
+				return http.StatusBadRequest
+			}
+			c.Revision = n
+			c.Set = true
+		}
+	}
+
+	return http.StatusOK
+}
+
+//
+// The watch can be resumed at the revision without
+// loss. Only (zero) revision is lossless because the
+// client has not observed any models.
+func (c *Cursor) Lossless() bool {
+	return c.Revision == 0
+}
+
+//
+// Watch model.
+// When a revision cursor has been passed, the watch is resumed.
+// The model revision is kept per model (not per collection) and
+// deleted models are not retained so models updated or deleted
+// after a (non-zero) revision cannot be determined. A resume which
+// cannot be lossless is rejected with 410 (Gone) and the client is
+// expected to re-list. A (zero) revision is resumed with the full
+// snapshot. When a filter has been set, events (including the
+// snapshot) for models not matched are not delivered.
+func (h *Handler) Watch(
+	ctx *gin.Context,
+	db libmodel.DB,
+	m libmodel.Model,
+	rb libweb.ResourceBuilder) (err error) {
+	//
+	watchDB := db
+	if h.Cursor.Set {
+		if !h.Cursor.Lossless() {
+			ctx.Header(RelistHeader, "true")
+			ctx.Status(http.StatusGone)
+			return
+		}
+		watchDB = &cursorDB{
+			DB: watchDB,
+		}
+	}
+	if h.WatchFilter != nil {
+		watchDB = &filterDB{
+			DB:     watchDB,
+			filter: h.WatchFilter,
+		}
+	}
+
+	err = h.Watched.Watch(ctx, watchDB, m, rb)
+
+	return
+}
+
+//
+// DB used to resume a watch.
+// The event handler is wrapped so the watch will
+// deliver the full snapshot.
+type cursorDB struct {
+	libmodel.DB
+}
+
+//
+// Watch a model collection.
+func (r *cursorDB) Watch(m libmodel.Model, handler libmodel.EventHandler) (*libmodel.Watch, error) {
+	return r.DB.Watch(
+		m,
+		&cursorHandler{
+			EventHandler: handler,
+		})
+}
+
+//
+// Event handler used to resume a watch.
+type cursorHandler struct {
+	libmodel.EventHandler
+}
+
+//
+// Watch options.
+// The snapshot is always delivered.
+func (r *cursorHandler) Options() libmodel.WatchOptions {
+	options := r.EventHandler.Options()
+	options.Snapshot = true
+	return options
+}
+
+//
+// DB used to filter a watch.
+// The event handler is wrapped so events for
+// models not matched are dropped.
+type filterDB struct {
+	libmodel.DB
+	// Event filter.
+	filter WatchFilter
+}
+
+//
+// Watch a model collection.
+func (r *filterDB) Watch(m libmodel.Model, handler libmodel.EventHandler) (*libmodel.Watch, error) {
+	return r.DB.Watch(
+		m,
+		&filterHandler{
+			EventHandler: handler,
+			filter:       r.filter,
+		})
+}
+
+//
+// Event handler used to filter a watch.
+type filterHandler struct {
+	libmodel.EventHandler
+	// Event filter.
+	filter WatchFilter
+}
+
+//
+// Model created.
+func (r *filterHandler) Created(event libmodel.Event) {
+	if r.filter(event.Model) {
+		r.EventHandler.Created(event)
+	}
+}
+
+//
+// Model updated.
+// Delivered when either the original or the updated
+// model is matched so clients observe models which
+// no longer match.
+func (r *filterHandler) Updated(event libmodel.Event) {
+	if r.filter(event.Model) ||
+		(event.Updated != nil && r.filter(event.Updated)) {
+		r.EventHandler.Updated(event)
+	}
+}
+
+//
+// Model deleted.
+func (r *filterHandler) Deleted(event libmodel.Event) {
+	if r.filter(event.Model) {
+		r.EventHandler.Deleted(event)
+	}
+}
@@ -0,0 +1,149 @@
+package base
+
+import (
+	"github.com/gin-gonic/gin"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	libweb "github.com/konveyor/controller/pkg/inventory/web"
+	"github.com/onsi/gomega"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	request := func(options ...string) *gin.Context {
+		return &gin.Context{
+			Request: &http.Request{
+				Header: map[string][]string{
+					libweb.WatchHeader: options,
+				},
+			},
+		}
+	}
+	cursor := Cursor{}
+	// Not set.
+	status := cursor.Prepare(request(libweb.WatchSnapshot))
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(cursor.Set).To(gomega.BeFalse())
+	// Set.
+	status = cursor.Prepare(request(libweb.WatchSnapshot, "revision=42"))
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(cursor.Set).To(gomega.BeTrue())
+	g.Expect(cursor.Revision).To(gomega.Equal(int64(42)))
+	g.Expect(cursor.Lossless()).To(gomega.BeFalse())
+	// Set (list).
+	status = cursor.Prepare(request("snapshot, revision=7"))
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(cursor.Revision).To(gomega.Equal(int64(7)))
+	// Lossless.
+	status = cursor.Prepare(request("revision=0"))
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(cursor.Set).To(gomega.BeTrue())
+	g.Expect(cursor.Lossless()).To(gomega.BeTrue())
+	// Not valid.
+	status = cursor.Prepare(request("revision=abc"))
+	g.Expect(status).To(gomega.Equal(http.StatusBadRequest))
+	status = cursor.Prepare(request("revision=-1"))
+	g.Expect(status).To(gomega.Equal(http.StatusBadRequest))
+}
+
+type watchedModel struct {
+	ID        string
+	Namespace string
+	Name      string
+}
+
+func (m *watchedModel) Pk() string {
+	return m.ID
+}
+
+type testHandler struct {
+	libmodel.EventHandler
+	delivered []string
+}
+
+func (r *testHandler) Created(event libmodel.Event) {
+	r.delivered = append(r.delivered, "C:"+event.Model.Pk())
+}
+
+func (r *testHandler) Updated(event libmodel.Event) {
+	r.delivered = append(r.delivered, "U:"+event.Model.Pk())
+}
+
+func (r *testHandler) Deleted(event libmodel.Event) {
+	r.delivered = append(r.delivered, "D:"+event.Model.Pk())
+}
+
+func TestWatchFilter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	// Nothing to match.
+	filter := FieldFilter(map[string]string{"Name": ""})
+	g.Expect(filter).To(gomega.BeNil())
+	// Match.
+	filter = FieldFilter(
+		map[string]string{
+			"Namespace": "ns1",
+			"Name":      "vm1",
+		})
+	g.Expect(filter(&watchedModel{ID: "1", Namespace: "ns1", Name: "vm1"})).To(gomega.BeTrue())
+	g.Expect(filter(&watchedModel{ID: "2", Namespace: "ns2", Name: "vm1"})).To(gomega.BeFalse())
+	// Events.
+	recorder := &testHandler{}
+	handler := &filterHandler{
+		EventHandler: recorder,
+		filter:       filter,
+	}
+	matched := &watchedModel{ID: "1", Namespace: "ns1", Name: "vm1"}
+	other := &watchedModel{ID: "2", Namespace: "ns1", Name: "vm2"}
+	handler.Created(libmodel.Event{Model: matched})
+	handler.Created(libmodel.Event{Model: other})
+	handler.Updated(libmodel.Event{Model: other, Updated: other})
+	handler.Updated(libmodel.Event{Model: matched, Updated: other})
+	handler.Deleted(libmodel.Event{Model: other})
+	handler.Deleted(libmodel.Event{Model: matched})
+	g.Expect(recorder.delivered).To(gomega.Equal([]string{"C:1", "U:1", "D:1"}))
+}
+
+func TestPredicateFilter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	// Nothing to match.
+	g.Expect(PredicateFilter(nil)).To(gomega.BeNil())
+	// Match (list predicate).
+	filter := PredicateFilter(
+		libmodel.And(
+			libmodel.Eq("name", "vm1"),
+			libmodel.Or(
+				libmodel.Eq("Namespace", "ns1"),
+				libmodel.Eq("Namespace", "ns2")),
+			libmodel.Neq("ID", "3")))
+	g.Expect(filter(&watchedModel{ID: "1", Namespace: "ns1", Name: "vm1"})).To(gomega.BeTrue())
+	g.Expect(filter(&watchedModel{ID: "2", Namespace: "ns2", Name: "vm1"})).To(gomega.BeTrue())
+	g.Expect(filter(&watchedModel{ID: "3", Namespace: "ns1", Name: "vm1"})).To(gomega.BeFalse())
+	g.Expect(filter(&watchedModel{ID: "4", Namespace: "ns3", Name: "vm1"})).To(gomega.BeFalse())
+	g.Expect(filter(&watchedModel{ID: "5", Namespace: "ns1", Name: "vm2"})).To(gomega.BeFalse())
+	// Field not defined.
+	filter = PredicateFilter(libmodel.Eq("Folder", "f1"))
+	g.Expect(filter(&watchedModel{ID: "1", Namespace: "ns1", Name: "vm1"})).To(gomega.BeFalse())
+}
+
+func TestMultiWatchCollections(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	request := func(query string, options ...string) *gin.Context {
+		return &gin.Context{
+			Request: &http.Request{
+				URL: &url.URL{RawQuery: query},
+				Header: map[string][]string{
+					libweb.WatchHeader: options,
+				},
+			},
+		}
+	}
+	h := Handler{}
+	ctx := request("collections=clusters,hosts&collections=vms,")
+	g.Expect(h.Collections(ctx)).To(gomega.Equal([]string{"clusters", "hosts", "vms"}))
+	g.Expect(h.snapshot(ctx)).To(gomega.BeFalse())
+	ctx = request("", "revision=7, snapshot")
+	g.Expect(h.Collections(ctx)).To(gomega.BeEmpty())
+	g.Expect(h.snapshot(ctx)).To(gomega.BeTrue())
+}
@@ -0,0 +1,196 @@
+package web
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	libcontainer "github.com/konveyor/controller/pkg/inventory/container"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	ovirt "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ovirt"
+	vsphere "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//
+// Routes.
+const (
+	CapacityRoot = PlanRoot + "/capacity"
+)
+
+//
+// The resources the target cluster must provide to
+// migrate the VMs listed on the plan.
+// Computed using the source provider inventory.
+func (h PlanHandler) Capacity(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	p := &api.Plan{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(PlanParam),
+		},
+		p)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	provider := &api.Provider{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: p.Spec.Provider.Source.Namespace,
+			Name:      p.Spec.Provider.Source.Name,
+		},
+		provider)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	collector, found := h.Container.Get(provider)
+	if !found {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	r := PlanCapacity{}
+	r.With(p, provider, collector)
+
+	ctx.JSON(http.StatusOK, r)
+}
+
+//
+// Resources.
+type Capacity struct {
+	// Virtual CPUs.
+	CPU int64 `json:"cpu"`
+	// Memory (MB).
+	MemoryMB int64 `json:"memoryMB"`
+	// Disk (MB).
+	StorageMB int64 `json:"storageMB"`
+}
+
+//
+// Add resources.
+func (r *Capacity) Add(other Capacity) {
+	r.CPU += other.CPU
+	r.MemoryMB += other.MemoryMB
+	r.StorageMB += other.StorageMB
+}
+
+//
+// Plan target resource requirements.
+type PlanCapacity struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Total of the resolved VMs.
+	Total Capacity `json:"total"`
+	// Number of VMs with unsupported (passthrough) devices
+	// which are not included on the target.
+	Caveats int `json:"caveats"`
+	// Number of VMs not resolved in the inventory.
+	Unresolved int `json:"unresolved"`
+	// VM requirements.
+	VMs []VMCapacity `json:"vms"`
+}
+
+//
+// VM target resource requirements.
+type VMCapacity struct {
+	ref.Ref  `json:",inline"`
+	Capacity `json:",inline"`
+	// Unsupported device kinds.
+	Devices []string `json:"devices,omitempty"`
+	// VM not resolved.
+	Error string `json:"error,omitempty"`
+}
+
+//
+// Build the requirements.
+// The storage reflects the disks transferred (excluded
+// and skipped shared disks are not included).
+func (r *PlanCapacity) With(p *api.Plan, provider *api.Provider, collector libcontainer.Collector) {
+	r.Namespace = p.Namespace
+	r.Name = p.Name
+	r.VMs = []VMCapacity{}
+	db := collector.DB()
+	for i := range p.Spec.VMs {
+		vm := &p.Spec.VMs[i]
+		vmCapacity := VMCapacity{Ref: vm.Ref}
+		err := r.build(p, provider, db, vm, &vmCapacity)
+		if err != nil {
+			vmCapacity.Error = err.Error()
+			if errors.Is(err, libmodel.NotFound) {
+				vmCapacity.Error = "VM not found in the inventory."
+			}
+			r.Unresolved++
+		} else {
+			r.Total.Add(vmCapacity.Capacity)
+			if len(vmCapacity.Devices) > 0 {
+				r.Caveats++
+			}
+		}
+		r.VMs = append(r.VMs, vmCapacity)
+	}
+}
+
+//
+// Build the requirements for a VM.
+func (r *PlanCapacity) build(p *api.Plan, provider *api.Provider, db libmodel.DB, planVM *plan.VM, vmCapacity *VMCapacity) (err error) {
+	pipeline := PlanPipeline{}
+	switch provider.Type() {
+	case api.VSphere:
+		vm := &vsphere.VM{}
+		err = pipeline.findVSphere(db, vm, planVM.Ref)
+		if err != nil {
+			return
+		}
+		vmCapacity.CPU = int64(vm.CpuCount)
+		vmCapacity.MemoryMB = int64(vm.MemoryMB)
+		unsupported := map[string]bool{}
+		for _, kind := range settings.Settings.Migration.UnsupportedDevices {
+			unsupported[kind] = true
+		}
+		found := map[string]bool{}
+		for _, device := range vm.Devices {
+			if unsupported[device.Kind] && !found[device.Kind] {
+				found[device.Kind] = true
+				vmCapacity.Devices = append(vmCapacity.Devices, device.Kind)
+			}
+		}
+	case api.OVirt:
+		vm := &ovirt.VM{}
+		err = pipeline.findOVirt(db, vm, planVM.Ref)
+		if err != nil {
+			return
+		}
+		vmCapacity.CPU = int64(vm.CpuSockets) * int64(vm.CpuCores)
+		vmCapacity.MemoryMB = vm.Memory / 0x100000
+		found := map[string]bool{}
+		for _, device := range vm.HostDevices {
+			if !found[device.Capability] {
+				found[device.Capability] = true
+				vmCapacity.Devices = append(vmCapacity.Devices, device.Capability)
+			}
+		}
+	default:
+		return
+	}
+	tasks, err := pipeline.tasks(p, provider, db, planVM)
+	if err != nil {
+		return
+	}
+	for _, task := range tasks {
+		vmCapacity.StorageMB += task.Progress.Total
+	}
+
+	return
+}
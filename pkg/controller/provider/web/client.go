@@ -1,6 +1,7 @@
 package web
 
 import (
+	"errors"
 	"fmt"
 	liberr "github.com/konveyor/controller/pkg/error"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
@@ -164,6 +165,35 @@ func (r *ProviderClient) VM(ref *base.Ref) (object interface{}, err error) {
 	return r.Finder().VM(ref)
 }
 
+//
+// Find VMs by ref.
+// Resolved in batch when supported by the provider finder.
+// Otherwise, each ref is resolved individually.
+// Refs are resolved in place. Refs not found are omitted.
+// Returns the matching resources keyed by (resolved) ref and:
+//   ProviderNotSupportedErr
+//   ProviderNotReadyErr
+//   RefNotUniqueErr
+func (r *ProviderClient) VMs(refs []*base.Ref) (found map[base.Ref]interface{}, err error) {
+	if batch, cast := r.Finder().(base.BatchFinder); cast {
+		return batch.VMs(refs)
+	}
+	found = map[base.Ref]interface{}{}
+	for _, ref := range refs {
+		object, vErr := r.VM(ref)
+		if vErr != nil {
+			if errors.As(vErr, &NotFoundError{}) {
+				continue
+			}
+			err = vErr
+			return
+		}
+		found[*ref] = object
+	}
+
+	return
+}
+
 //
 // Find a workload by ref.
 // Returns the matching resource and:
@@ -216,7 +246,7 @@ func (r *ProviderClient) Host(ref *base.Ref) (object interface{}, err error) {
 func (r *ProviderClient) asError(status int, id string) (err error) {
 	switch status {
 	case http.StatusOK:
-	case http.StatusPartialContent:
+	case http.StatusPartialContent, http.StatusServiceUnavailable:
 		err = liberr.Wrap(
 			ProviderNotReadyError{
 				r.provider,
@@ -0,0 +1,393 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	ovirt "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ovirt"
+	vsphere "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	cnv "kubevirt.io/client-go/api/v1"
+	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"net/http"
+	"path"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//
+// Routes.
+const (
+	CompareRoot = PlanRoot + "/vms/:" + VMParam + "/compare"
+)
+
+//
+// Comparison categories.
+const (
+	// The source and target match.
+	CompareMatch = "Match"
+	// The mismatch is the expected result of the migration.
+	CompareExpected = "Expected"
+	// The mismatch is not expected.
+	CompareUnexpected = "Unexpected"
+)
+
+//
+// Compare the source VM and the (migrated) target VM.
+// The source VM is found in the inventory and the target VM
+// using the (latest) import CR created for the VM. The target
+// VM must be on the host cluster.
+func (h PlanHandler) Compare(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	p := &api.Plan{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(PlanParam),
+		},
+		p)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	vm, found := p.Status.Migration.FindVM(ref.Ref{ID: ctx.Param(VMParam)})
+	if !found {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	vmImport, found, err := h.findImport(p, vm)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	if !found || vmImport.Status.TargetVMName == "" {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	target := &cnv.VirtualMachine{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: vmImport.Namespace,
+			Name:      vmImport.Status.TargetVMName,
+		},
+		target)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	dvList, err := h.dataVolumes(vmImport)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	provider := &api.Provider{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: p.Spec.Provider.Source.Namespace,
+			Name:      p.Spec.Provider.Source.Name,
+		},
+		provider)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	collector, found := h.Container.Get(provider)
+	if !found {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	r := VMComparison{}
+	err = r.With(p, vm, provider, collector.DB(), target, dvList)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, r)
+}
+
+//
+// The DataVolumes reported on the import status.
+// DataVolumes already deleted are ignored.
+func (h PlanHandler) dataVolumes(vmImport *vmio.VirtualMachineImport) (list []cdi.DataVolume, err error) {
+	for _, ref := range vmImport.Status.DataVolumes {
+		dv := cdi.DataVolume{}
+		gErr := h.Client.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: vmImport.Namespace,
+				Name:      ref.Name,
+			},
+			&dv)
+		if gErr != nil {
+			if k8serr.IsNotFound(gErr) {
+				continue
+			}
+			err = liberr.Wrap(gErr)
+			return
+		}
+		list = append(list, dv)
+	}
+
+	return
+}
+
+//
+// Compared VM field.
+type FieldComparison struct {
+	// Field name.
+	Name string `json:"name"`
+	// Source value.
+	Source string `json:"source"`
+	// Target value.
+	Target string `json:"target"`
+	// Category: Match|Expected|Unexpected.
+	Category string `json:"category"`
+	// Reason the mismatch is expected.
+	Reason string `json:"reason,omitempty"`
+}
+
+//
+// Source and target VM comparison.
+type VMComparison struct {
+	ref.Ref `json:",inline"`
+	// Target VM (namespace/name).
+	Target string `json:"target"`
+	// Compared fields.
+	Fields []FieldComparison `json:"fields"`
+	// Number of expected mismatches.
+	Expected int `json:"expected"`
+	// Number of unexpected mismatches.
+	Unexpected int `json:"unexpected"`
+}
+
+//
+// VM properties compared.
+type comparedVM struct {
+	// Virtual CPUs.
+	CPU int64
+	// Memory (MB).
+	MemoryMB int64
+	// Number of disks.
+	Disks int
+	// Disk capacity (MB).
+	StorageMB int64
+	// Number of NICs.
+	NICs int
+	// Firmware.
+	Firmware string
+}
+
+//
+// Build the comparison.
+func (r *VMComparison) With(
+	p *api.Plan,
+	vm *plan.VMStatus,
+	provider *api.Provider,
+	db libmodel.DB,
+	target *cnv.VirtualMachine,
+	dvList []cdi.DataVolume) (err error) {
+	//
+	r.Ref = vm.Ref
+	r.Target = path.Join(target.Namespace, target.Name)
+	r.Fields = []FieldComparison{}
+	source, err := r.source(provider, db, &vm.VM)
+	if err != nil {
+		return
+	}
+	source.Firmware = vm.Firmware
+	pipeline := PlanPipeline{}
+	tasks, err := pipeline.tasks(p, provider, db, &vm.VM)
+	if err != nil {
+		return
+	}
+	transferred := comparedVM{Disks: len(tasks)}
+	for _, task := range tasks {
+		transferred.StorageMB += task.Progress.Total
+	}
+	migrated := r.target(target, dvList)
+	r.add("cpu", source.CPU, migrated.CPU, false, "")
+	r.add("memoryMB", source.MemoryMB, migrated.MemoryMB, false, "")
+	r.add(
+		"disks",
+		source.Disks,
+		migrated.Disks,
+		migrated.Disks == transferred.Disks,
+		"Excluded (or skipped shared) disks are not migrated.")
+	reason := "Target volumes are rounded up."
+	if transferred.Disks != source.Disks {
+		reason = "Excluded (or skipped shared) disks are not migrated."
+	}
+	r.add(
+		"storageMB",
+		source.StorageMB,
+		migrated.StorageMB,
+		migrated.StorageMB >= transferred.StorageMB,
+		reason)
+	reason = "NICs are counted by source network."
+	r.add(
+		"nics",
+		source.NICs,
+		migrated.NICs,
+		provider.Type() == api.VSphere && migrated.NICs > source.NICs,
+		reason)
+	if source.Firmware != "" {
+		r.add(
+			"firmware",
+			source.Firmware,
+			migrated.Firmware,
+			source.Firmware == plan.FirmwareSecureBoot && migrated.Firmware == plan.FirmwareUEFI,
+			"Secure boot cannot be configured on the target VM.")
+	}
+
+	return
+}
+
+//
+// Add a compared field.
+// A mismatch is categorized as expected when indicated.
+func (r *VMComparison) add(name string, source, target interface{}, expected bool, reason string) {
+	field := FieldComparison{
+		Name:   name,
+		Source: fmt.Sprint(source),
+		Target: fmt.Sprint(target),
+	}
+	switch {
+	case field.Source == field.Target:
+		field.Category = CompareMatch
+	case expected:
+		field.Category = CompareExpected
+		field.Reason = reason
+		r.Expected++
+	default:
+		field.Category = CompareUnexpected
+		r.Unexpected++
+	}
+	r.Fields = append(r.Fields, field)
+}
+
+//
+// Build the source VM properties using the inventory.
+// vSphere NICs are counted by (connected) source network.
+func (r *VMComparison) source(provider *api.Provider, db libmodel.DB, planVM *plan.VM) (compared comparedVM, err error) {
+	pipeline := PlanPipeline{}
+	switch provider.Type() {
+	case api.VSphere:
+		vm := &vsphere.VM{}
+		err = pipeline.findVSphere(db, vm, planVM.Ref)
+		if err != nil {
+			return
+		}
+		compared.CPU = int64(vm.CpuCount)
+		compared.MemoryMB = int64(vm.MemoryMB)
+		compared.Disks = len(vm.Disks)
+		for _, disk := range vm.Disks {
+			compared.StorageMB += disk.Capacity / 0x100000
+		}
+		compared.NICs = len(vm.Networks)
+	case api.OVirt:
+		vm := &ovirt.VM{}
+		err = pipeline.findOVirt(db, vm, planVM.Ref)
+		if err != nil {
+			return
+		}
+		compared.CPU = int64(vm.CpuSockets) * int64(vm.CpuCores)
+		compared.MemoryMB = vm.Memory / 0x100000
+		compared.Disks = len(vm.DiskAttachments)
+		for _, da := range vm.DiskAttachments {
+			disk := &ovirt.Disk{
+				Base: ovirt.Base{ID: da.Disk},
+			}
+			err = db.Get(disk)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			compared.StorageMB += disk.ProvisionedSize / 0x100000
+		}
+		compared.NICs = len(vm.NICs)
+	}
+
+	return
+}
+
+//
+// Build the target VM properties.
+// The disks are the volumes backed by DataVolumes.
+func (r *VMComparison) target(vm *cnv.VirtualMachine, dvList []cdi.DataVolume) (compared comparedVM) {
+	if vm.Spec.Template == nil {
+		return
+	}
+	spec := &vm.Spec.Template.Spec
+	domain := &spec.Domain
+	compared.CPU = 1
+	if cpu := domain.CPU; cpu != nil {
+		compared.CPU = int64(topology(cpu.Sockets)) * int64(topology(cpu.Cores)) * int64(topology(cpu.Threads))
+	}
+	if domain.Memory != nil && domain.Memory.Guest != nil {
+		compared.MemoryMB = domain.Memory.Guest.Value() / 0x100000
+	} else if memory, found := domain.Resources.Requests[core.ResourceMemory]; found {
+		compared.MemoryMB = memory.Value() / 0x100000
+	}
+	for _, volume := range spec.Volumes {
+		if volume.DataVolume != nil {
+			compared.Disks++
+		}
+	}
+	for _, dv := range dvList {
+		if dv.Spec.PVC == nil {
+			continue
+		}
+		if size, found := dv.Spec.PVC.Resources.Requests[core.ResourceStorage]; found {
+			compared.StorageMB += size.Value() / 0x100000
+		}
+	}
+	compared.NICs = len(domain.Devices.Interfaces)
+	compared.Firmware = plan.FirmwareBIOS
+	if domain.Firmware != nil && domain.Firmware.Bootloader != nil {
+		if efi := domain.Firmware.Bootloader.EFI; efi != nil {
+			compared.Firmware = plan.FirmwareUEFI
+			if efi.SecureBoot != nil && *efi.SecureBoot {
+				compared.Firmware = plan.FirmwareSecureBoot
+			}
+		}
+	}
+
+	return
+}
+
+//
+// Topology value (not set is 1).
+func topology(n uint32) uint32 {
+	if n == 0 {
+		return 1
+	}
+
+	return n
+}
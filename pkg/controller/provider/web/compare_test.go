@@ -0,0 +1,93 @@
+package web
+
+import (
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	cnv "kubevirt.io/client-go/api/v1"
+	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"testing"
+)
+
+func TestCompareTarget(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	guest := resource.MustParse("4Gi")
+	vm := &cnv.VirtualMachine{
+		Spec: cnv.VirtualMachineSpec{
+			Template: &cnv.VirtualMachineInstanceTemplateSpec{
+				Spec: cnv.VirtualMachineInstanceSpec{
+					Domain: cnv.DomainSpec{
+						CPU: &cnv.CPU{
+							Sockets: 2,
+							Cores:   2,
+						},
+						Memory: &cnv.Memory{
+							Guest: &guest,
+						},
+						Firmware: &cnv.Firmware{
+							Bootloader: &cnv.Bootloader{
+								EFI: &cnv.EFI{},
+							},
+						},
+						Devices: cnv.Devices{
+							Interfaces: []cnv.Interface{{Name: "nic0"}},
+						},
+					},
+					Volumes: []cnv.Volume{
+						{
+							Name: "disk0",
+							VolumeSource: cnv.VolumeSource{
+								DataVolume: &cnv.DataVolumeSource{Name: "dv0"},
+							},
+						},
+						{
+							Name: "cloudinit",
+							VolumeSource: cnv.VolumeSource{
+								CloudInitNoCloud: &cnv.CloudInitNoCloudSource{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	dvList := []cdi.DataVolume{
+		{
+			Spec: cdi.DataVolumeSpec{
+				PVC: &core.PersistentVolumeClaimSpec{
+					Resources: core.ResourceRequirements{
+						Requests: core.ResourceList{
+							core.ResourceStorage: resource.MustParse("10Gi"),
+						},
+					},
+				},
+			},
+		},
+	}
+	r := VMComparison{}
+	compared := r.target(vm, dvList)
+	g.Expect(compared.CPU).To(gomega.Equal(int64(4)))
+	g.Expect(compared.MemoryMB).To(gomega.Equal(int64(4096)))
+	g.Expect(compared.Disks).To(gomega.Equal(1))
+	g.Expect(compared.StorageMB).To(gomega.Equal(int64(10240)))
+	g.Expect(compared.NICs).To(gomega.Equal(1))
+	g.Expect(compared.Firmware).To(gomega.Equal(plan.FirmwareUEFI))
+}
+
+func TestCompareCategory(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	r := VMComparison{}
+	r.add("cpu", 4, 4, false, "")
+	r.add("disks", 3, 2, true, "Excluded.")
+	r.add("memoryMB", 4096, 2048, false, "")
+	g.Expect(r.Fields).To(gomega.HaveLen(3))
+	g.Expect(r.Fields[0].Category).To(gomega.Equal(CompareMatch))
+	g.Expect(r.Fields[1].Category).To(gomega.Equal(CompareExpected))
+	g.Expect(r.Fields[1].Reason).To(gomega.Equal("Excluded."))
+	g.Expect(r.Fields[2].Category).To(gomega.Equal(CompareUnexpected))
+	g.Expect(r.Expected).To(gomega.Equal(1))
+	g.Expect(r.Unexpected).To(gomega.Equal(1))
+}
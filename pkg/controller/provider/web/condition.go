@@ -0,0 +1,121 @@
+package web
+
+import (
+	libcnd "github.com/konveyor/controller/pkg/condition"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sort"
+)
+
+//
+// Condition sources.
+const (
+	PlanSource     = "plan"
+	SnapshotSource = "snapshot"
+	VMSource       = "vm"
+)
+
+//
+// Condition categories in (severity) order.
+var CategoryOrder = []string{
+	libcnd.Critical,
+	libcnd.Error,
+	libcnd.Warn,
+	libcnd.Required,
+	libcnd.Advisory,
+}
+
+//
+// Plan conditions (diagnostics).
+// The active conditions of the plan, the active snapshot
+// and each VM as a flat list.
+type PlanConditions struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Active conditions.
+	// Blocking (critical) and error conditions first.
+	Conditions []PlanCondition `json:"conditions"`
+}
+
+//
+// Plan condition.
+type PlanCondition struct {
+	// Set on: plan|snapshot|vm.
+	Source string `json:"source"`
+	// The VM (when set on a VM).
+	VM *ref.Ref `json:"vm,omitempty"`
+	// Condition type.
+	Type string `json:"type"`
+	// Condition category.
+	Category string `json:"category"`
+	// Condition reason.
+	Reason string `json:"reason,omitempty"`
+	// Condition message.
+	Message string `json:"message,omitempty"`
+	// Condition items.
+	Items []string `json:"items,omitempty"`
+	// Last transition.
+	LastTransitionTime meta.Time `json:"lastTransitionTime"`
+}
+
+//
+// Build the list of conditions.
+// Derived from the plan status (read-only).
+func (r *PlanConditions) With(p *api.Plan) {
+	r.Namespace = p.Namespace
+	r.Name = p.Name
+	r.Conditions = []PlanCondition{}
+	r.add(PlanSource, nil, &p.Status.Conditions)
+	migration := p.Status.Migration
+	if len(migration.History) > 0 {
+		snapshot := migration.ActiveSnapshot()
+		r.add(SnapshotSource, nil, &snapshot.Conditions)
+	}
+	for _, vm := range migration.VMs {
+		vmRef := vm.Ref
+		r.add(VMSource, &vmRef, &vm.Conditions)
+	}
+	rank := map[string]int{}
+	for i, category := range CategoryOrder {
+		rank[category] = i
+	}
+	sort.SliceStable(
+		r.Conditions,
+		func(i, j int) bool {
+			return r.rank(rank, r.Conditions[i]) < r.rank(rank, r.Conditions[j])
+		})
+}
+
+//
+// Add the active conditions.
+func (r *PlanConditions) add(source string, vmRef *ref.Ref, conditions *libcnd.Conditions) {
+	for _, cnd := range conditions.List {
+		if cnd.Status != libcnd.True {
+			continue
+		}
+		r.Conditions = append(
+			r.Conditions,
+			PlanCondition{
+				Source:             source,
+				VM:                 vmRef,
+				Type:               cnd.Type,
+				Category:           cnd.Category,
+				Reason:             cnd.Reason,
+				Message:            cnd.Message,
+				Items:              cnd.Items,
+				LastTransitionTime: cnd.LastTransitionTime,
+			})
+	}
+}
+
+//
+// The (severity) rank of a condition.
+// Unknown categories are ranked last.
+func (r *PlanConditions) rank(rank map[string]int, cnd PlanCondition) int {
+	if n, found := rank[cnd.Category]; found {
+		return n
+	}
+
+	return len(rank)
+}
@@ -7,11 +7,12 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 //
 // All handlers.
-func All(container *container.Container) (all []libweb.RequestHandler) {
+func All(container *container.Container, client client.Client) (all []libweb.RequestHandler) {
 	all = []libweb.RequestHandler{
 		&libweb.SchemaHandler{},
 		&ProviderHandler{
@@ -19,6 +20,18 @@ func All(container *container.Container) (all []libweb.RequestHandler) {
 				Container: container,
 			},
 		},
+		&PlanHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Client: client,
+		},
+		&OrphanHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Client: client,
+		},
 	}
 	all = append(
 		all,
@@ -0,0 +1,144 @@
+package web
+
+import (
+	"fmt"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"io"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+//
+// Annotations not exported.
+var ExportSkippedAnnotations = []string{
+	core.LastAppliedConfigAnnotation,
+}
+
+//
+// Plan export.
+// The plan and referenced mappings as (apply-able) manifests.
+type PlanExport struct {
+	Namespace string
+	Name      string
+	// Manifests (plan last).
+	Manifests []Manifest
+}
+
+//
+// Kubernetes manifest.
+// Status and generated metadata are not included.
+type Manifest struct {
+	meta.TypeMeta `json:",inline"`
+	Metadata      Metadata    `json:"metadata"`
+	Spec          interface{} `json:"spec"`
+}
+
+//
+// Manifest metadata.
+type Metadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+//
+// Build the metadata using the object metadata.
+func (r *Metadata) With(m *meta.ObjectMeta) {
+	r.Name = m.Name
+	r.Namespace = m.Namespace
+	r.Labels = m.Labels
+	for k, v := range m.Annotations {
+		skipped := false
+		for _, name := range ExportSkippedAnnotations {
+			if k == name {
+				skipped = true
+				break
+			}
+		}
+		if skipped {
+			continue
+		}
+		if r.Annotations == nil {
+			r.Annotations = map[string]string{}
+		}
+		r.Annotations[k] = v
+	}
+}
+
+//
+// The export file name.
+func (r *PlanExport) FileName() string {
+	return fmt.Sprintf("%s-%s.yaml", r.Namespace, r.Name)
+}
+
+//
+// Build the export.
+// The mappings precede the plan so the manifests may be
+// applied in order. The (cluster specific) UID and resource
+// version of the map references are not exported.
+func (r *PlanExport) With(p *api.Plan, network *api.NetworkMap, storage *api.StorageMap) {
+	r.Namespace = p.Namespace
+	r.Name = p.Name
+	r.Manifests = []Manifest{}
+	gv := api.SchemeGroupVersion
+	networkManifest := Manifest{
+		TypeMeta: meta.TypeMeta{
+			APIVersion: gv.String(),
+			Kind:       "NetworkMap",
+		},
+		Spec: network.Spec,
+	}
+	networkManifest.Metadata.With(&network.ObjectMeta)
+	storageManifest := Manifest{
+		TypeMeta: meta.TypeMeta{
+			APIVersion: gv.String(),
+			Kind:       "StorageMap",
+		},
+		Spec: storage.Spec,
+	}
+	storageManifest.Metadata.With(&storage.ObjectMeta)
+	spec := p.Spec.DeepCopy()
+	for _, ref := range []*core.ObjectReference{&spec.Map.Network, &spec.Map.Storage} {
+		ref.UID = ""
+		ref.ResourceVersion = ""
+	}
+	planManifest := Manifest{
+		TypeMeta: meta.TypeMeta{
+			APIVersion: gv.String(),
+			Kind:       "Plan",
+		},
+		Spec: spec,
+	}
+	planManifest.Metadata.With(&p.ObjectMeta)
+	r.Manifests = append(
+		r.Manifests,
+		networkManifest,
+		storageManifest,
+		planManifest)
+}
+
+//
+// Write the manifests as (multi-document) YAML.
+func (r *PlanExport) Write(w io.Writer) (err error) {
+	for i, manifest := range r.Manifests {
+		if i > 0 {
+			_, err = io.WriteString(w, "---\n")
+			if err != nil {
+				return
+			}
+		}
+		var b []byte
+		b, err = yaml.Marshal(manifest)
+		if err != nil {
+			return
+		}
+		_, err = w.Write(b)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
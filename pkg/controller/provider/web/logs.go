@@ -0,0 +1,308 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	liberr "github.com/konveyor/controller/pkg/error"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"io"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"strconv"
+)
+
+//
+// Routes.
+const (
+	VMParam  = "vm"
+	LogsRoot = PlanRoot + "/vms/:" + VMParam + "/logs"
+)
+
+//
+// Log parameters.
+const (
+	PodParam    = "pod"
+	TailParam   = "tail"
+	FollowParam = "follow"
+)
+
+//
+// Labels and annotations used to find the import pods.
+const (
+	// Import CR labels (plan controller).
+	ImportPlanLabel      = "plan"
+	ImportMigrationLabel = "migration"
+	ImportVMLabel        = "vmID"
+	// VMIO conversion (job) pod label.
+	ConversionPodLabel = "vmimport.v2v.kubevirt.io/vmi-name"
+	// CDI importer pod PVC annotation.
+	ImporterPodAnnotation = "cdi.kubevirt.io/storage.import.importPodName"
+)
+
+//
+// The importer and conversion pod logs for a VM.
+// The pods are found using the (latest) import CR created for
+// the VM. Logs for all pods are returned unless the `pod`
+// parameter is specified. Supports the `tail` (lines) and
+// `follow` parameters. Follow (streamed) requires a single pod.
+func (h PlanHandler) Logs(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	options, status := h.logOptions(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	p := &api.Plan{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(PlanParam),
+		},
+		p)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	vm, found := p.Status.Migration.FindVM(ref.Ref{ID: ctx.Param(VMParam)})
+	if !found {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	vmImport, found, err := h.findImport(p, vm)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	clientset, err := h.clientset()
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	pods, err := h.importPods(clientset, vmImport)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	podName := ctx.Request.URL.Query().Get(PodParam)
+	if podName != "" {
+		selected := []string{}
+		for _, name := range pods {
+			if name == podName {
+				selected = append(selected, name)
+			}
+		}
+		pods = selected
+	}
+	if len(pods) == 0 {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if options.Follow && len(pods) > 1 {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	ctx.Header("Content-Type", "text/plain")
+	ctx.Status(http.StatusOK)
+	for _, name := range pods {
+		if len(pods) > 1 {
+			_, err = io.WriteString(ctx.Writer, fmt.Sprintf("==> %s <==\n", name))
+			if err != nil {
+				return
+			}
+		}
+		err = h.writeLog(ctx, clientset, vmImport.Namespace, name, options)
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			return
+		}
+	}
+}
+
+//
+// Build the pod log options using the passed parameters.
+func (h PlanHandler) logOptions(ctx *gin.Context) (options *core.PodLogOptions, status int) {
+	status = http.StatusOK
+	options = &core.PodLogOptions{}
+	q := ctx.Request.URL.Query()
+	if s := q.Get(TailParam); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < 0 {
+			status = http.StatusBadRequest
+			return
+		}
+		options.TailLines = &n
+	}
+	if s := q.Get(FollowParam); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			status = http.StatusBadRequest
+			return
+		}
+		options.Follow = b
+	}
+
+	return
+}
+
+//
+// Find the latest import CR created for the VM.
+func (h PlanHandler) findImport(p *api.Plan, vm *plan.VMStatus) (vmImport *vmio.VirtualMachineImport, found bool, err error) {
+	list := &vmio.VirtualMachineImportList{}
+	err = h.Client.List(
+		context.TODO(),
+		list,
+		client.MatchingLabels{
+			ImportPlanLabel: string(p.UID),
+			ImportVMLabel:   vm.ID,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		item := &list.Items[i]
+		if vmImport == nil || vmImport.CreationTimestamp.Before(&item.CreationTimestamp) {
+			vmImport = item
+			found = true
+		}
+	}
+
+	return
+}
+
+//
+// The names of the conversion (job) and importer pods
+// created for the import.
+func (h PlanHandler) importPods(clientset kubernetes.Interface, vmImport *vmio.VirtualMachineImport) (pods []string, err error) {
+	for _, dv := range vmImport.Status.DataVolumes {
+		pvc, gErr := clientset.CoreV1().PersistentVolumeClaims(vmImport.Namespace).Get(
+			context.TODO(),
+			dv.Name,
+			meta.GetOptions{})
+		if gErr != nil {
+			if k8serr.IsNotFound(gErr) {
+				continue
+			}
+			err = liberr.Wrap(gErr)
+			return
+		}
+		if name, found := pvc.Annotations[ImporterPodAnnotation]; found {
+			pods = append(pods, name)
+		}
+	}
+	list, err := clientset.CoreV1().Pods(vmImport.Namespace).List(
+		context.TODO(),
+		meta.ListOptions{
+			LabelSelector: ConversionPodLabel + "=" + h.labelValue(vmImport.Name),
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, pod := range list.Items {
+		pods = append(pods, pod.Name)
+	}
+
+	return
+}
+
+//
+// Write the pod log.
+// Pods which no longer exist are skipped.
+func (h PlanHandler) writeLog(ctx *gin.Context, clientset kubernetes.Interface, namespace, name string, options *core.PodLogOptions) (err error) {
+	stream, err := clientset.CoreV1().Pods(namespace).GetLogs(name, options).Stream(ctx.Request.Context())
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	defer stream.Close()
+	b := make([]byte, 4096)
+	for {
+		n, rErr := stream.Read(b)
+		if n > 0 {
+			_, err = ctx.Writer.Write(b[:n])
+			if err != nil {
+				return
+			}
+			ctx.Writer.Flush()
+		}
+		if rErr != nil {
+			if rErr != io.EOF {
+				err = liberr.Wrap(rErr)
+			}
+			break
+		}
+	}
+
+	return
+}
+
+//
+// Build a clientset.
+// Pod logs are not supported by the controller-runtime client.
+func (h PlanHandler) clientset() (clientset kubernetes.Interface, err error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	clientset, err = kubernetes.NewForConfig(cfg)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+//
+// Label value (shortened) as set by VMIO.
+func (h PlanHandler) labelValue(value string) string {
+	n := len(value)
+	if n > k8svalidation.LabelValueMaxLength {
+		suffix := strconv.Itoa(n)
+		maxLen := k8svalidation.LabelValueMaxLength - len(suffix) - 1
+		value = fmt.Sprintf("%s-%s", value[:maxLen], suffix)
+	}
+
+	return value
+}
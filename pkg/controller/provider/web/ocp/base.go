@@ -53,6 +53,18 @@ func (h Handler) Predicate(ctx *gin.Context) (p libmodel.Predicate) {
 	return
 }
 
+//
+// Build the watch filter.
+// Consistent with the list predicate.
+func (h Handler) WatchPredicate(ctx *gin.Context) base.WatchFilter {
+	q := ctx.Request.URL.Query()
+	return base.FieldFilter(
+		map[string]string{
+			"Namespace": q.Get(NsParam),
+			"Name":      q.Get(NameParam),
+		})
+}
+
 //
 // Build list options.
 func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
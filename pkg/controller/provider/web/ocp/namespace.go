@@ -49,7 +49,17 @@ func (h NamespaceHandler) List(ctx *gin.Context) {
 	}
 	db := h.Collector.DB()
 	list := []model.Namespace{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	err := h.Sort.Build(&list, &options)
+	if err != nil {
+		log.V(3).Info(
+			err.Error(),
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	err = db.List(&list, options)
 	if err != nil {
 		log.Trace(
 			err,
@@ -66,7 +76,7 @@ func (h NamespaceHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -101,13 +111,14 @@ func (h NamespaceHandler) Get(ctx *gin.Context) {
 	r.Link(h.Provider)
 	content := r.Content(true)
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
 // Watch.
 func (h NamespaceHandler) watch(ctx *gin.Context) {
 	db := h.Collector.DB()
+	h.WatchFilter = h.WatchPredicate(ctx)
 	err := h.Watch(
 		ctx,
 		db,
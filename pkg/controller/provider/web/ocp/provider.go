@@ -49,7 +49,7 @@ func (h ProviderHandler) List(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -81,7 +81,7 @@ func (h ProviderHandler) Get(ctx *gin.Context) {
 	r.Link()
 	content := r.Content(true)
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -50,7 +50,17 @@ func (h VMHandler) List(ctx *gin.Context) {
 	}
 	db := h.Collector.DB()
 	list := []model.VM{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	err := h.Sort.Build(&list, &options)
+	if err != nil {
+		log.V(3).Info(
+			err.Error(),
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	err = db.List(&list, options)
 	if err != nil {
 		log.Trace(
 			err,
@@ -67,7 +77,7 @@ func (h VMHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -102,13 +112,14 @@ func (h VMHandler) Get(ctx *gin.Context) {
 	r.Link(h.Provider)
 	content := r.Content(true)
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
 // Watch.
 func (h VMHandler) watch(ctx *gin.Context) {
 	db := h.Collector.DB()
+	h.WatchFilter = h.WatchPredicate(ctx)
 	err := h.Watch(
 		ctx,
 		db,
@@ -0,0 +1,334 @@
+package web
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	liberr "github.com/konveyor/controller/pkg/error"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	cdi "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	vmio "kubevirt.io/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"net/http"
+	"path"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+//
+// Routes.
+const (
+	OrphanParam = "orphan"
+	OrphansRoot = "/namespaces/:" + base.NsParam + "/orphans"
+	OrphanRoot  = OrphansRoot + "/:" + OrphanParam
+)
+
+//
+// Orphan reasons.
+const (
+	// The plan (UID) does not exist.
+	PlanNotFound = "PlanNotFound"
+	// The VM is no longer listed on the plan.
+	VMNotOnPlan = "VMNotOnPlan"
+	// Created by a migration other than the
+	// (active) migration of the plan.
+	MigrationNotActive = "MigrationNotActive"
+)
+
+//
+// Orphaned import handler.
+// Import CRs (and DataVolumes) created by the plan controller
+// which are not referenced by a current plan. An import is
+// referenced when it would be included in the ImportMap built
+// for the plan: labeled with the plan UID, the UID of the
+// active migration and the ID of a VM listed on the plan.
+type OrphanHandler struct {
+	base.Handler
+	// k8s API client.
+	Client client.Client
+}
+
+//
+// Add routes to the `gin` router.
+func (h *OrphanHandler) AddRoutes(e *gin.Engine) {
+	e.GET(OrphansRoot, h.List)
+	e.GET(OrphansRoot+"/", h.List)
+	e.GET(OrphanRoot, h.Get)
+	e.DELETE(OrphanRoot, h.Delete)
+}
+
+//
+// List the orphaned imports in the namespace.
+func (h OrphanHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	list, err := h.orphans(ctx.Param(base.NsParam))
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, list)
+}
+
+//
+// Get an orphaned import.
+func (h OrphanHandler) Get(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	orphan, found, err := h.find(ctx.Param(base.NsParam), ctx.Param(OrphanParam))
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orphan)
+}
+
+//
+// Delete (cleanup) an orphaned import.
+// The import is confirmed to be orphaned before the DataVolumes
+// reported on the import status and the import CR are deleted.
+func (h OrphanHandler) Delete(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	orphan, found, err := h.find(ctx.Param(base.NsParam), ctx.Param(OrphanParam))
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	err = h.cleanup(orphan)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orphan)
+}
+
+//
+// Find an orphaned import by name.
+func (h OrphanHandler) find(namespace, name string) (orphan *Orphan, found bool, err error) {
+	list, err := h.orphans(namespace)
+	if err != nil {
+		return
+	}
+	for i := range list {
+		if list[i].Name == name {
+			orphan = &list[i]
+			found = true
+			break
+		}
+	}
+
+	return
+}
+
+//
+// Build the list of orphaned imports in the namespace.
+func (h OrphanHandler) orphans(namespace string) (list []Orphan, err error) {
+	list = []Orphan{}
+	importList := &vmio.VirtualMachineImportList{}
+	err = h.Client.List(
+		context.TODO(),
+		importList,
+		client.InNamespace(namespace),
+		client.HasLabels{ImportPlanLabel})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(importList.Items) == 0 {
+		return
+	}
+	planList := &api.PlanList{}
+	err = h.Client.List(context.TODO(), planList)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	plans := map[types.UID]*api.Plan{}
+	for i := range planList.Items {
+		p := &planList.Items[i]
+		plans[p.UID] = p
+	}
+	dvList := &cdi.DataVolumeList{}
+	err = h.Client.List(
+		context.TODO(),
+		dvList,
+		client.InNamespace(namespace))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	dvs := map[string]bool{}
+	for _, dv := range dvList.Items {
+		dvs[dv.Name] = true
+	}
+	now := time.Now()
+	for i := range importList.Items {
+		vmImport := &importList.Items[i]
+		orphan := Orphan{}
+		if !orphan.With(vmImport, plans) {
+			continue
+		}
+		for _, dv := range vmImport.Status.DataVolumes {
+			if dvs[dv.Name] {
+				orphan.DataVolumes = append(orphan.DataVolumes, dv.Name)
+			}
+		}
+		orphan.Age = now.Sub(orphan.Created.Time).Round(time.Second).String()
+		list = append(list, orphan)
+	}
+
+	return
+}
+
+//
+// Delete the DataVolumes and the import CR.
+// Objects already deleted are ignored.
+func (h OrphanHandler) cleanup(orphan *Orphan) (err error) {
+	objects := []k8sObject{}
+	for _, name := range orphan.DataVolumes {
+		objects = append(
+			objects,
+			&cdi.DataVolume{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: orphan.Namespace,
+					Name:      name,
+				},
+			})
+	}
+	objects = append(
+		objects,
+		&vmio.VirtualMachineImport{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: orphan.Namespace,
+				Name:      orphan.Name,
+			},
+		})
+	for _, object := range objects {
+		err = h.Client.Delete(context.TODO(), object)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		log.Info(
+			"Orphan cleanup: deleted.",
+			"object",
+			path.Join(
+				object.GetNamespace(),
+				object.GetName()))
+	}
+
+	return
+}
+
+//
+// k8s object.
+type k8sObject interface {
+	runtime.Object
+	meta.Object
+}
+
+//
+// Orphaned import.
+type Orphan struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Plan UID (label).
+	PlanUID string `json:"planUID"`
+	// Plan (namespace/name) when found.
+	Plan string `json:"plan,omitempty"`
+	// Migration UID (label).
+	Migration string `json:"migration"`
+	// VM ID (label).
+	VM string `json:"vm"`
+	// Reason the import is orphaned.
+	Reason string `json:"reason"`
+	// Import created.
+	Created meta.Time `json:"created"`
+	// Time since created.
+	Age string `json:"age"`
+	// DataVolumes reported on the import status.
+	DataVolumes []string `json:"dataVolumes,omitempty"`
+}
+
+//
+// Build the orphan.
+// Returns false when the import is referenced by a plan.
+func (r *Orphan) With(vmImport *vmio.VirtualMachineImport, plans map[types.UID]*api.Plan) (orphaned bool) {
+	r.Namespace = vmImport.Namespace
+	r.Name = vmImport.Name
+	r.PlanUID = vmImport.Labels[ImportPlanLabel]
+	r.Migration = vmImport.Labels[ImportMigrationLabel]
+	r.VM = vmImport.Labels[ImportVMLabel]
+	r.Created = vmImport.CreationTimestamp
+	p, found := plans[types.UID(r.PlanUID)]
+	if !found {
+		r.Reason = PlanNotFound
+		orphaned = true
+		return
+	}
+	r.Plan = path.Join(p.Namespace, p.Name)
+	listed := false
+	for _, vm := range p.Spec.VMs {
+		if vm.ID == r.VM {
+			listed = true
+			break
+		}
+	}
+	if !listed {
+		r.Reason = VMNotOnPlan
+		orphaned = true
+		return
+	}
+	snapshot := p.Status.Migration.ActiveSnapshot()
+	if string(snapshot.Migration.UID) != r.Migration {
+		r.Reason = MigrationNotActive
+		orphaned = true
+		return
+	}
+
+	return
+}
@@ -39,6 +39,23 @@ func (h Handler) Predicate(ctx *gin.Context) (p libmodel.Predicate) {
 	return
 }
 
+//
+// Build the watch filter.
+// Consistent with the list predicate.
+func (h Handler) WatchPredicate(ctx *gin.Context) base.WatchFilter {
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) > 0 {
+		path := strings.Split(name, "/")
+		name = path[len(path)-1]
+	}
+
+	return base.FieldFilter(
+		map[string]string{
+			"Name": name,
+		})
+}
+
 //
 // Build list options.
 func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
@@ -47,7 +64,7 @@ func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
 		detail = 1
 	}
 	return libmodel.ListOptions{
-		Predicate: h.Predicate(ctx),
+		Predicate: h.Since.Predicate(h.Predicate(ctx)),
 		Detail:    detail,
 		Page:      &h.Page,
 	}
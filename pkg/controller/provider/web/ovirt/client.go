@@ -1,6 +1,7 @@
 package ovirt
 
 import (
+	"errors"
 	liberr "github.com/konveyor/controller/pkg/error"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
@@ -250,6 +251,71 @@ func (r *Finder) VM(ref *base.Ref) (object interface{}, err error) {
 	return
 }
 
+//
+// Find VMs by ref.
+// Resolved using a single (detailed) list of the VMs.
+// Refs are resolved in place. Refs by qualified (path) name
+// are resolved individually. Refs not found are omitted.
+// Returns the matching resources keyed by (resolved) ref and:
+//   ProviderNotSupportedErr
+//   ProviderNotReadyErr
+//   RefNotUniqueErr
+func (r *Finder) VMs(refs []*base.Ref) (found map[base.Ref]interface{}, err error) {
+	list := []VM{}
+	err = r.List(
+		&list,
+		base.Param{
+			Key:   DetailParam,
+			Value: "1",
+		})
+	if err != nil {
+		return
+	}
+	byID := map[string]*VM{}
+	byName := map[string][]*VM{}
+	for i := range list {
+		vm := &list[i]
+		byID[vm.ID] = vm
+		byName[vm.Name] = append(byName[vm.Name], vm)
+	}
+	found = map[base.Ref]interface{}{}
+	for _, ref := range refs {
+		var vm *VM
+		switch {
+		case ref.ID != "":
+			vm = byID[ref.ID]
+		case strings.Contains(ref.Name, "/"):
+			object, vErr := r.VM(ref)
+			if vErr != nil {
+				if errors.As(vErr, &NotFoundError{}) {
+					continue
+				}
+				err = vErr
+				return
+			}
+			found[*ref] = object
+			continue
+		default:
+			matched := byName[ref.Name]
+			if len(matched) > 1 {
+				err = liberr.Wrap(RefNotUniqueError{Ref: *ref})
+				return
+			}
+			if len(matched) == 1 {
+				vm = matched[0]
+			}
+		}
+		if vm == nil {
+			continue
+		}
+		ref.ID = vm.ID
+		ref.Name = vm.Name
+		found[*ref] = vm
+	}
+
+	return
+}
+
 //
 // Find workload by ref.
 // Returns the matching resource and:
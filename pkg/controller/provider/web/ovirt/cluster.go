@@ -52,7 +52,26 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 	}
 	db := h.Collector.DB()
 	list := []model.Cluster{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	err := h.Sort.Build(&list, &options)
+	if err != nil {
+		log.V(3).Info(
+			err.Error(),
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	err = db.List(&list, options)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	err = h.Since.Header(ctx, db, &list)
 	if err != nil {
 		log.Trace(
 			err,
@@ -69,7 +88,7 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -104,13 +123,14 @@ func (h ClusterHandler) Get(ctx *gin.Context) {
 	r.Link(h.Provider)
 	content := r.Content(true)
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
 // Watch.
 func (h ClusterHandler) watch(ctx *gin.Context) {
 	db := h.Collector.DB()
+	h.WatchFilter = h.WatchPredicate(ctx)
 	err := h.Watch(
 		ctx,
 		db,
@@ -136,9 +156,10 @@ func (h ClusterHandler) watch(ctx *gin.Context) {
 // REST Resource.
 type Cluster struct {
 	Resource
-	DataCenter    string `json:"dataCenter"`
-	HaReservation bool   `json:"haReservation"`
-	KsmEnabled    bool   `json:"ksmEnabled"`
+	DataCenter     string                `json:"dataCenter"`
+	HaReservation  bool                  `json:"haReservation"`
+	KsmEnabled     bool                  `json:"ksmEnabled"`
+	AffinityGroups []model.AffinityGroup `json:"affinityGroups"`
 }
 
 //
@@ -148,6 +169,7 @@ func (r *Cluster) With(m *model.Cluster) {
 	r.DataCenter = m.DataCenter
 	r.HaReservation = m.HaReservation
 	r.KsmEnabled = m.KsmEnabled
+	r.AffinityGroups = m.AffinityGroups
 }
 
 //
@@ -51,7 +51,26 @@ func (h DiskHandler) List(ctx *gin.Context) {
 	}
 	db := h.Collector.DB()
 	list := []model.Disk{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	err := h.Sort.Build(&list, &options)
+	if err != nil {
+		log.V(3).Info(
+			err.Error(),
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	err = db.List(&list, options)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	err = h.Since.Header(ctx, db, &list)
 	if err != nil {
 		log.Trace(
 			err,
@@ -77,7 +96,7 @@ func (h DiskHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -122,7 +141,7 @@ func (h DiskHandler) Get(ctx *gin.Context) {
 	r.Link(h.Provider)
 	content := r.Content(true)
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -139,6 +158,7 @@ func (h *DiskHandler) Expand(r *Disk) (err error) {
 // Watch.
 func (h DiskHandler) watch(ctx *gin.Context) {
 	db := h.Collector.DB()
+	h.WatchFilter = h.WatchPredicate(ctx)
 	err := h.Watch(
 		ctx,
 		db,
@@ -171,6 +191,7 @@ type Disk struct {
 	ActualSize      int64       `json:"actualSize"`
 	StorageType     string      `json:"storageType"`
 	Status          string      `json:"status"`
+	Backup          string      `json:"backup"`
 }
 
 //
@@ -178,6 +199,7 @@ type Disk struct {
 func (r *Disk) With(m *model.Disk) {
 	r.Resource.With(&m.Base)
 	r.Status = m.Status
+	r.Backup = m.Backup
 	r.StorageType = m.StorageType
 	r.ProvisionedSize = m.ProvisionedSize
 	r.ActualSize = m.ActualSize
@@ -32,6 +32,7 @@ func (h *VMHandler) AddRoutes(e *gin.Engine) {
 	e.GET(VMsRoot, h.List)
 	e.GET(VMsRoot+"/", h.List)
 	e.GET(VMRoot, h.Get)
+	e.POST(VMValidateRoot, h.Validate)
 }
 
 //
@@ -51,7 +52,26 @@ func (h VMHandler) List(ctx *gin.Context) {
 	}
 	db := h.Collector.DB()
 	list := []model.VM{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	err := h.Sort.Build(&list, &options)
+	if err != nil {
+		log.V(3).Info(
+			err.Error(),
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	err = db.List(&list, options)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	err = h.Since.Header(ctx, db, &list)
 	if err != nil {
 		log.Trace(
 			err,
@@ -77,7 +97,7 @@ func (h VMHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -122,7 +142,7 @@ func (h VMHandler) Get(ctx *gin.Context) {
 	r.Link(h.Provider)
 	content := r.Content(true)
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -139,6 +159,7 @@ func (h *VMHandler) Expand(r *VM) (err error) {
 // Watch.
 func (h VMHandler) watch(ctx *gin.Context) {
 	db := h.Collector.DB()
+	h.WatchFilter = h.WatchPredicate(ctx)
 	err := h.Watch(
 		ctx,
 		db,
@@ -169,6 +190,7 @@ type VM struct {
 	RevisionValidated           int64            `json:"revisionValidated"`
 	PolicyVersion               int              `json:"policyVersion"`
 	GuestName                   string           `json:"guestName"`
+	HostName                    string           `json:"hostName"`
 	CpuSockets                  int16            `json:"cpuSockets"`
 	CpuCores                    int16            `json:"cpuCores"`
 	CpuShares                   int16            `json:"cpuShares"`
@@ -200,6 +222,19 @@ type VM struct {
 }
 
 type IpAddress = model.IpAddress
+
+//
+// VM summary (not detailed) resource.
+// Includes the fields which affect the migration strategy
+// and bootability on the target. Named as reported for vSphere.
+type VMSummary struct {
+	Resource
+	// BIOS type.
+	Firmware       string `json:"firmware"`
+	CpuCount       int32  `json:"cpuCount"`
+	CoresPerSocket int32  `json:"coresPerSocket"`
+	MemoryMB       int32  `json:"memoryMB"`
+}
 type CpuPinning = model.CpuPinning
 type HostDevice = model.HostDevice
 type CDROM = model.CDROM
@@ -229,6 +264,7 @@ func (r *VM) With(m *model.VM) {
 	r.RevisionValidated = m.RevisionValidated
 	r.PolicyVersion = m.PolicyVersion
 	r.GuestName = m.GuestName
+	r.HostName = m.HostName
 	r.CpuSockets = m.CpuSockets
 	r.CpuCores = m.CpuCores
 	r.CpuShares = m.CpuShares
@@ -358,7 +394,13 @@ func (r *VM) addNICs(m *model.VM) {
 // As content.
 func (r *VM) Content(detail bool) interface{} {
 	if !detail {
-		return r.Resource
+		return VMSummary{
+			Resource:       r.Resource,
+			Firmware:       r.BIOS,
+			CpuCount:       int32(r.CpuSockets) * int32(r.CpuCores),
+			CoresPerSocket: int32(r.CpuCores),
+			MemoryMB:       int32(r.Memory / 0x100000),
+		}
 	}
 
 	return r
@@ -76,7 +76,7 @@ func (h WorkloadHandler) Get(ctx *gin.Context) {
 	}
 	r.Link(h.Provider)
 
-	ctx.JSON(http.StatusOK, r)
+	h.Render(ctx, r)
 }
 
 //
@@ -0,0 +1,313 @@
+package web
+
+import (
+	liberr "github.com/konveyor/controller/pkg/error"
+	libcontainer "github.com/konveyor/controller/pkg/inventory/container"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	libitr "github.com/konveyor/controller/pkg/itinerary"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	ovirt "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ovirt"
+	vsphere "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+)
+
+//
+// Pipeline steps.
+// Must match the plan controller.
+const (
+	PreHook         = "PreHook"
+	QuiesceGuest    = "QuiesceGuest"
+	PreImportHook   = "PreImportHook"
+	DiskTransfer    = "DiskTransfer"
+	ImageConversion = "ImageConversion"
+	PostImportHook  = "PostImportHook"
+	PostHook        = "PostHook"
+)
+
+//
+// Plan (resolved) pipeline.
+type PlanPipeline struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// The pipelines reflect the running (or completed)
+	// migration. Otherwise, a preview.
+	Live bool `json:"live"`
+	// VM pipelines.
+	VMs []VMPipeline `json:"vms"`
+}
+
+//
+// VM (resolved) pipeline.
+type VMPipeline struct {
+	ref.Ref `json:",inline"`
+	// Pipeline includes image conversion.
+	ImageConversion bool `json:"imageConversion"`
+	// Pipeline.
+	Pipeline []*plan.Step `json:"pipeline"`
+	// Preview failed.
+	Error string `json:"error,omitempty"`
+}
+
+//
+// Build the live pipelines.
+// Derived from the plan status (read-only).
+func (r *PlanPipeline) With(p *api.Plan) {
+	r.Namespace = p.Namespace
+	r.Name = p.Name
+	r.Live = true
+	r.VMs = []VMPipeline{}
+	for _, vm := range p.Status.Migration.VMs {
+		r.VMs = append(
+			r.VMs,
+			VMPipeline{
+				Ref:             vm.Ref,
+				ImageConversion: r.hasStep(vm, ImageConversion),
+				Pipeline:        vm.Pipeline,
+			})
+	}
+}
+
+//
+// The VM pipeline includes the named step.
+func (r *PlanPipeline) hasStep(vm *plan.VMStatus, name string) (found bool) {
+	_, found = vm.FindStep(name)
+	return
+}
+
+//
+// Build the preview pipelines.
+// Tasks are resolved using the source provider inventory.
+func (r *PlanPipeline) Preview(p *api.Plan, provider *api.Provider, collector libcontainer.Collector) {
+	r.Namespace = p.Namespace
+	r.Name = p.Name
+	r.VMs = []VMPipeline{}
+	for i := range p.Spec.VMs {
+		vm := p.Spec.VMs[i]
+		vm.Hooks = p.Spec.VMHooks(&vm)
+		quiesce := p.Spec.Quiesce &&
+			!p.Spec.VMWarm(&vm) &&
+			provider.Type() == api.VSphere
+		vmPipeline := VMPipeline{
+			Ref:             vm.Ref,
+			ImageConversion: provider.Type() == api.VSphere && !vm.SkipConversion,
+		}
+		tasks, err := r.tasks(p, provider, collector.DB(), &vm)
+		if err != nil {
+			vmPipeline.Error = err.Error()
+		}
+		if _, found := vm.FindHook(PreHook); found {
+			vmPipeline.Pipeline = append(
+				vmPipeline.Pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        PreHook,
+						Description: "Run pre-migration hook.",
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
+		}
+		if quiesce {
+			vmPipeline.Pipeline = append(
+				vmPipeline.Pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        QuiesceGuest,
+						Description: "Quiesce the guest file systems.",
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
+		}
+		if _, found := vm.FindHook(PreImportHook); found {
+			vmPipeline.Pipeline = append(
+				vmPipeline.Pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        PreImportHook,
+						Description: "Run hook before the import is created.",
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
+		}
+		total := int64(0)
+		for _, task := range tasks {
+			total += task.Progress.Total
+		}
+		vmPipeline.Pipeline = append(
+			vmPipeline.Pipeline,
+			&plan.Step{
+				Task: plan.Task{
+					Name:        DiskTransfer,
+					Description: "Transfer disks.",
+					Progress: libitr.Progress{
+						Total: total,
+					},
+					Annotations: map[string]string{
+						"unit": "MB",
+					},
+				},
+				Tasks: tasks,
+			})
+		if vmPipeline.ImageConversion {
+			vmPipeline.Pipeline = append(
+				vmPipeline.Pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        ImageConversion,
+						Description: "Convert image to kubevirt.",
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
+		}
+		if _, found := vm.FindHook(PostImportHook); found {
+			vmPipeline.Pipeline = append(
+				vmPipeline.Pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        PostImportHook,
+						Description: "Run hook after the disk transfer and image conversion.",
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
+		}
+		if _, found := vm.FindHook(PostHook); found {
+			vmPipeline.Pipeline = append(
+				vmPipeline.Pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        PostHook,
+						Description: "Run post-migration hook.",
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
+		}
+		r.VMs = append(r.VMs, vmPipeline)
+	}
+}
+
+//
+// Build the disk transfer tasks.
+func (r *PlanPipeline) tasks(p *api.Plan, provider *api.Provider, db libmodel.DB, planVM *plan.VM) (list []*plan.Task, err error) {
+	vmRef := planVM.Ref
+	task := func(name string, size int64) *plan.Task {
+		return &plan.Task{
+			Name: name,
+			Progress: libitr.Progress{
+				Total: size / 0x100000,
+			},
+			Annotations: map[string]string{
+				"unit": "MB",
+			},
+		}
+	}
+	switch provider.Type() {
+	case api.VSphere:
+		vm := &vsphere.VM{}
+		err = r.findVSphere(db, vm, vmRef)
+		if err != nil {
+			return
+		}
+		for _, disk := range vm.Disks {
+			if p.Spec.SkipSharedDisks && (disk.Shared || disk.RDM) {
+				continue
+			}
+			name := vsphere.TrimBackingFileName(disk.File)
+			if planVM.Excluded(name) {
+				continue
+			}
+			list = append(
+				list,
+				task(name, disk.Capacity))
+		}
+	case api.OVirt:
+		vm := &ovirt.VM{}
+		err = r.findOVirt(db, vm, vmRef)
+		if err != nil {
+			return
+		}
+		for _, da := range vm.DiskAttachments {
+			disk := &ovirt.Disk{
+				Base: ovirt.Base{ID: da.Disk},
+			}
+			err = db.Get(disk)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			if p.Spec.SkipSharedDisks && disk.Shared {
+				continue
+			}
+			if planVM.Excluded(disk.ID) {
+				continue
+			}
+			list = append(
+				list,
+				task(disk.ID, disk.ProvisionedSize))
+		}
+	}
+
+	return
+}
+
+//
+// Find a vSphere VM by ID or by name.
+func (r *PlanPipeline) findVSphere(db libmodel.DB, vm *vsphere.VM, vmRef ref.Ref) (err error) {
+	if vmRef.ID != "" {
+		vm.ID = vmRef.ID
+		err = db.Get(vm)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	list := []vsphere.VM{}
+	err = db.List(
+		&list,
+		vsphere.ListOptions{
+			Predicate: libmodel.Eq(base.NameParam, vmRef.Name),
+			Detail:    vsphere.MaxDetail,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(list) == 0 {
+		err = liberr.Wrap(vsphere.NotFound)
+		return
+	}
+	*vm = list[0]
+
+	return
+}
+
+//
+// Find an oVirt VM by ID or by name.
+func (r *PlanPipeline) findOVirt(db libmodel.DB, vm *ovirt.VM, vmRef ref.Ref) (err error) {
+	if vmRef.ID != "" {
+		vm.ID = vmRef.ID
+		err = db.Get(vm)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	list := []ovirt.VM{}
+	err = db.List(
+		&list,
+		ovirt.ListOptions{
+			Predicate: libmodel.Eq(base.NameParam, vmRef.Name),
+			Detail:    ovirt.MaxDetail,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(list) == 0 {
+		err = liberr.Wrap(ovirt.NotFound)
+		return
+	}
+	*vm = list[0]
+
+	return
+}
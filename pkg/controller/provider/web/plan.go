@@ -0,0 +1,379 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	libcnd "github.com/konveyor/controller/pkg/condition"
+	libcontainer "github.com/konveyor/controller/pkg/inventory/container"
+	libitr "github.com/konveyor/controller/pkg/itinerary"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//
+// Routes.
+const (
+	PlanParam     = "plan"
+	PlansRoot     = "/namespaces/:" + base.NsParam + "/plans"
+	PlanRoot      = PlansRoot + "/:" + PlanParam
+	DescribeRoot  = PlanRoot + "/describe"
+	PipelineRoot  = PlanRoot + "/pipeline"
+	ReportRoot    = PlanRoot + "/report"
+	ConditionRoot = PlanRoot + "/conditions"
+	ExportRoot    = PlanRoot + "/export"
+)
+
+//
+// VM phases (summary).
+const (
+	VMPending   = "Pending"
+	VMStarted   = "Started"
+	VMCompleted = "Completed"
+)
+
+//
+// VM conditions (summary).
+const (
+	Succeeded = "Succeeded"
+	Failed    = "Failed"
+	Canceled  = "Canceled"
+)
+
+//
+// Plan handler.
+type PlanHandler struct {
+	base.Handler
+	// k8s API reader.
+	Client client.Reader
+}
+
+//
+// Add routes to the `gin` router.
+func (h *PlanHandler) AddRoutes(e *gin.Engine) {
+	e.GET(DescribeRoot, h.Describe)
+	e.GET(PipelineRoot, h.Pipeline)
+	e.GET(ReportRoot, h.Report)
+	e.GET(ConditionRoot, h.Conditions)
+	e.GET(ExportRoot, h.Export)
+	e.GET(LogsRoot, h.Logs)
+	e.GET(WaitRoot, h.Wait)
+	e.GET(CapacityRoot, h.Capacity)
+	e.GET(CompareRoot, h.Compare)
+}
+
+//
+// List resources in a REST collection.
+func (h PlanHandler) List(ctx *gin.Context) {
+}
+
+//
+// Get a specific REST resource.
+func (h PlanHandler) Get(ctx *gin.Context) {
+}
+
+//
+// Describe the plan.
+// Returns a summary of the VM migration status.
+func (h PlanHandler) Describe(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	p := &api.Plan{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(PlanParam),
+		},
+		p)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	r := PlanSummary{}
+	r.With(p)
+
+	ctx.JSON(http.StatusOK, r)
+}
+
+//
+// The resolved pipeline of each VM.
+// Reflects the migration when the plan has been executed.
+// Otherwise, a preview is built using the inventory.
+func (h PlanHandler) Pipeline(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	p := &api.Plan{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(PlanParam),
+		},
+		p)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	r := PlanPipeline{}
+	if len(p.Status.Migration.VMs) > 0 {
+		r.With(p)
+		ctx.JSON(http.StatusOK, r)
+		return
+	}
+	provider := &api.Provider{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: p.Spec.Provider.Source.Namespace,
+			Name:      p.Spec.Provider.Source.Name,
+		},
+		provider)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	collector, found := h.Container.Get(provider)
+	if !found {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	r.Preview(p, provider, collector)
+
+	ctx.JSON(http.StatusOK, r)
+}
+
+//
+// Migration report (CSV).
+// The source VM path is resolved using the inventory
+// when the provider is available.
+func (h PlanHandler) Report(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	p := &api.Plan{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(PlanParam),
+		},
+		p)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	var collector libcontainer.Collector
+	provider := &api.Provider{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: p.Spec.Provider.Source.Namespace,
+			Name:      p.Spec.Provider.Source.Name,
+		},
+		provider)
+	if status == http.StatusOK {
+		collector, _ = h.Container.Get(provider)
+	}
+	r := PlanReport{}
+	r.With(p, provider, collector)
+	ctx.Header(
+		"Content-Disposition",
+		fmt.Sprintf("attachment; filename=%q", r.FileName()))
+	ctx.Header("Content-Type", "text/csv")
+	ctx.Status(http.StatusOK)
+	err := r.Write(ctx.Writer)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+	}
+}
+
+//
+// The active conditions of the plan, the active snapshot
+// and each VM. Blocking (critical) and error conditions first.
+func (h PlanHandler) Conditions(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	p := &api.Plan{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(PlanParam),
+		},
+		p)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	r := PlanConditions{}
+	r.With(p)
+
+	ctx.JSON(http.StatusOK, r)
+}
+
+//
+// Export the plan and referenced mappings as
+// (multi-document) YAML manifests.
+func (h PlanHandler) Export(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	p := &api.Plan{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(PlanParam),
+		},
+		p)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	network := &api.NetworkMap{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: p.Spec.Map.Network.Namespace,
+			Name:      p.Spec.Map.Network.Name,
+		},
+		network)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	storage := &api.StorageMap{}
+	status = h.get(
+		ctx,
+		client.ObjectKey{
+			Namespace: p.Spec.Map.Storage.Namespace,
+			Name:      p.Spec.Map.Storage.Name,
+		},
+		storage)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	r := PlanExport{}
+	r.With(p, network, storage)
+	ctx.Header(
+		"Content-Disposition",
+		fmt.Sprintf("attachment; filename=%q", r.FileName()))
+	ctx.Header("Content-Type", "application/yaml")
+	ctx.Status(http.StatusOK)
+	err := r.Write(ctx.Writer)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+	}
+}
+
+//
+// Get a k8s resource.
+// Returns the http status.
+func (h PlanHandler) get(ctx *gin.Context, key client.ObjectKey, object runtime.Object) (status int) {
+	status = http.StatusOK
+	err := h.Client.Get(context.TODO(), key, object)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			status = http.StatusNotFound
+		} else {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			status = http.StatusInternalServerError
+		}
+	}
+
+	return
+}
+
+//
+// Plan summary.
+type PlanSummary struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// VM count by phase.
+	Phases map[string]int `json:"phases"`
+	// VM count by condition.
+	Conditions map[string]int `json:"conditions"`
+	// VM progress.
+	Progress libitr.Progress `json:"progress"`
+	// Conditions of the active snapshot.
+	Snapshot []libcnd.Condition `json:"snapshot,omitempty"`
+}
+
+//
+// Build the summary.
+// Derived from the plan status (read-only).
+func (r *PlanSummary) With(p *api.Plan) {
+	r.Namespace = p.Namespace
+	r.Name = p.Name
+	r.Phases = map[string]int{}
+	r.Conditions = map[string]int{
+		Succeeded: 0,
+		Failed:    0,
+		Canceled:  0,
+	}
+	migration := p.Status.Migration
+	for _, vm := range migration.VMs {
+		r.Phases[r.phase(vm)]++
+		for _, cndType := range []string{Succeeded, Failed, Canceled} {
+			if vm.HasCondition(cndType) {
+				r.Conditions[cndType]++
+			}
+		}
+		if vm.MarkedCompleted() {
+			r.Progress.Completed++
+		}
+	}
+	r.Progress.Total = int64(len(migration.VMs))
+	if len(migration.History) > 0 {
+		snapshot := migration.ActiveSnapshot()
+		r.Snapshot = snapshot.List
+	}
+}
+
+//
+// The VM phase.
+// The name of the running pipeline step when running.
+func (r *PlanSummary) phase(vm *plan.VMStatus) string {
+	if !vm.MarkedStarted() {
+		return VMPending
+	}
+	if vm.MarkedCompleted() {
+		return VMCompleted
+	}
+	for _, step := range vm.Pipeline {
+		if step.Running() {
+			return step.Name
+		}
+	}
+
+	return VMStarted
+}
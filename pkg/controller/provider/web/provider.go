@@ -8,8 +8,8 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
-
 	"net/http"
+	"strings"
 )
 
 //
@@ -22,6 +22,12 @@ const (
 	ProvidersRoot = "/providers"
 )
 
+//
+// Params.
+const (
+	TypeParam = "type"
+)
+
 //
 // Provider handler.
 type ProviderHandler struct {
@@ -37,76 +43,88 @@ func (h *ProviderHandler) AddRoutes(e *gin.Engine) {
 
 //
 // List resources in a REST collection.
+// Providers are listed by type. The `type` parameter
+// may be used to list providers of the specified type.
 func (h ProviderHandler) List(ctx *gin.Context) {
 	status := h.Prepare(ctx)
 	if status != http.StatusOK {
 		ctx.Status(status)
 		return
 	}
-	// OCP
-	ocpHandler := &ocp.ProviderHandler{
-		Handler: base.Handler{
-			Container: h.Container,
-		},
-	}
-	status = ocpHandler.Prepare(ctx)
+	pType, status := h.providerType(ctx)
 	if status != http.StatusOK {
 		ctx.Status(status)
 		return
 	}
-	ocpList, err := ocpHandler.ListContent(ctx)
-	if err != nil {
-		log.Trace(
-			err,
-			"url",
-			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
-		return
+	r := Provider{}
+	// OCP
+	if pType == "" || pType == api.OpenShift {
+		ocpHandler := &ocp.ProviderHandler{
+			Handler: base.Handler{
+				Container: h.Container,
+			},
+		}
+		status = ocpHandler.Prepare(ctx)
+		if status != http.StatusOK {
+			ctx.Status(status)
+			return
+		}
+		ocpList, err := ocpHandler.ListContent(ctx)
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		r[api.OpenShift] = ocpList
 	}
 	// vSphere
-	vSphereHandler := &vsphere.ProviderHandler{
-		Handler: base.Handler{
-			Container: h.Container,
-		},
-	}
-	status = vSphereHandler.Prepare(ctx)
-	if status != http.StatusOK {
-		ctx.Status(status)
-		return
-	}
-	vSphereList, err := vSphereHandler.ListContent(ctx)
-	if err != nil {
-		log.Trace(
-			err,
-			"url",
-			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
-		return
+	if pType == "" || pType == api.VSphere {
+		vSphereHandler := &vsphere.ProviderHandler{
+			Handler: base.Handler{
+				Container: h.Container,
+			},
+		}
+		status = vSphereHandler.Prepare(ctx)
+		if status != http.StatusOK {
+			ctx.Status(status)
+			return
+		}
+		vSphereList, err := vSphereHandler.ListContent(ctx)
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		r[api.VSphere] = vSphereList
 	}
 	// oVirt
-	oVirtHandler := &ovirt.ProviderHandler{
-		Handler: base.Handler{
-			Container: h.Container,
-		},
-	}
-	status = oVirtHandler.Prepare(ctx)
-	if status != http.StatusOK {
-		ctx.Status(status)
-		return
-	}
-	oVirtList, err := oVirtHandler.ListContent(ctx)
-	if err != nil {
-		log.Trace(
-			err,
-			"url",
-			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
-		return
-	}
-	r := Provider{
-		api.OpenShift: ocpList,
-		api.VSphere:   vSphereList,
-		api.OVirt:     oVirtList,
+	if pType == "" || pType == api.OVirt {
+		oVirtHandler := &ovirt.ProviderHandler{
+			Handler: base.Handler{
+				Container: h.Container,
+			},
+		}
+		status = oVirtHandler.Prepare(ctx)
+		if status != http.StatusOK {
+			ctx.Status(status)
+			return
+		}
+		oVirtList, err := oVirtHandler.ListContent(ctx)
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		r[api.OVirt] = oVirtList
 	}
 
 	content := r
@@ -114,6 +132,25 @@ func (h ProviderHandler) List(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, content)
 }
 
+//
+// The provider type requested by the `type` parameter.
+// The `ocp` alias may be used for OpenShift.
+func (h ProviderHandler) providerType(ctx *gin.Context) (pType string, status int) {
+	status = http.StatusOK
+	q := ctx.Request.URL.Query()
+	pType = strings.ToLower(q.Get(TypeParam))
+	switch pType {
+	case "":
+	case "ocp":
+		pType = api.OpenShift
+	case api.OpenShift, api.VSphere, api.OVirt:
+	default:
+		status = http.StatusBadRequest
+	}
+
+	return
+}
+
 //
 // Get a specific REST resource.
 func (h ProviderHandler) Get(ctx *gin.Context) {
@@ -0,0 +1,202 @@
+package web
+
+import (
+	"encoding/csv"
+	"fmt"
+	libcontainer "github.com/konveyor/controller/pkg/inventory/container"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	vsphere "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"io"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+	"time"
+)
+
+//
+// Report columns.
+var ReportColumns = []string{
+	"vm",
+	"id",
+	"path",
+	"phase",
+	"outcome",
+	"disks",
+	"transferredMB",
+	"started",
+	"completed",
+	"steps",
+	"error",
+}
+
+//
+// Plan migration report.
+type PlanReport struct {
+	Namespace string
+	Name      string
+	// Report rows.
+	Rows []ReportRow
+}
+
+//
+// Report row (VM).
+type ReportRow struct {
+	// VM name.
+	VM string
+	// VM ID.
+	ID string
+	// Source VM location.
+	Path string
+	// VM phase.
+	Phase string
+	// Succeeded|Failed|Canceled.
+	// Otherwise, Pending|Running.
+	Outcome string
+	// Disks transferred (completed/total).
+	Disks string
+	// Data transferred (MB).
+	Transferred int64
+	// Started timestamp.
+	Started *meta.Time
+	// Completed timestamp.
+	Completed *meta.Time
+	// Per-step progress.
+	Steps string
+	// Error reasons.
+	Error string
+}
+
+//
+// The report file name.
+func (r *PlanReport) FileName() string {
+	return fmt.Sprintf("%s-%s.csv", r.Namespace, r.Name)
+}
+
+//
+// Build the report.
+// Derived from the plan status (read-only). The source VM
+// path is resolved using the inventory when available.
+func (r *PlanReport) With(p *api.Plan, provider *api.Provider, collector libcontainer.Collector) {
+	r.Namespace = p.Namespace
+	r.Name = p.Name
+	r.Rows = []ReportRow{}
+	summary := PlanSummary{}
+	for _, vm := range p.Status.Migration.VMs {
+		row := ReportRow{
+			VM:        vm.Name,
+			ID:        vm.ID,
+			Phase:     summary.phase(vm),
+			Outcome:   r.outcome(vm),
+			Started:   vm.Started,
+			Completed: vm.Completed,
+		}
+		if collector != nil {
+			row.Path = r.path(provider, collector, vm)
+		}
+		steps := []string{}
+		for _, step := range vm.Pipeline {
+			steps = append(
+				steps,
+				fmt.Sprintf(
+					"%s=%d/%d",
+					step.Name,
+					step.Progress.Completed,
+					step.Progress.Total))
+			if step.Name != DiskTransfer {
+				continue
+			}
+			completed := 0
+			for _, task := range step.Tasks {
+				if task.MarkedCompleted() && task.Error == nil {
+					completed++
+				}
+			}
+			row.Disks = fmt.Sprintf("%d/%d", completed, len(step.Tasks))
+			row.Transferred = step.Progress.Completed
+		}
+		row.Steps = strings.Join(steps, ";")
+		if vm.Error != nil {
+			row.Error = strings.Join(vm.Error.Reasons, ";")
+		}
+		r.Rows = append(r.Rows, row)
+	}
+}
+
+//
+// Write the report as CSV.
+func (r *PlanReport) Write(w io.Writer) (err error) {
+	writer := csv.NewWriter(w)
+	err = writer.Write(ReportColumns)
+	if err != nil {
+		return
+	}
+	for _, row := range r.Rows {
+		err = writer.Write(
+			[]string{
+				row.VM,
+				row.ID,
+				row.Path,
+				row.Phase,
+				row.Outcome,
+				row.Disks,
+				fmt.Sprintf("%d", row.Transferred),
+				r.timestamp(row.Started),
+				r.timestamp(row.Completed),
+				row.Steps,
+				row.Error,
+			})
+		if err != nil {
+			return
+		}
+	}
+	writer.Flush()
+	err = writer.Error()
+	return
+}
+
+//
+// The VM outcome.
+func (r *PlanReport) outcome(vm *plan.VMStatus) string {
+	for _, cndType := range []string{Succeeded, Failed, Canceled} {
+		if vm.HasCondition(cndType) {
+			return cndType
+		}
+	}
+	if vm.MarkedStarted() {
+		return "Running"
+	}
+
+	return VMPending
+}
+
+//
+// Resolve the source VM path.
+// Only vSphere VMs have an inventory path.
+func (r *PlanReport) path(provider *api.Provider, collector libcontainer.Collector, vm *plan.VMStatus) (path string) {
+	if provider.Type() != api.VSphere {
+		return
+	}
+	m := &vsphere.VM{}
+	m.ID = vm.ID
+	db := collector.DB()
+	err := db.Get(m)
+	if err != nil {
+		return
+	}
+	path, err = m.Path(db)
+	if err != nil {
+		path = ""
+	}
+
+	return
+}
+
+//
+// Format a timestamp.
+func (r *PlanReport) timestamp(t *meta.Time) (s string) {
+	if t != nil {
+		s = t.UTC().Format(time.RFC3339)
+	}
+
+	return
+}
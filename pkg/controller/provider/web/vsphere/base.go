@@ -39,6 +39,51 @@ func (h Handler) Predicate(ctx *gin.Context) (p libmodel.Predicate) {
 	return
 }
 
+//
+// Build the watch filter.
+// Built using the list predicate (and additional predicates
+// such as the `folder` predicate) and the `name` (path) filter.
+func (h Handler) WatchPredicate(
+	ctx *gin.Context,
+	db libmodel.DB,
+	predicates ...libmodel.Predicate) (filter base.WatchFilter) {
+	//
+	list := []libmodel.Predicate{}
+	for _, p := range append([]libmodel.Predicate{h.Predicate(ctx)}, predicates...) {
+		if p != nil {
+			list = append(list, p)
+		}
+	}
+	if len(list) == 0 {
+		return
+	}
+	matched := base.PredicateFilter(libmodel.And(list...))
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	path := strings.Split(name, "/")
+	filter = func(m libmodel.Model) bool {
+		if !matched(m) {
+			return false
+		}
+		if len(path) < 2 {
+			return true
+		}
+		pathed, cast := m.(interface {
+			Path(libmodel.DB) (string, error)
+		})
+		if !cast {
+			return true
+		}
+		absolute, err := pathed.Path(db)
+		if err != nil {
+			return true
+		}
+		return h.PathMatch(absolute, name)
+	}
+
+	return
+}
+
 //
 // Build list options.
 func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
@@ -47,7 +92,7 @@ func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
 		detail = 1
 	}
 	return libmodel.ListOptions{
-		Predicate: h.Predicate(ctx),
+		Predicate: h.Since.Predicate(h.Predicate(ctx)),
 		Detail:    detail,
 		Page:      &h.Page,
 	}
@@ -52,7 +52,26 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 	}
 	db := h.Collector.DB()
 	list := []model.Cluster{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	err := h.Sort.Build(&list, &options)
+	if err != nil {
+		log.V(3).Info(
+			err.Error(),
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	err = db.List(&list, options)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	err = h.Since.Header(ctx, db, &list)
 	if err != nil {
 		log.Trace(
 			err,
@@ -69,7 +88,7 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -112,13 +131,14 @@ func (h ClusterHandler) Get(ctx *gin.Context) {
 	r.Link(h.Provider)
 	content := r.Content(true)
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
 // Watch.
 func (h ClusterHandler) watch(ctx *gin.Context) {
 	db := h.Collector.DB()
+	h.WatchFilter = h.WatchPredicate(ctx, db)
 	err := h.Watch(
 		ctx,
 		db,
@@ -154,6 +174,8 @@ type Cluster struct {
 	DrsEnabled  bool        `json:"drsEnabled"`
 	DrsBehavior string      `json:"drsBehavior"`
 	DrsVms      []model.Ref `json:"drsVms"`
+	// DRS VM anti-affinity rules.
+	AntiAffinity []model.AntiAffinityRule `json:"antiAffinity"`
 }
 
 //
@@ -168,7 +190,8 @@ func (r *Cluster) With(m *model.Cluster) {
 	r.Datastores = m.Datastores
 	r.Hosts = m.Hosts
 	r.DasVms = m.DasVms
-	r.DrsVms = m.DasVms
+	r.DrsVms = m.DrsVms
+	r.AntiAffinity = m.AntiAffinity
 }
 
 //
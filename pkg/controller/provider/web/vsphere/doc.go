@@ -67,5 +67,15 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				base.Handler{Container: container},
 			},
 		},
+		&SearchHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&WatchHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
 	}
 }
@@ -30,6 +30,8 @@ func (h *ProviderHandler) AddRoutes(e *gin.Engine) {
 	e.GET(ProvidersRoot, h.List)
 	e.GET(ProvidersRoot+"/", h.List)
 	e.GET(ProviderRoot, h.Get)
+	e.GET(ProviderRoot+base.RefreshRoot, h.Refreshed)
+	e.POST(ProviderRoot+base.RefreshRoot, h.Refresh)
 }
 
 //
@@ -54,7 +56,7 @@ func (h ProviderHandler) List(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -86,7 +88,7 @@ func (h ProviderHandler) Get(ctx *gin.Context) {
 	r.Link()
 	content := r.Content(true)
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
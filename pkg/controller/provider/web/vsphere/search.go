@@ -0,0 +1,303 @@
+package vsphere
+
+import (
+	"github.com/gin-gonic/gin"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"net/http"
+	"strings"
+)
+
+//
+// Routes.
+const (
+	SearchParam = "q"
+	KindParam   = "kind"
+	SearchRoot  = ProviderRoot + "/search"
+)
+
+//
+// Max number of matches returned.
+const (
+	MaxSearchResults = 100
+)
+
+//
+// Searched kinds (collections).
+var SearchKinds = []string{
+	VMCollection,
+	HostCollection,
+	NetworkCollection,
+	DatastoreCollection,
+}
+
+//
+// Search handler.
+type SearchHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *SearchHandler) AddRoutes(e *gin.Engine) {
+	e.GET(SearchRoot, h.Search)
+}
+
+//
+// List resources in a REST collection.
+func (h SearchHandler) List(ctx *gin.Context) {
+}
+
+//
+// Get a specific REST resource.
+func (h SearchHandler) Get(ctx *gin.Context) {
+}
+
+//
+// Search VMs, hosts, networks and datastores by name.
+// VMs and hosts are also matched by IP address.
+// Case-insensitive substring match. The `kind` parameter
+// narrows the search to a collection (vms|hosts|networks|datastores).
+func (h SearchHandler) Search(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	q := ctx.Request.URL.Query()
+	search := SearchMatcher{
+		Text: strings.ToLower(strings.TrimSpace(q.Get(SearchParam))),
+	}
+	if search.Text == "" {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	kinds := SearchKinds
+	if kind := q.Get(KindParam); kind != "" {
+		kinds = []string{}
+		for _, k := range SearchKinds {
+			if k == kind {
+				kinds = append(kinds, k)
+			}
+		}
+		if len(kinds) == 0 {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+	}
+	db := h.Collector.DB()
+	content := []SearchMatch{}
+	for _, kind := range kinds {
+		var matches []SearchMatch
+		var err error
+		switch kind {
+		case VMCollection:
+			matches, err = h.vms(db, &search)
+		case HostCollection:
+			matches, err = h.hosts(db, &search)
+		case NetworkCollection:
+			matches, err = h.networks(db, &search)
+		case DatastoreCollection:
+			matches, err = h.datastores(db, &search)
+		}
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		for i := range matches {
+			if len(content) == MaxSearchResults {
+				break
+			}
+			m := &matches[i]
+			m.Kind = kind
+			m.Link(h.Provider)
+			content = append(content, *m)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+//
+// Search VMs by name and IP address.
+func (h SearchHandler) vms(db libmodel.DB, search *SearchMatcher) (matches []SearchMatch, err error) {
+	list := []model.VM{}
+	err = db.List(&list, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	for i := range list {
+		m := &list[i]
+		values := []string{m.Name, m.IpAddress}
+		for _, guestNetwork := range m.GuestNetworks {
+			values = append(values, guestNetwork.IPs...)
+		}
+		if !search.Match(values...) {
+			continue
+		}
+		match := SearchMatch{}
+		err = match.With(db, &m.Base)
+		if err != nil {
+			return
+		}
+		matches = append(matches, match)
+		if len(matches) == MaxSearchResults {
+			break
+		}
+	}
+
+	return
+}
+
+//
+// Search hosts by name and management IP address.
+func (h SearchHandler) hosts(db libmodel.DB, search *SearchMatcher) (matches []SearchMatch, err error) {
+	list := []model.Host{}
+	err = db.List(&list, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	for i := range list {
+		m := &list[i]
+		if !search.Match(m.Name, m.ManagementServerIp) {
+			continue
+		}
+		match := SearchMatch{}
+		err = match.With(db, &m.Base)
+		if err != nil {
+			return
+		}
+		matches = append(matches, match)
+		if len(matches) == MaxSearchResults {
+			break
+		}
+	}
+
+	return
+}
+
+//
+// Search networks by name.
+func (h SearchHandler) networks(db libmodel.DB, search *SearchMatcher) (matches []SearchMatch, err error) {
+	list := []model.Network{}
+	err = db.List(&list, model.ListOptions{})
+	if err != nil {
+		return
+	}
+	for i := range list {
+		m := &list[i]
+		if !search.Match(m.Name) {
+			continue
+		}
+		match := SearchMatch{}
+		err = match.With(db, &m.Base)
+		if err != nil {
+			return
+		}
+		matches = append(matches, match)
+		if len(matches) == MaxSearchResults {
+			break
+		}
+	}
+
+	return
+}
+
+//
+// Search datastores by name.
+func (h SearchHandler) datastores(db libmodel.DB, search *SearchMatcher) (matches []SearchMatch, err error) {
+	list := []model.Datastore{}
+	err = db.List(&list, model.ListOptions{})
+	if err != nil {
+		return
+	}
+	for i := range list {
+		m := &list[i]
+		if !search.Match(m.Name) {
+			continue
+		}
+		match := SearchMatch{}
+		err = match.With(db, &m.Base)
+		if err != nil {
+			return
+		}
+		matches = append(matches, match)
+		if len(matches) == MaxSearchResults {
+			break
+		}
+	}
+
+	return
+}
+
+//
+// Search (text) matcher.
+type SearchMatcher struct {
+	// Lower case text.
+	Text string
+}
+
+//
+// Case-insensitive substring match of any value.
+func (r *SearchMatcher) Match(values ...string) bool {
+	for _, v := range values {
+		if v != "" && strings.Contains(strings.ToLower(v), r.Text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// Search match.
+type SearchMatch struct {
+	// Kind (collection).
+	Kind string `json:"kind"`
+	// Object ID.
+	ID string `json:"id"`
+	// Object name.
+	Name string `json:"name"`
+	// Inventory path.
+	Path string `json:"path"`
+	// Self link.
+	SelfLink string `json:"selfLink"`
+}
+
+//
+// Build the match using the model.
+func (r *SearchMatch) With(db libmodel.DB, m *model.Base) (err error) {
+	r.ID = m.ID
+	r.Name = m.Name
+	r.Path, err = m.Path(db)
+	return
+}
+
+//
+// Build self link (URI).
+func (r *SearchMatch) Link(p *api.Provider) {
+	root := map[string]string{
+		VMCollection:        VMRoot,
+		HostCollection:      HostRoot,
+		NetworkCollection:   NetworkRoot,
+		DatastoreCollection: DatastoreRoot,
+	}
+	param := map[string]string{
+		VMCollection:        VMParam,
+		HostCollection:      HostParam,
+		NetworkCollection:   NetworkParam,
+		DatastoreCollection: DatastoreParam,
+	}
+	r.SelfLink = base.Link(
+		root[r.Kind],
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			param[r.Kind]:      r.ID,
+		})
+}
@@ -0,0 +1,154 @@
+package vsphere
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	liberr "github.com/konveyor/controller/pkg/error"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/validation/policy"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	VMValidateRoot = VMRoot + "/validate"
+)
+
+//
+// Policy agent endpoints.
+const (
+	PolicyBaseEndpoint       = "/v1/data/io/konveyor/forklift/vmware/"
+	PolicyVersionEndpoint    = PolicyBaseEndpoint + "rules_version"
+	PolicyValidationEndpoint = PolicyBaseEndpoint + "validate"
+)
+
+//
+// Errors.
+var VMChanged = errors.New("VM changed during validation")
+
+//
+// Validate (on demand) a specific VM.
+// The VM is validated by the policy agent and the concerns,
+// policy version and validated revision are updated. The
+// updated VM is returned.
+func (h VMHandler) Validate(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	if !policy.Agent.Enabled() {
+		ctx.Status(http.StatusServiceUnavailable)
+		return
+	}
+	db := h.Collector.DB()
+	m, err := h.validate(db, ctx.Param(VMParam))
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, VMChanged) {
+		ctx.Status(http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &VM{}
+	r.With(m)
+	r.Path, err = m.Path(db)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	builder := DiskBuilder{
+		db:         db,
+		datastores: map[string]string{},
+	}
+	err = builder.build(r)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r.Link(h.Provider)
+	content := r.Content(true)
+
+	h.Render(ctx, content)
+}
+
+//
+// Validate the VM and update the model.
+// The VM is not updated (VMChanged) when it has been
+// updated while being validated.
+func (h VMHandler) validate(db libmodel.DB, id string) (vm *model.VM, err error) {
+	vm = &model.VM{
+		Base: model.Base{ID: id},
+	}
+	err = db.Get(vm)
+	if err != nil {
+		return
+	}
+	workload := Workload{}
+	workload.With(vm)
+	err = workload.Expand(db)
+	if err != nil {
+		return
+	}
+	workload.Link(h.Provider)
+	version, concerns, err := policy.Agent.Validate(PolicyValidationEndpoint, workload)
+	if err != nil {
+		return
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer func() {
+		_ = tx.End()
+	}()
+	latest := &model.VM{
+		Base: model.Base{ID: id},
+	}
+	err = tx.Get(latest)
+	if err != nil {
+		return
+	}
+	if latest.Revision != vm.Revision {
+		err = liberr.Wrap(VMChanged)
+		return
+	}
+	latest.PolicyVersion = version
+	latest.RevisionValidated = latest.Revision
+	latest.Concerns = concerns
+	latest.Revision--
+	err = tx.Update(latest)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	vm = latest
+
+	return
+}
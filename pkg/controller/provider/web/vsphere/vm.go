@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/gin-gonic/gin"
 	liberr "github.com/konveyor/controller/pkg/error"
+	fb "github.com/konveyor/controller/pkg/filebacked"
 	libmodel "github.com/konveyor/controller/pkg/inventory/model"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
@@ -33,6 +34,7 @@ func (h *VMHandler) AddRoutes(e *gin.Engine) {
 	e.GET(VMsRoot, h.List)
 	e.GET(VMsRoot+"/", h.List)
 	e.GET(VMRoot, h.Get)
+	e.POST(VMValidateRoot, h.Validate)
 }
 
 //
@@ -40,6 +42,9 @@ func (h *VMHandler) AddRoutes(e *gin.Engine) {
 // A GET onn the collection that includes the `X-Watch`
 // header will negotiate an upgrade of the connection
 // to a websocket and push watch events.
+// The `folder` parameter (ID) limits the list to VMs in
+// the folder and nested folders. The VMs are streamed as
+// NDJSON when requested by the Accept header.
 func (h VMHandler) List(ctx *gin.Context) {
 	status := h.Prepare(ctx)
 	if status != http.StatusOK {
@@ -52,7 +57,42 @@ func (h VMHandler) List(ctx *gin.Context) {
 	}
 	db := h.Collector.DB()
 	list := []model.VM{}
-	err := db.List(&list, h.ListOptions(ctx))
+	options := h.ListOptions(ctx)
+	folder, status := h.folderPredicate(ctx, db)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	if folder != nil {
+		if options.Predicate != nil {
+			options.Predicate = libmodel.And(options.Predicate, folder)
+		} else {
+			options.Predicate = folder
+		}
+	}
+	err := h.Sort.Build(&list, &options)
+	if err != nil {
+		log.V(3).Info(
+			err.Error(),
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	if h.Streaming() {
+		h.stream(ctx, db, options)
+		return
+	}
+	err = db.List(&list, options)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	err = h.Since.Header(ctx, db, &list)
 	if err != nil {
 		log.Trace(
 			err,
@@ -71,14 +111,29 @@ func (h VMHandler) List(ctx *gin.Context) {
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
+	builder := DiskBuilder{
+		db:         db,
+		datastores: map[string]string{},
+	}
 	for _, m := range list {
 		r := &VM{}
 		r.With(&m)
+		if h.Detail {
+			err = builder.build(r)
+			if err != nil {
+				log.Trace(
+					err,
+					"url",
+					ctx.Request.URL)
+				ctx.Status(http.StatusInternalServerError)
+				return
+			}
+		}
 		r.Link(h.Provider)
 		content = append(content, r.Content(h.Detail))
 	}
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -119,16 +174,35 @@ func (h VMHandler) Get(ctx *gin.Context) {
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
+	builder := DiskBuilder{
+		db:         db,
+		datastores: map[string]string{},
+	}
+	err = builder.build(r)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
 	r.Link(h.Provider)
 	content := r.Content(true)
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
 // Watch.
 func (h VMHandler) watch(ctx *gin.Context) {
 	db := h.Collector.DB()
+	folder, status := h.folderPredicate(ctx, db)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	h.WatchFilter = h.WatchPredicate(ctx, db, folder)
 	err := h.Watch(
 		ctx,
 		db,
@@ -158,14 +232,10 @@ func (h VMHandler) filter(ctx *gin.Context, list *[]model.VM) (err error) {
 	if len(*list) < 2 {
 		return
 	}
-	q := ctx.Request.URL.Query()
-	name := q.Get(NameParam)
+	name := h.pathName(ctx)
 	if len(name) == 0 {
 		return
 	}
-	if len(strings.Split(name, "/")) < 2 {
-		return
-	}
 	db := h.Collector.DB()
 	kept := []model.VM{}
 	for _, m := range *list {
@@ -184,6 +254,133 @@ func (h VMHandler) filter(ctx *gin.Context, list *[]model.VM) (err error) {
 	return
 }
 
+//
+// The `name` parameter when specified as a path.
+func (h VMHandler) pathName(ctx *gin.Context) (name string) {
+	q := ctx.Request.URL.Query()
+	name = q.Get(NameParam)
+	if len(strings.Split(name, "/")) < 2 {
+		name = ""
+	}
+
+	return
+}
+
+//
+// Stream the VMs (NDJSON).
+// Each VM is read from the DB, built and written as
+// it is read rather than rendered as a list.
+func (h VMHandler) stream(ctx *gin.Context, db libmodel.DB, options libmodel.ListOptions) {
+	err := h.Since.Header(ctx, db, &[]model.VM{})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	itr, err := db.Find(&model.VM{}, options)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	name := h.pathName(ctx)
+	builder := DiskBuilder{
+		db:         db,
+		datastores: map[string]string{},
+	}
+	h.Stream(
+		ctx,
+		itr,
+		func(itr fb.Iterator, index int) (content interface{}, kept bool, err error) {
+			m := &model.VM{}
+			itr.AtWith(index, m)
+			if len(name) > 0 {
+				path, pErr := m.Path(db)
+				if pErr != nil {
+					err = liberr.Wrap(pErr)
+					return
+				}
+				if !h.PathMatch(path, name) {
+					return
+				}
+			}
+			r := &VM{}
+			r.With(m)
+			if h.Detail {
+				err = builder.build(r)
+				if err != nil {
+					return
+				}
+			}
+			r.Link(h.Provider)
+			content = r.Content(h.Detail)
+			kept = true
+			return
+		})
+}
+
+//
+// Build the folder predicate using the `folder` parameter.
+// VMs in the folder subtree (nested folders) are included.
+func (h VMHandler) folderPredicate(ctx *gin.Context, db libmodel.DB) (p libmodel.Predicate, status int) {
+	status = http.StatusOK
+	root := ctx.Request.URL.Query().Get(FolderParam)
+	if len(root) == 0 {
+		return
+	}
+	folders := []libmodel.Predicate{}
+	visited := map[string]bool{}
+	pending := []string{root}
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		m := &model.Folder{
+			Base: model.Base{
+				ID: id,
+			},
+		}
+		err := db.Get(m)
+		if errors.Is(err, model.NotFound) {
+			if id == root {
+				status = http.StatusNotFound
+				return
+			}
+			continue
+		}
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			status = http.StatusInternalServerError
+			return
+		}
+		folders = append(folders, libmodel.Eq("Folder", id))
+		for _, child := range m.Children {
+			if child.Kind == model.FolderKind {
+				pending = append(pending, child.ID)
+			}
+		}
+	}
+	if len(folders) == 1 {
+		p = folders[0]
+	} else {
+		p = libmodel.Or(folders...)
+	}
+
+	return
+}
+
 //
 // REST Resource.
 type VM struct {
@@ -194,6 +391,7 @@ type VM struct {
 	RevisionValidated     int64           `json:"revisionValidated"`
 	UUID                  string          `json:"uuid"`
 	Firmware              string          `json:"firmware"`
+	SecureBoot            bool            `json:"secureBoot"`
 	PowerState            string          `json:"powerState"`
 	ConnectionState       string          `json:"connectionState"`
 	Snapshot              model.Ref       `json:"snapshot"`
@@ -210,14 +408,42 @@ type VM struct {
 	GuestName             string          `json:"guestName"`
 	BalloonedMemory       int32           `json:"balloonedMemory"`
 	IpAddress             string          `json:"ipAddress"`
+	HostName              string          `json:"hostName"`
+	ToolsRunningStatus    string          `json:"toolsRunningStatus"`
 	StorageUsed           int64           `json:"storageUsed"`
 	NumaNodeAffinity      []string        `json:"numaNodeAffinity"`
 	Devices               []model.Device  `json:"devices"`
 	Networks              []model.Ref     `json:"networks"`
-	Disks                 []model.Disk    `json:"disks"`
+	GuestNetworks         []GuestNetwork  `json:"guestNetworks"`
+	Disks                 []Disk          `json:"disks"`
 	Concerns              []model.Concern `json:"concerns"`
 }
 
+type GuestNetwork = model.GuestNetwork
+
+//
+// VM disk.
+// The datastore name is resolved for display.
+type Disk struct {
+	model.Disk
+	// Datastore name.
+	DatastoreName string `json:"datastoreName"`
+}
+
+//
+// VM summary (not detailed) resource.
+// Includes the fields which affect the migration strategy
+// and bootability on the target.
+type VMSummary struct {
+	Resource
+	Firmware              string `json:"firmware"`
+	SecureBoot            bool   `json:"secureBoot"`
+	ChangeTrackingEnabled bool   `json:"changeTrackingEnabled"`
+	CpuCount              int32  `json:"cpuCount"`
+	CoresPerSocket        int32  `json:"coresPerSocket"`
+	MemoryMB              int32  `json:"memoryMB"`
+}
+
 //
 // Build the resource using the model.
 func (r *VM) With(m *model.VM) {
@@ -228,6 +454,7 @@ func (r *VM) With(m *model.VM) {
 	r.RevisionValidated = m.RevisionValidated
 	r.UUID = m.UUID
 	r.Firmware = m.Firmware
+	r.SecureBoot = m.SecureBoot
 	r.PowerState = m.PowerState
 	r.ConnectionState = m.ConnectionState
 	r.Snapshot = m.Snapshot
@@ -243,12 +470,21 @@ func (r *VM) With(m *model.VM) {
 	r.GuestName = m.GuestName
 	r.BalloonedMemory = m.BalloonedMemory
 	r.IpAddress = m.IpAddress
+	r.HostName = m.HostName
+	r.ToolsRunningStatus = m.ToolsRunningStatus
 	r.StorageUsed = m.StorageUsed
 	r.FaultToleranceEnabled = m.FaultToleranceEnabled
 	r.Devices = m.Devices
 	r.NumaNodeAffinity = m.NumaNodeAffinity
 	r.Networks = m.Networks
-	r.Disks = m.Disks
+	r.GuestNetworks = m.GuestNetworks
+	if r.GuestNetworks == nil {
+		r.GuestNetworks = []GuestNetwork{}
+	}
+	r.Disks = []Disk{}
+	for _, disk := range m.Disks {
+		r.Disks = append(r.Disks, Disk{Disk: disk})
+	}
 	r.Concerns = m.Concerns
 }
 
@@ -267,8 +503,55 @@ func (r *VM) Link(p *api.Provider) {
 // As content.
 func (r *VM) Content(detail bool) interface{} {
 	if !detail {
-		return r.Resource
+		return VMSummary{
+			Resource:              r.Resource,
+			Firmware:              r.Firmware,
+			SecureBoot:            r.SecureBoot,
+			ChangeTrackingEnabled: r.ChangeTrackingEnabled,
+			CpuCount:              r.CpuCount,
+			CoresPerSocket:        r.CoresPerSocket,
+			MemoryMB:              r.MemoryMB,
+		}
 	}
 
 	return r
 }
+
+//
+// Build (resolve) the VM disk datastore names.
+type DiskBuilder struct {
+	db libmodel.DB
+	// Datastore names cached by ID.
+	datastores map[string]string
+}
+
+//
+// Resolve the datastore names.
+// Datastores not found in the inventory are not named.
+func (r *DiskBuilder) build(vm *VM) (err error) {
+	for i := range vm.Disks {
+		disk := &vm.Disks[i]
+		id := disk.Datastore.ID
+		if name, found := r.datastores[id]; found {
+			disk.DatastoreName = name
+			continue
+		}
+		ds := &model.Datastore{
+			Base: model.Base{
+				ID: id,
+			},
+		}
+		err = r.db.Get(ds)
+		if err != nil {
+			if errors.Is(err, model.NotFound) {
+				err = nil
+				continue
+			}
+			return
+		}
+		r.datastores[id] = ds.Name
+		disk.DatastoreName = ds.Name
+	}
+
+	return
+}
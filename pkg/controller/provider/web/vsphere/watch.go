@@ -0,0 +1,176 @@
+package vsphere
+
+import (
+	"github.com/gin-gonic/gin"
+	libmodel "github.com/konveyor/controller/pkg/inventory/model"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"net/http"
+)
+
+//
+// Routes.
+const (
+	WatchRoot = ProviderRoot + "/watch"
+)
+
+//
+// Multiplexed watch handler.
+type WatchHandler struct {
+	Handler
+}
+
+//
+// Add routes to the `gin` router.
+func (h *WatchHandler) AddRoutes(e *gin.Engine) {
+	e.GET(WatchRoot, h.Watch)
+}
+
+//
+// List resources in a REST collection.
+func (h WatchHandler) List(ctx *gin.Context) {
+}
+
+//
+// Get a specific REST resource.
+func (h WatchHandler) Get(ctx *gin.Context) {
+}
+
+//
+// Watch multiple collections on one websocket.
+// The `collections` parameter lists the collections (for
+// example: collections=clusters,hosts,networks,vms). Each
+// event is tagged with the collection. The `name` filter is
+// applied to each collection and the `folder` filter is applied
+// to the VM collection (consistent with the collection watch).
+func (h WatchHandler) Watch(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	names := h.Collections(ctx)
+	if len(names) == 0 {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	db := h.Collector.DB()
+	folder, status := VMHandler{Handler: h.Handler}.folderPredicate(ctx, db)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	filter := h.WatchPredicate(ctx, db)
+	collections := []base.WatchedCollection{}
+	for _, name := range names {
+		collection, found := h.collection(db, name)
+		if !found {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+		collection.Filter = filter
+		if name == VMCollection {
+			collection.Filter = h.WatchPredicate(ctx, db, folder)
+		}
+		collections = append(collections, collection)
+	}
+	err := h.MultiWatch(ctx, db, collections)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+	}
+}
+
+//
+// Build the watched collection by name.
+// The resources are built the same as the collection watch.
+func (h WatchHandler) collection(db libmodel.DB, name string) (collection base.WatchedCollection, found bool) {
+	found = true
+	collection.Kind = name
+	switch name {
+	case FolderCollection:
+		collection.Model = &model.Folder{}
+		collection.Builder = func(in libmodel.Model) (r interface{}) {
+			m := in.(*model.Folder)
+			folder := &Folder{}
+			folder.With(m)
+			folder.Link(h.Provider)
+			folder.Path, _ = m.Path(db)
+			r = folder
+			return
+		}
+	case DatacenterCollection:
+		collection.Model = &model.Datacenter{}
+		collection.Builder = func(in libmodel.Model) (r interface{}) {
+			m := in.(*model.Datacenter)
+			dc := &Datacenter{}
+			dc.With(m)
+			dc.Link(h.Provider)
+			dc.Path, _ = m.Path(db)
+			r = dc
+			return
+		}
+	case ClusterCollection:
+		collection.Model = &model.Cluster{}
+		collection.Builder = func(in libmodel.Model) (r interface{}) {
+			m := in.(*model.Cluster)
+			cluster := &Cluster{}
+			cluster.With(m)
+			cluster.Link(h.Provider)
+			cluster.Path, _ = m.Path(db)
+			r = cluster
+			return
+		}
+	case HostCollection:
+		collection.Model = &model.Host{}
+		collection.Builder = func(in libmodel.Model) (r interface{}) {
+			m := in.(*model.Host)
+			host := &Host{}
+			host.With(m)
+			host.Link(h.Provider)
+			host.Path, _ = m.Path(db)
+			r = host
+			return
+		}
+	case NetworkCollection:
+		collection.Model = &model.Network{}
+		collection.Builder = func(in libmodel.Model) (r interface{}) {
+			m := in.(*model.Network)
+			network := &Network{}
+			network.With(m)
+			network.Link(h.Provider)
+			network.Path, _ = m.Path(db)
+			r = network
+			return
+		}
+	case DatastoreCollection:
+		collection.Model = &model.Datastore{}
+		collection.Builder = func(in libmodel.Model) (r interface{}) {
+			m := in.(*model.Datastore)
+			ds := &Datastore{}
+			ds.With(m)
+			ds.Link(h.Provider)
+			ds.Path, _ = m.Path(db)
+			r = ds
+			return
+		}
+	case VMCollection:
+		collection.Model = &model.VM{}
+		collection.Builder = func(in libmodel.Model) (r interface{}) {
+			m := in.(*model.VM)
+			vm := &VM{}
+			vm.With(m)
+			vm.Link(h.Provider)
+			vm.Path, _ = m.Path(db)
+			r = vm
+			return
+		}
+	default:
+		found = false
+	}
+
+	return
+}
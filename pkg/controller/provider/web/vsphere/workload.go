@@ -76,7 +76,7 @@ func (h WorkloadHandler) Get(ctx *gin.Context) {
 	r.Link(h.Provider)
 	content := r
 
-	ctx.JSON(http.StatusOK, content)
+	h.Render(ctx, content)
 }
 
 //
@@ -0,0 +1,132 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+	"time"
+)
+
+//
+// Routes.
+const (
+	TimeoutParam = "timeout"
+	WaitRoot     = PlanRoot + "/wait"
+)
+
+//
+// Long-poll (wait) timeouts.
+const (
+	// Default (seconds).
+	DefaultWaitTimeout = 30
+	// Maximum (seconds).
+	MaxWaitTimeout = 300
+	// Interval between reads of the plan.
+	WaitInterval = time.Second * 2
+)
+
+//
+// Wait (long-poll) for the plan execution to complete.
+// The request is held open until the plan reaches a terminal
+// state (Succeeded|Failed|Canceled) or the `timeout` (seconds)
+// expires. On timeout, the current (non-terminal) status is
+// returned so the client can re-issue the request. The plan is
+// read through the (cached) client so waiting does not add load
+// on the API server.
+func (h PlanHandler) Wait(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	timeout, status := h.waitTimeout(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	expired := time.After(timeout)
+	ticker := time.NewTicker(WaitInterval)
+	defer ticker.Stop()
+	for {
+		p := &api.Plan{}
+		status = h.get(
+			ctx,
+			client.ObjectKey{
+				Namespace: ctx.Param(base.NsParam),
+				Name:      ctx.Param(PlanParam),
+			},
+			p)
+		if status != http.StatusOK {
+			ctx.Status(status)
+			return
+		}
+		r := PlanCompletion{}
+		r.With(p)
+		if r.Completed {
+			ctx.JSON(http.StatusOK, r)
+			return
+		}
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-expired:
+			ctx.JSON(http.StatusOK, r)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+//
+// Build the wait timeout.
+// Capped at MaxWaitTimeout.
+func (h PlanHandler) waitTimeout(ctx *gin.Context) (timeout time.Duration, status int) {
+	status = http.StatusOK
+	seconds := int64(DefaultWaitTimeout)
+	if s := ctx.Request.URL.Query().Get(TimeoutParam); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < 0 {
+			status = http.StatusBadRequest
+			return
+		}
+		seconds = n
+	}
+	if seconds > MaxWaitTimeout {
+		seconds = MaxWaitTimeout
+	}
+	timeout = time.Duration(seconds) * time.Second
+
+	return
+}
+
+//
+// Plan completion.
+type PlanCompletion struct {
+	PlanSummary `json:",inline"`
+	// The plan execution has reached a terminal state.
+	Completed bool `json:"completed"`
+	// Terminal outcome: Succeeded|Failed|Canceled.
+	Outcome string `json:"outcome,omitempty"`
+}
+
+//
+// Build the completion.
+// Derived from the conditions of the active snapshot.
+func (r *PlanCompletion) With(p *api.Plan) {
+	r.PlanSummary.With(p)
+	migration := p.Status.Migration
+	if len(migration.History) == 0 || !migration.MarkedCompleted() {
+		return
+	}
+	snapshot := migration.ActiveSnapshot()
+	for _, cndType := range []string{Succeeded, Failed, Canceled} {
+		if snapshot.HasCondition(cndType) {
+			r.Completed = true
+			r.Outcome = cndType
+			break
+		}
+	}
+}
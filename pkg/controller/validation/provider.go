@@ -148,7 +148,7 @@ func (r *ProviderPair) validateSource(pair provider.Pair) (result libcnd.Conditi
 		}
 		result.SetCondition(newCnd)
 	}
-	// An openshift source is not supported.
+	// An openshift source is not supported by the importer (VMIO).
 	r.Referenced.Source = pv.Referenced
 	if r.Referenced.Source != nil && r.Referenced.Source.Type() == api.OpenShift {
 		result.SetCondition(libcnd.Condition{
@@ -156,7 +156,7 @@ func (r *ProviderPair) validateSource(pair provider.Pair) (result libcnd.Conditi
 			Status:   True,
 			Reason:   TypeNotValid,
 			Category: Critical,
-			Message:  "The provider is not valid.",
+			Message:  "The provider is not valid: import from OpenShift is not supported.",
 		})
 		return
 	}
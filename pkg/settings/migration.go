@@ -1,6 +1,10 @@
 package settings
 
-import liberr "github.com/konveyor/controller/pkg/error"
+import (
+	liberr "github.com/konveyor/controller/pkg/error"
+	"os"
+	"strings"
+)
 
 //
 // Environment variables.
@@ -8,17 +12,47 @@ const (
 	MaxVmInFlight = "MAX_VM_INFLIGHT"
 	HookDeadline  = "HOOK_DEADLINE"
 	HookRetry     = "HOOK_RETRY"
+	StepWorkers   = "STEP_WORKERS"
+	CancelBatch   = "CANCEL_BATCH"
+	// Max VMs in-flight across all plans.
+	MaxVmInFlightGlobal = "MAX_VM_INFLIGHT_GLOBAL"
+	// Seconds given to reconciles in progress on shutdown.
+	ShutdownTimeout = "SHUTDOWN_TIMEOUT"
+	// Comma-separated list of (additional) VM device
+	// kinds which cannot be migrated.
+	UnsupportedDevices = "UNSUPPORTED_DEVICES"
 )
 
+//
+// VM device kinds which cannot be migrated.
+var DefaultUnsupportedDevices = []string{
+	"VirtualPCIPassthrough",
+	"VirtualSCSIPassthrough",
+	"VirtualSriovEthernetCard",
+	"VirtualUSBController",
+	"VirtualSerialPort",
+}
+
 //
 // Migration settings
 type Migration struct {
 	// Max VMs in-flight.
 	MaxInFlight int
+	// Max VMs in-flight across all plans.
+	// Zero (default) is unlimited.
+	MaxInFlightGlobal int
 	// Hook fail/retry limit.
 	HookRetry int
 	// Hook completion deadline.
 	HookDeadline int
+	// Max workers stepping VMs (per plan).
+	StepWorkers int
+	// Max VMs (canceled or failed) cleaned up per reconcile.
+	CancelBatch int
+	// Seconds given to reconciles in progress on shutdown.
+	ShutdownTimeout int
+	// VM device kinds which cannot be migrated.
+	UnsupportedDevices []string
 }
 
 //
@@ -28,6 +62,10 @@ func (r *Migration) Load() (err error) {
 	if err != nil {
 		err = liberr.Wrap(err)
 	}
+	r.MaxInFlightGlobal, err = getEnvLimit(MaxVmInFlightGlobal, 0)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
 	r.HookRetry, err = getEnvLimit(HookRetry, 3)
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -36,6 +74,27 @@ func (r *Migration) Load() (err error) {
 	if err != nil {
 		err = liberr.Wrap(err)
 	}
+	r.StepWorkers, err = getEnvLimit(StepWorkers, 10)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	r.CancelBatch, err = getEnvLimit(CancelBatch, 20)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	r.ShutdownTimeout, err = getEnvLimit(ShutdownTimeout, 20)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	r.UnsupportedDevices = append([]string{}, DefaultUnsupportedDevices...)
+	if s, found := os.LookupEnv(UnsupportedDevices); found {
+		for _, kind := range strings.Split(s, ",") {
+			kind = strings.TrimSpace(kind)
+			if kind != "" {
+				r.UnsupportedDevices = append(r.UnsupportedDevices, kind)
+			}
+		}
+	}
 
 	return
 }